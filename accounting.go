@@ -0,0 +1,377 @@
+package radius
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// SessionUsage represents the cumulative usage of an accounting session.
+type SessionUsage struct {
+	SessionID     string
+	InputOctets   uint64
+	OutputOctets  uint64
+	InputPackets  uint32
+	OutputPackets uint32
+	Duration      time.Duration
+}
+
+// AggregateSession returns the usage of a session from a series of its
+// Accounting-Request records. Interim-Update and Stop records report
+// cumulative values, so the record with the highest Acct-Session-Time is
+// authoritative regardless of the order the records arrived in.
+func AggregateSession(records []*RADIUS) (SessionUsage, error) {
+	if len(records) == 0 {
+		return SessionUsage{}, fmt.Errorf("RADIUS no accounting records")
+	}
+
+	var latest *RADIUS
+	var latestTime uint32
+	var sessionID string
+	for i, r := range records {
+		if r == nil || r.Code != RADIUSCodeAccountingRequest {
+			return SessionUsage{}, fmt.Errorf("RADIUS record %d is not %s", i, RADIUSCodeAccountingRequest)
+		}
+		attr, ok := r.GetAttribute(RADIUSAttributeTypeAcctSessionId)
+		if !ok {
+			return SessionUsage{}, fmt.Errorf("RADIUS record %d has no %s", i, RADIUSAttributeTypeAcctSessionId)
+		}
+		if latest == nil {
+			sessionID = string(attr.Value)
+		} else if string(attr.Value) != sessionID {
+			return SessionUsage{}, fmt.Errorf("RADIUS record %d %s %q differs from %q", i, RADIUSAttributeTypeAcctSessionId, attr.Value, sessionID)
+		}
+		sessionTime, _ := r.uint32Attribute(RADIUSAttributeTypeAcctSessionTime)
+		if latest == nil || sessionTime >= latestTime {
+			latest, latestTime = r, sessionTime
+		}
+	}
+
+	usage := SessionUsage{
+		SessionID: sessionID,
+		Duration:  time.Duration(latestTime) * time.Second,
+	}
+	usage.InputOctets, _ = latest.counter64(RADIUSAttributeTypeAcctInputOctets, RADIUSAttributeTypeAcctInputGigawords)
+	usage.OutputOctets, _ = latest.counter64(RADIUSAttributeTypeAcctOutputOctets, RADIUSAttributeTypeAcctOutputGigawords)
+	usage.InputPackets, _ = latest.uint32Attribute(RADIUSAttributeTypeAcctInputPackets)
+	usage.OutputPackets, _ = latest.uint32Attribute(RADIUSAttributeTypeAcctOutputPackets)
+	return usage, nil
+}
+
+// counter64 combines a 32 bit octet counter with its Gigawords counterpart,
+// which is treated as zero when absent.
+func (radius *RADIUS) counter64(low, high RADIUSAttributeType) (uint64, bool) {
+	lo, ok := radius.uint32Attribute(low)
+	if !ok {
+		return 0, false
+	}
+	hi, _ := radius.uint32Attribute(high)
+	return uint64(hi)<<32 | uint64(lo), true
+}
+
+// SessionDuration returns the Acct-Session-Time attribute as a time.Duration.
+func (radius *RADIUS) SessionDuration() (time.Duration, bool) {
+	n, ok := radius.uint32Attribute(RADIUSAttributeTypeAcctSessionTime)
+	if !ok {
+		return 0, false
+	}
+	return time.Duration(n) * time.Second, true
+}
+
+// AcctSessionTime returns the Acct-Session-Time attribute as a
+// time.Duration, as SessionDuration does.
+func (radius *RADIUS) AcctSessionTime() (time.Duration, bool) {
+	return radius.SessionDuration()
+}
+
+// radiusMaxSessionTime is the longest Acct-Session-Time accepted by
+// SessionDurationSafe, 10 years. No NAS keeps a session for longer, so larger
+// values, such as 0xFFFFFFFF, are corrupt counters.
+const radiusMaxSessionTime = 10 * 365 * 24 * 60 * 60
+
+// SessionDurationSafe is like SessionDuration but returns false when the
+// Acct-Session-Time exceeds 10 years, which is implausible for any session
+// and is rather the sign of a corrupt or uninitialized counter.
+func (radius *RADIUS) SessionDurationSafe() (time.Duration, bool) {
+	d, ok := radius.SessionDuration()
+	if !ok || d > radiusMaxSessionTime*time.Second {
+		return 0, false
+	}
+	return d, true
+}
+
+// AcctInputBytes returns the 64 bit input octet counter, combining
+// Acct-Input-Octets with Acct-Input-Gigawords (treated as zero when absent).
+func (radius *RADIUS) AcctInputBytes() (uint64, bool) {
+	return radius.counter64(RADIUSAttributeTypeAcctInputOctets, RADIUSAttributeTypeAcctInputGigawords)
+}
+
+// AcctOutputBytes returns the 64 bit output octet counter, combining
+// Acct-Output-Octets with Acct-Output-Gigawords (treated as zero when absent).
+func (radius *RADIUS) AcctOutputBytes() (uint64, bool) {
+	return radius.counter64(RADIUSAttributeTypeAcctOutputOctets, RADIUSAttributeTypeAcctOutputGigawords)
+}
+
+// AcctTotalOctets returns the 64 bit input and output octet counters, as
+// AcctInputBytes and AcctOutputBytes do. It returns false unless both
+// Acct-Input-Octets and Acct-Output-Octets are present; the Gigawords
+// counters are treated as zero when absent.
+func (radius *RADIUS) AcctTotalOctets() (in, out uint64, ok bool) {
+	in, inOK := radius.AcctInputBytes()
+	out, outOK := radius.AcctOutputBytes()
+	if !inOK || !outOK {
+		return 0, 0, false
+	}
+	return in, out, true
+}
+
+// AccountingCounters represents the usage counters of an accounting record.
+type AccountingCounters struct {
+	InputBytes    uint64
+	OutputBytes   uint64
+	InputPackets  uint32
+	OutputPackets uint32
+	SessionTime   time.Duration
+}
+
+// accountingCounterTypes are the attributes read by AccountingCounters.
+var accountingCounterTypes = [...]RADIUSAttributeType{
+	RADIUSAttributeTypeAcctInputOctets,
+	RADIUSAttributeTypeAcctInputGigawords,
+	RADIUSAttributeTypeAcctOutputOctets,
+	RADIUSAttributeTypeAcctOutputGigawords,
+	RADIUSAttributeTypeAcctInputPackets,
+	RADIUSAttributeTypeAcctOutputPackets,
+	RADIUSAttributeTypeAcctSessionTime,
+}
+
+// AccountingCounters returns the usage counters of an Accounting-Request,
+// read in a single pass over the attributes. Octet counters are combined with
+// their Gigawords counterpart as AcctInputBytes and AcctOutputBytes do, and
+// absent counters are zero. It returns false for other packets.
+func (radius *RADIUS) AccountingCounters() (AccountingCounters, bool) {
+	if radius.Code != RADIUSCodeAccountingRequest {
+		return AccountingCounters{}, false
+	}
+
+	// first value of each counter, indexed as in accountingCounterTypes
+	var counters [len(accountingCounterTypes)]uint32
+	var seen [len(accountingCounterTypes)]bool
+	for _, v := range radius.Attributes {
+		for i, t := range accountingCounterTypes {
+			if v.Type != t || seen[i] {
+				continue
+			}
+			if n, err := v.Value.Uint32(); err == nil {
+				counters[i], seen[i] = n, true
+			}
+		}
+	}
+
+	c := AccountingCounters{
+		InputBytes:    uint64(counters[1])<<32 | uint64(counters[0]),
+		OutputBytes:   uint64(counters[3])<<32 | uint64(counters[2]),
+		InputPackets:  counters[4],
+		OutputPackets: counters[5],
+		SessionTime:   time.Duration(counters[6]) * time.Second,
+	}
+	return c, true
+}
+
+// EffectiveTimestamp estimates when the event of an accounting record
+// occurred on the NAS. Event-Timestamp already records that time (RFC2869
+// 5.3) and is returned as it is. Otherwise Acct-Delay-Time, the seconds the
+// NAS has been trying to send the record (RFC2866 5.2), is subtracted from
+// receivedAt. Without either, receivedAt is returned.
+func (radius *RADIUS) EffectiveTimestamp(receivedAt time.Time) time.Time {
+	if n, ok := radius.uint32Attribute(RADIUSAttributeTypeEventTimestamp); ok {
+		return time.Unix(int64(n), 0).UTC()
+	}
+	delay, _ := radius.uint32Attribute(RADIUSAttributeTypeAcctDelayTime)
+	return receivedAt.Add(-time.Duration(delay) * time.Second)
+}
+
+// AcctOctetDelta returns the input octets counted between two accounting
+// records of the same session. When either record carries
+// Acct-Input-Gigawords the 64 bit counters are compared, a missing
+// Acct-Input-Gigawords counting as 0 since NASes commonly omit it while it
+// is 0. Otherwise the 32 bit Acct-Input-Octets are assumed to have rolled
+// over at most once.
+func AcctOctetDelta(earlier, later *RADIUS) (int64, error) {
+	earlierKey, ok := earlier.SessionKey()
+	if !ok {
+		return 0, fmt.Errorf("RADIUS earlier record has no %s", RADIUSAttributeTypeAcctSessionId)
+	}
+	laterKey, ok := later.SessionKey()
+	if !ok {
+		return 0, fmt.Errorf("RADIUS later record has no %s", RADIUSAttributeTypeAcctSessionId)
+	}
+	if earlierKey != laterKey {
+		return 0, fmt.Errorf("RADIUS records are for different sessions %q and %q", earlierKey.SessionID, laterKey.SessionID)
+	}
+
+	earlierOctets, ok := earlier.AcctInputBytes()
+	if !ok {
+		return 0, fmt.Errorf("RADIUS earlier record has no %s", RADIUSAttributeTypeAcctInputOctets)
+	}
+	laterOctets, ok := later.AcctInputBytes()
+	if !ok {
+		return 0, fmt.Errorf("RADIUS later record has no %s", RADIUSAttributeTypeAcctInputOctets)
+	}
+
+	_, earlierGigawords := earlier.GetAttribute(RADIUSAttributeTypeAcctInputGigawords)
+	_, laterGigawords := later.GetAttribute(RADIUSAttributeTypeAcctInputGigawords)
+	if earlierGigawords || laterGigawords {
+		if laterOctets < earlierOctets {
+			return 0, fmt.Errorf("RADIUS %s decreased from %d to %d", RADIUSAttributeTypeAcctInputOctets, earlierOctets, laterOctets)
+		}
+		return int64(laterOctets - earlierOctets), nil
+	}
+	return int64(uint32(laterOctets) - uint32(earlierOctets)), nil
+}
+
+// interimUpdateReplacedAttributeTypes are the attributes of a base session
+// packet replaced by NewInterimUpdate.
+var interimUpdateReplacedAttributeTypes = map[RADIUSAttributeType]bool{
+	RADIUSAttributeTypeAcctStatusType:       true,
+	RADIUSAttributeTypeAcctInputOctets:      true,
+	RADIUSAttributeTypeAcctOutputOctets:     true,
+	RADIUSAttributeTypeAcctInputGigawords:   true,
+	RADIUSAttributeTypeAcctOutputGigawords:  true,
+	RADIUSAttributeTypeAcctInputPackets:     true,
+	RADIUSAttributeTypeAcctOutputPackets:    true,
+	RADIUSAttributeTypeAcctSessionTime:      true,
+	RADIUSAttributeTypeAcctDelayTime:        true,
+	RADIUSAttributeTypeEventTimestamp:       true,
+	RADIUSAttributeTypeAcctTerminateCause:   true,
+	RADIUSAttributeTypeMessageAuthenticator: true,
+}
+
+// NewInterimUpdate returns an Interim-Update Accounting-Request for the
+// session of radius, e.g. its Accounting Start. The session attributes are
+// kept and the cumulative counters are set from the arguments, encoding
+// values above 32 bits with Acct-Input-Gigawords and Acct-Output-Gigawords
+// (RFC2869 5.1, 5.2). The attributes that would be stale in the update, the
+// packet counters, Acct-Delay-Time and Event-Timestamp, are left out for the
+// caller to add. The Identifier is the one following radius; the
+// authenticator is left zeroed for the caller to compute.
+func (radius *RADIUS) NewInterimUpdate(inputOctets, outputOctets uint64, sessionTime uint32) *RADIUS {
+	b := NewRADIUS(RADIUSCodeAccountingRequest, radius.Identifier+1)
+	b.AddRaw(RADIUSAttributeTypeAcctStatusType, uint32Value(uint32(AcctStatusTypeInterimUpdate)))
+	for _, v := range radius.Attributes {
+		if !interimUpdateReplacedAttributeTypes[v.Type] {
+			b.AddRaw(v.Type, v.Value)
+		}
+	}
+	b.addCounter64(RADIUSAttributeTypeAcctInputOctets, RADIUSAttributeTypeAcctInputGigawords, inputOctets)
+	b.addCounter64(RADIUSAttributeTypeAcctOutputOctets, RADIUSAttributeTypeAcctOutputGigawords, outputOctets)
+	b.AddRaw(RADIUSAttributeTypeAcctSessionTime, uint32Value(sessionTime))
+	return b.Build()
+}
+
+// addCounter64 appends n as a 32 bit octet counter, followed by its Gigawords
+// counterpart when n does not fit in 32 bits.
+func (b *RADIUSBuilder) addCounter64(low, high RADIUSAttributeType, n uint64) {
+	b.AddRaw(low, uint32Value(uint32(n)))
+	if n>>32 != 0 {
+		b.AddRaw(high, uint32Value(uint32(n>>32)))
+	}
+}
+
+// acctSessionIDSequence numbers the session IDs of GenerateAcctSessionID.
+// It starts at a random value so that runs started within the same second
+// are unlikely to collide.
+var acctSessionIDSequence = func() uint32 {
+	var seed [4]byte
+	if _, err := rand.Read(seed[:]); err != nil {
+		return uint32(time.Now().UnixNano())
+	}
+	return binary.BigEndian.Uint32(seed[:])
+}()
+
+// GenerateAcctSessionID returns a new Acct-Session-Id for NAS simulators and
+// test harnesses, in the common "TTTTTTTT-SSSSSSSS" format of a hex Unix
+// timestamp followed by a hex sequence number. IDs are unique within a run
+// until the sequence wraps after 2^32 IDs.
+func GenerateAcctSessionID() string {
+	return fmt.Sprintf("%08X-%08X", uint32(time.Now().Unix()), atomic.AddUint32(&acctSessionIDSequence, 1))
+}
+
+// AccountingKind classifies an Accounting-Request by what it reports.
+type AccountingKind uint8
+
+// constants that define AccountingKind.
+const (
+	AccountingKindSessionStart  AccountingKind = iota // Start
+	AccountingKindSessionStop                         // Stop
+	AccountingKindInterimUpdate                       // Interim-Update
+	AccountingKindNASReboot                           // Accounting-On
+	AccountingKindNASShutdown                         // Accounting-Off
+	AccountingKindTunnel                              // Tunnel-* and Tunnel-Link-*
+	AccountingKindFailed                              // Failed
+)
+
+// String returns a string version of a AccountingKind.
+func (k AccountingKind) String() (s string) {
+	switch k {
+	case AccountingKindSessionStart:
+		s = "SessionStart"
+	case AccountingKindSessionStop:
+		s = "SessionStop"
+	case AccountingKindInterimUpdate:
+		s = "InterimUpdate"
+	case AccountingKindNASReboot:
+		s = "NASReboot"
+	case AccountingKindNASShutdown:
+		s = "NASShutdown"
+	case AccountingKindTunnel:
+		s = "Tunnel"
+	case AccountingKindFailed:
+		s = "Failed"
+	default:
+		s = fmt.Sprintf("Unknown(%d)", k)
+	}
+	return
+}
+
+// IsAccountingSystemEvent reports whether an Accounting-Request reports the
+// NAS starting or stopping accounting (Accounting-On or Accounting-Off),
+// which ends all sessions of the NAS rather than a single one.
+func (radius *RADIUS) IsAccountingSystemEvent() bool {
+	status, ok := radius.AcctStatusType()
+	return ok && (status == AcctStatusTypeAccountingOn || status == AcctStatusTypeAccountingOff)
+}
+
+// ClassifyAccounting returns the AccountingKind of an Accounting-Request
+// from its Acct-Status-Type.
+func (radius *RADIUS) ClassifyAccounting() (AccountingKind, error) {
+	if radius.Code != RADIUSCodeAccountingRequest {
+		return 0, fmt.Errorf("RADIUS %s is not %s", radius.Code, RADIUSCodeAccountingRequest)
+	}
+	status, ok := radius.AcctStatusType()
+	if !ok {
+		return 0, fmt.Errorf("RADIUS %s missing or invalid", RADIUSAttributeTypeAcctStatusType)
+	}
+
+	switch status {
+	case AcctStatusTypeStart:
+		return AccountingKindSessionStart, nil
+	case AcctStatusTypeStop:
+		return AccountingKindSessionStop, nil
+	case AcctStatusTypeInterimUpdate:
+		return AccountingKindInterimUpdate, nil
+	case AcctStatusTypeAccountingOn:
+		return AccountingKindNASReboot, nil
+	case AcctStatusTypeAccountingOff:
+		return AccountingKindNASShutdown, nil
+	case AcctStatusTypeTunnelStart, AcctStatusTypeTunnelStop, AcctStatusTypeTunnelReject,
+		AcctStatusTypeTunnelLinkStart, AcctStatusTypeTunnelLinkStop, AcctStatusTypeTunnelLinkReject:
+		return AccountingKindTunnel, nil
+	case AcctStatusTypeFailed:
+		return AccountingKindFailed, nil
+	default:
+		return 0, fmt.Errorf("RADIUS %s %s unknown", RADIUSAttributeTypeAcctStatusType, status)
+	}
+}
@@ -0,0 +1,391 @@
+package radius
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+func newTestAccountingRecord(sessionID string, sessionTime, inOctets, inGigawords, outOctets uint32) *RADIUS {
+	return &RADIUS{
+		Code: RADIUSCodeAccountingRequest,
+		Attributes: []RADIUSAttribute{
+			newAttribute(RADIUSAttributeTypeAcctSessionId, []byte(sessionID)),
+			newAttribute(RADIUSAttributeTypeAcctSessionTime, uint32Value(sessionTime)),
+			newAttribute(RADIUSAttributeTypeAcctInputOctets, uint32Value(inOctets)),
+			newAttribute(RADIUSAttributeTypeAcctInputGigawords, uint32Value(inGigawords)),
+			newAttribute(RADIUSAttributeTypeAcctOutputOctets, uint32Value(outOctets)),
+		},
+	}
+}
+
+func TestAggregateSession(t *testing.T) {
+	records := []*RADIUS{
+		newTestAccountingRecord("0001", 60, 1000, 0, 2000),
+		newTestAccountingRecord("0001", 300, 5000, 1, 9000),
+		// arrives late, must not override the later record
+		newTestAccountingRecord("0001", 120, 3000, 0, 4000),
+	}
+
+	usage, err := AggregateSession(records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := SessionUsage{
+		SessionID:    "0001",
+		InputOctets:  1<<32 + 5000,
+		OutputOctets: 9000,
+		Duration:     300 * time.Second,
+	}
+	if usage != want {
+		t.Errorf("got %+v want %+v", usage, want)
+	}
+
+	records = append(records, newTestAccountingRecord("0002", 600, 0, 0, 0))
+	if _, err := AggregateSession(records); err == nil {
+		t.Error("expected error for records of different sessions")
+	}
+	if _, err := AggregateSession(nil); err == nil {
+		t.Error("expected error for no records")
+	}
+	if _, err := AggregateSession([]*RADIUS{{Code: RADIUSCodeAccessRequest}}); err == nil {
+		t.Error("expected error for non accounting record")
+	}
+}
+
+func TestRADIUSSessionDuration(t *testing.T) {
+	radius := newTestAccountingRecord("0001", 3725, 0, 0, 0)
+	d, ok := radius.SessionDuration()
+	if !ok || d != time.Hour+2*time.Minute+5*time.Second {
+		t.Errorf("got %v, %v want %v, true", d, ok, time.Hour+2*time.Minute+5*time.Second)
+	}
+
+	if _, ok := (&RADIUS{Code: RADIUSCodeAccountingRequest}).SessionDuration(); ok {
+		t.Error("expected no duration without Acct-Session-Time")
+	}
+}
+
+func TestRADIUSSessionDurationSafe(t *testing.T) {
+	tests := []struct {
+		desc        string
+		sessionTime uint32
+		want        time.Duration
+		wantOK      bool
+	}{
+		{desc: "zero", sessionTime: 0, want: 0, wantOK: true},
+		{desc: "one day", sessionTime: 86400, want: 24 * time.Hour, wantOK: true},
+		{desc: "threshold", sessionTime: radiusMaxSessionTime, want: radiusMaxSessionTime * time.Second, wantOK: true},
+		{desc: "above threshold", sessionTime: radiusMaxSessionTime + 1},
+		{desc: "all ones", sessionTime: 0xffffffff},
+	}
+
+	for _, tt := range tests {
+		radius := newTestAccountingRecord("0001", tt.sessionTime, 0, 0, 0)
+		d, ok := radius.SessionDurationSafe()
+		if d != tt.want || ok != tt.wantOK {
+			t.Errorf("%s: got %v, %v want %v, %v", tt.desc, d, ok, tt.want, tt.wantOK)
+		}
+	}
+
+	if _, ok := (&RADIUS{Code: RADIUSCodeAccountingRequest}).SessionDurationSafe(); ok {
+		t.Error("expected no duration without Acct-Session-Time")
+	}
+}
+
+func TestRADIUSAcctBytes(t *testing.T) {
+	// 10 GiB + 1234 bytes downloaded, 2000 bytes uploaded without gigawords
+	radius := &RADIUS{
+		Code: RADIUSCodeAccountingRequest,
+		Attributes: []RADIUSAttribute{
+			newAttribute(RADIUSAttributeTypeAcctInputOctets, uint32Value(2000)),
+			newAttribute(RADIUSAttributeTypeAcctOutputOctets, uint32Value(2<<30+1234)),
+			newAttribute(RADIUSAttributeTypeAcctOutputGigawords, uint32Value(2)),
+		},
+	}
+
+	if n, ok := radius.AcctOutputBytes(); n != 10<<30+1234 || !ok {
+		t.Errorf("AcctOutputBytes() got %d, %v want %d, true", n, ok, uint64(10<<30+1234))
+	}
+	if n, ok := radius.AcctInputBytes(); n != 2000 || !ok {
+		t.Errorf("AcctInputBytes() got %d, %v want 2000, true", n, ok)
+	}
+	if _, ok := (&RADIUS{}).AcctOutputBytes(); ok {
+		t.Error("expected no output counter")
+	}
+}
+
+func TestRADIUSAcctTotalOctets(t *testing.T) {
+	data, err := NewRADIUS(RADIUSCodeAccountingRequest, 1).
+		AddUint32(RADIUSAttributeTypeAcctStatusType, uint32(AcctStatusTypeInterimUpdate)).
+		AddUint32(RADIUSAttributeTypeAcctSessionTime, 5400).
+		AddUint32(RADIUSAttributeTypeAcctInputOctets, 5000).
+		AddUint32(RADIUSAttributeTypeAcctOutputOctets, 9000).
+		AddUint32(RADIUSAttributeTypeAcctInputGigawords, 2).
+		AddUint32(RADIUSAttributeTypeAcctOutputGigawords, 3).
+		Build().serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	radius := decodeTestRADIUS(t, data)
+
+	if d, ok := radius.AcctSessionTime(); !ok || d != 90*time.Minute {
+		t.Errorf("AcctSessionTime got %v, %v want 1h30m0s, true", d, ok)
+	}
+	if in, out, ok := radius.AcctTotalOctets(); !ok || in != 2<<32+5000 || out != 3<<32+9000 {
+		t.Errorf("AcctTotalOctets got %d, %d, %v want %d, %d, true", in, out, ok, uint64(2<<32+5000), uint64(3<<32+9000))
+	}
+
+	// missing Gigawords are zero, missing octet counters are not
+	radius.RemoveAttribute(RADIUSAttributeTypeAcctOutputGigawords)
+	if in, out, ok := radius.AcctTotalOctets(); !ok || in != 2<<32+5000 || out != 9000 {
+		t.Errorf("without Acct-Output-Gigawords got %d, %d, %v", in, out, ok)
+	}
+	radius.RemoveAttribute(RADIUSAttributeTypeAcctInputOctets)
+	if _, _, ok := radius.AcctTotalOctets(); ok {
+		t.Error("without Acct-Input-Octets: expected false")
+	}
+	if _, ok := (&RADIUS{}).AcctSessionTime(); ok {
+		t.Error("AcctSessionTime: expected false")
+	}
+}
+
+func TestRADIUSAccountingCounters(t *testing.T) {
+	radius := NewRADIUS(RADIUSCodeAccountingRequest, 1).
+		AddUint32(RADIUSAttributeTypeAcctStatusType, uint32(AcctStatusTypeStop)).
+		AddUint32(RADIUSAttributeTypeAcctSessionTime, 3600).
+		AddUint32(RADIUSAttributeTypeAcctInputOctets, 5000).
+		AddUint32(RADIUSAttributeTypeAcctInputGigawords, 2).
+		AddUint32(RADIUSAttributeTypeAcctOutputOctets, 9000).
+		AddUint32(RADIUSAttributeTypeAcctInputPackets, 40).
+		AddUint32(RADIUSAttributeTypeAcctOutputPackets, 60).
+		Build()
+
+	want := AccountingCounters{
+		InputBytes:    2<<32 + 5000,
+		OutputBytes:   9000,
+		InputPackets:  40,
+		OutputPackets: 60,
+		SessionTime:   time.Hour,
+	}
+	if got, ok := radius.AccountingCounters(); got != want || !ok {
+		t.Errorf("got %+v, %v want %+v, true", got, ok, want)
+	}
+
+	if got, ok := (&RADIUS{Code: RADIUSCodeAccountingRequest}).AccountingCounters(); got != (AccountingCounters{}) || !ok {
+		t.Errorf("no counters: got %+v, %v", got, ok)
+	}
+	if _, ok := decodeTestRADIUS(t, testRADIUSAccessRequest).AccountingCounters(); ok {
+		t.Error("Access-Request: expected not ok")
+	}
+}
+
+func TestRADIUSEffectiveTimestamp(t *testing.T) {
+	receivedAt := time.Date(2020, 9, 1, 12, 0, 0, 0, time.UTC)
+	eventAt := time.Date(2020, 9, 1, 11, 58, 0, 0, time.UTC)
+
+	tests := []struct {
+		desc       string
+		attributes []RADIUSAttribute
+		want       time.Time
+	}{
+		{"None", nil, receivedAt},
+		{
+			"Acct-Delay-Time",
+			[]RADIUSAttribute{newAttribute(RADIUSAttributeTypeAcctDelayTime, uint32Value(30))},
+			receivedAt.Add(-30 * time.Second),
+		},
+		{
+			"Event-Timestamp",
+			[]RADIUSAttribute{
+				newAttribute(RADIUSAttributeTypeAcctDelayTime, uint32Value(30)),
+				newAttribute(RADIUSAttributeTypeEventTimestamp, uint32Value(uint32(eventAt.Unix()))),
+			},
+			eventAt,
+		},
+	}
+
+	for _, tt := range tests {
+		radius := &RADIUS{Code: RADIUSCodeAccountingRequest, Attributes: tt.attributes}
+		if got := radius.EffectiveTimestamp(receivedAt); !got.Equal(tt.want) {
+			t.Errorf("%s: got %v want %v", tt.desc, got, tt.want)
+		}
+	}
+}
+
+func TestAcctOctetDelta(t *testing.T) {
+	tests := []struct {
+		desc           string
+		earlier, later *RADIUS
+		want           int64
+		wantErr        bool
+	}{
+		{
+			desc:    "Gigawords",
+			earlier: newTestAccountingRecord("0001", 60, 0xfffff000, 1, 0),
+			later:   newTestAccountingRecord("0001", 120, 0x00001000, 3, 0),
+			want:    2<<32 - 0xfffff000 + 0x1000,
+		},
+		{
+			desc:    "GigawordsOnlyLater",
+			earlier: &RADIUS{Attributes: []RADIUSAttribute{newAttribute(RADIUSAttributeTypeAcctSessionId, []byte("0001")), newAttribute(RADIUSAttributeTypeAcctInputOctets, uint32Value(100))}},
+			later:   newTestAccountingRecord("0001", 120, 200, 1, 0),
+			want:    1<<32 + 100,
+		},
+		{
+			desc:    "Rollover",
+			earlier: &RADIUS{Attributes: []RADIUSAttribute{newAttribute(RADIUSAttributeTypeAcctSessionId, []byte("0001")), newAttribute(RADIUSAttributeTypeAcctInputOctets, uint32Value(0xfffff000))}},
+			later:   &RADIUS{Attributes: []RADIUSAttribute{newAttribute(RADIUSAttributeTypeAcctSessionId, []byte("0001")), newAttribute(RADIUSAttributeTypeAcctInputOctets, uint32Value(0x00001000))}},
+			want:    0x2000,
+		},
+		{
+			desc:    "NoRollover",
+			earlier: &RADIUS{Attributes: []RADIUSAttribute{newAttribute(RADIUSAttributeTypeAcctSessionId, []byte("0001")), newAttribute(RADIUSAttributeTypeAcctInputOctets, uint32Value(1000))}},
+			later:   &RADIUS{Attributes: []RADIUSAttribute{newAttribute(RADIUSAttributeTypeAcctSessionId, []byte("0001")), newAttribute(RADIUSAttributeTypeAcctInputOctets, uint32Value(5000))}},
+			want:    4000,
+		},
+		{
+			desc:    "Decreased",
+			earlier: newTestAccountingRecord("0001", 120, 0, 3, 0),
+			later:   newTestAccountingRecord("0001", 60, 0, 1, 0),
+			wantErr: true,
+		},
+		{
+			desc:    "DifferentSessions",
+			earlier: newTestAccountingRecord("0001", 60, 1000, 0, 0),
+			later:   newTestAccountingRecord("0002", 120, 5000, 0, 0),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := AcctOctetDelta(tt.earlier, tt.later)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error", tt.desc)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: got %d, %v want %d", tt.desc, got, err, tt.want)
+		}
+	}
+}
+
+func TestRADIUSNewInterimUpdate(t *testing.T) {
+	start := NewRADIUS(RADIUSCodeAccountingRequest, 7).
+		AddRaw(RADIUSAttributeTypeAcctStatusType, uint32Value(uint32(AcctStatusTypeStart))).
+		AddRaw(RADIUSAttributeTypeAcctSessionId, []byte("0001")).
+		AddRaw(RADIUSAttributeTypeNASIdentifier, []byte("nas01")).
+		AddRaw(RADIUSAttributeTypeAcctDelayTime, uint32Value(5)).
+		AddRaw(RADIUSAttributeTypeEventTimestamp, uint32Value(1600000000)).
+		AddRaw(RADIUSAttributeTypeAcctInputPackets, uint32Value(10)).
+		AddRaw(RADIUSAttributeTypeAcctOutputPackets, uint32Value(20)).
+		Build()
+
+	tests := []struct {
+		desc                      string
+		inputOctets, outputOctets uint64
+		wantGigawords             bool
+	}{
+		{"32bit", 1000, 2000, false},
+		{"64bit", 5<<32 + 1000, 1<<32 + 2000, true},
+	}
+
+	for _, tt := range tests {
+		data, err := start.NewInterimUpdate(tt.inputOctets, tt.outputOctets, 300).serialize()
+		if err != nil {
+			t.Fatalf("%s: serialize: %v", tt.desc, err)
+		}
+		update := decodeTestRADIUS(t, data)
+
+		if update.Code != RADIUSCodeAccountingRequest || update.Identifier != 8 {
+			t.Errorf("%s: got %s %d want %s 8", tt.desc, update.Code, update.Identifier, RADIUSCodeAccountingRequest)
+		}
+		if status, ok := update.AcctStatusType(); !ok || status != AcctStatusTypeInterimUpdate {
+			t.Errorf("%s: Acct-Status-Type got %s, %v want %s", tt.desc, status, ok, AcctStatusTypeInterimUpdate)
+		}
+		if key, ok := update.SessionKey(); !ok || key != (SessionKey{NAS: "nas01", SessionID: "0001"}) {
+			t.Errorf("%s: SessionKey got %+v, %v", tt.desc, key, ok)
+		}
+		if in, ok := update.AcctInputBytes(); !ok || in != tt.inputOctets {
+			t.Errorf("%s: AcctInputBytes got %d, %v want %d", tt.desc, in, ok, tt.inputOctets)
+		}
+		if out, ok := update.AcctOutputBytes(); !ok || out != tt.outputOctets {
+			t.Errorf("%s: AcctOutputBytes got %d, %v want %d", tt.desc, out, ok, tt.outputOctets)
+		}
+		if _, ok := update.GetAttribute(RADIUSAttributeTypeAcctInputGigawords); ok != tt.wantGigawords {
+			t.Errorf("%s: Acct-Input-Gigawords present got %v want %v", tt.desc, ok, tt.wantGigawords)
+		}
+		if d, ok := update.SessionDuration(); !ok || d != 300*time.Second {
+			t.Errorf("%s: SessionDuration got %s, %v", tt.desc, d, ok)
+		}
+		for _, stale := range []RADIUSAttributeType{
+			RADIUSAttributeTypeAcctDelayTime,
+			RADIUSAttributeTypeEventTimestamp,
+			RADIUSAttributeTypeAcctInputPackets,
+			RADIUSAttributeTypeAcctOutputPackets,
+		} {
+			if _, ok := update.GetAttribute(stale); ok {
+				t.Errorf("%s: %s must not be kept", tt.desc, stale)
+			}
+		}
+	}
+}
+
+func TestGenerateAcctSessionID(t *testing.T) {
+	format := regexp.MustCompile(`^[0-9A-F]{8}-[0-9A-F]{8}$`)
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		id := GenerateAcctSessionID()
+		if !format.MatchString(id) {
+			t.Fatalf("%q does not match %s", id, format)
+		}
+		if seen[id] {
+			t.Fatalf("%q generated twice", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestRADIUSClassifyAccounting(t *testing.T) {
+	request := func(status AcctStatusType) *RADIUS {
+		return NewRADIUS(RADIUSCodeAccountingRequest, 1).
+			AddRaw(RADIUSAttributeTypeAcctStatusType, uint32Value(uint32(status))).
+			Build()
+	}
+
+	tests := []struct {
+		desc            string
+		radius          *RADIUS
+		want            AccountingKind
+		wantSystemEvent bool
+		wantErr         bool
+	}{
+		{"Start", request(AcctStatusTypeStart), AccountingKindSessionStart, false, false},
+		{"Stop", request(AcctStatusTypeStop), AccountingKindSessionStop, false, false},
+		{"InterimUpdate", request(AcctStatusTypeInterimUpdate), AccountingKindInterimUpdate, false, false},
+		{"AccountingOn", request(AcctStatusTypeAccountingOn), AccountingKindNASReboot, true, false},
+		{"AccountingOff", request(AcctStatusTypeAccountingOff), AccountingKindNASShutdown, true, false},
+		{"TunnelLinkStop", request(AcctStatusTypeTunnelLinkStop), AccountingKindTunnel, false, false},
+		{"Failed", request(AcctStatusTypeFailed), AccountingKindFailed, false, false},
+		{"Unknown", request(AcctStatusType(4)), 0, false, true},
+		{"Missing", NewRADIUS(RADIUSCodeAccountingRequest, 1).Build(), 0, false, true},
+		{"AccessRequest", decodeTestRADIUS(t, testRADIUSAccessRequest), 0, false, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.radius.IsAccountingSystemEvent(); got != tt.wantSystemEvent {
+			t.Errorf("%s: IsAccountingSystemEvent got %v want %v", tt.desc, got, tt.wantSystemEvent)
+		}
+		got, err := tt.radius.ClassifyAccounting()
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error", tt.desc)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("%s: got %s, %v want %s", tt.desc, got, err, tt.want)
+		}
+	}
+}
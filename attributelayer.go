@@ -0,0 +1,82 @@
+package radius
+
+import (
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+// LayerTypeRADIUSAttribute is registered by init, as its decoder refers to
+// it.
+var LayerTypeRADIUSAttribute gopacket.LayerType
+
+// RADIUSAttributeLayer represents an attribute as a gopacket layer, added
+// after the RADIUS layer by DecodeOptions.AttributeLayers. Its Contents are
+// the Type, Length and Value of the attribute, and its Payload the following
+// attributes, or the Payload of the RADIUS layer for the last attribute.
+type RADIUSAttributeLayer struct {
+	layers.BaseLayer
+	RADIUSAttribute
+
+	next gopacket.LayerType
+}
+
+// LayerType returns LayerTypeRADIUSAttribute.
+func (a *RADIUSAttributeLayer) LayerType() gopacket.LayerType {
+	return LayerTypeRADIUSAttribute
+}
+
+// CanDecode returns the set of layer types that this DecodingLayer can decode.
+func (a *RADIUSAttributeLayer) CanDecode() gopacket.LayerClass {
+	return LayerTypeRADIUSAttribute
+}
+
+// NextLayerType returns the layer type contained by this DecodingLayer:
+// LayerTypeRADIUSAttribute for the following attribute, or for the last
+// attribute of a packet the NextLayerType of its RADIUS layer.
+func (a *RADIUSAttributeLayer) NextLayerType() gopacket.LayerType {
+	return a.next
+}
+
+// DecodeFromBytes decodes the attribute at the start of data, the following
+// attributes being its Payload. The Value refers to data.
+func (a *RADIUSAttributeLayer) DecodeFromBytes(data []byte, df gopacket.DecodeFeedback) error {
+	attr, err := DecodeAttribute(data)
+	if err != nil {
+		if len(data) < 2 || int(data[1]) > len(data) {
+			df.SetTruncated()
+		}
+		return err
+	}
+	a.RADIUSAttribute = attr
+	a.BaseLayer = layers.BaseLayer{Contents: data[:attr.Length], Payload: data[attr.Length:]}
+	a.next = gopacket.LayerTypeZero
+	if len(a.BaseLayer.Payload) > 0 {
+		a.next = LayerTypeRADIUSAttribute
+	}
+	return nil
+}
+
+func decodeRADIUSAttributeLayer(data []byte, p gopacket.PacketBuilder) error {
+	a := &RADIUSAttributeLayer{}
+	if err := a.DecodeFromBytes(data, p); err != nil {
+		return err
+	}
+	p.AddLayer(a)
+	return p.NextDecoder(a.next)
+}
+
+// addAttributeLayers adds a RADIUSAttributeLayer for each attribute of a
+// decoded packet, chained to the NextLayerType of the packet.
+func (radius *RADIUS) addAttributeLayers(p gopacket.PacketBuilder) {
+	data := radius.Contents[radiusMinimumRecordSizeInBytes:]
+	for i, v := range radius.Attributes {
+		a := &RADIUSAttributeLayer{RADIUSAttribute: v, next: LayerTypeRADIUSAttribute}
+		a.BaseLayer = layers.BaseLayer{Contents: data[:v.Length], Payload: data[v.Length:]}
+		if i == len(radius.Attributes)-1 {
+			a.BaseLayer.Payload = radius.BaseLayer.Payload
+			a.next = radius.NextLayerType()
+		}
+		p.AddLayer(a)
+		data = data[v.Length:]
+	}
+}
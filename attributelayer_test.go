@@ -0,0 +1,94 @@
+package radius
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+func TestRADIUSAttributeLayers(t *testing.T) {
+	p := gopacket.NewPacket(testRADIUSAccessRequest, DecodeOptions{AttributeLayers: true}, gopacket.Default)
+	if err := p.ErrorLayer(); err != nil {
+		t.Fatal(err.Error())
+	}
+	radius := p.Layer(LayerTypeRADIUS).(*RADIUS)
+	if got, want := len(p.Layers()), 1+len(radius.Attributes); got != want {
+		t.Fatalf("got %d layers want %d", got, want)
+	}
+
+	pos := radiusMinimumRecordSizeInBytes
+	for i, l := range p.Layers()[1:] {
+		a, ok := l.(*RADIUSAttributeLayer)
+		if !ok {
+			t.Fatalf("layer %d: got %s want %s", i+1, l.LayerType(), LayerTypeRADIUSAttribute)
+		}
+		if a.Type != radius.Attributes[i].Type || !bytes.Equal(a.Value, radius.Attributes[i].Value) {
+			t.Errorf("layer %d: got %s want %s", i+1, a.RADIUSAttribute, radius.Attributes[i])
+		}
+		end := pos + int(a.Length)
+		if !bytes.Equal(a.LayerContents(), testRADIUSAccessRequest[pos:end]) || !bytes.Equal(a.LayerPayload(), testRADIUSAccessRequest[end:]) {
+			t.Errorf("layer %d: got contents %x payload %x", i+1, a.LayerContents(), a.LayerPayload())
+		}
+		pos = end
+	}
+
+	// the default options add no attribute layers
+	p = gopacket.NewPacket(testRADIUSAccessRequest, DecodeOptions{}, gopacket.Default)
+	if len(p.Layers()) != 1 || p.Layer(LayerTypeRADIUSAttribute) != nil {
+		t.Errorf("default options: got %d layers", len(p.Layers()))
+	}
+}
+
+func TestRADIUSAttributeLayersEAP(t *testing.T) {
+	eap := []byte{0x02, 0x01, 0x00, 0x05, 0x01}
+	data, err := NewRADIUS(RADIUSCodeAccessRequest, 0x01).
+		AddEAPMessage(eap).
+		AddRaw(RADIUSAttributeTypeMessageAuthenticator, make([]byte, 16)).
+		Build().serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := gopacket.NewPacket(data, DecodeOptions{AttributeLayers: true}, gopacket.Default)
+	var types []gopacket.LayerType
+	for _, l := range p.Layers() {
+		types = append(types, l.LayerType())
+	}
+	want := []gopacket.LayerType{LayerTypeRADIUS, LayerTypeRADIUSAttribute, LayerTypeRADIUSAttribute, layers.LayerTypeEAP}
+	if len(types) != len(want) {
+		t.Fatalf("got layers %v want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Errorf("layer %d: got %s want %s", i, types[i], want[i])
+		}
+	}
+}
+
+func TestRADIUSAttributeLayerDecodingLayerParser(t *testing.T) {
+	var a RADIUSAttributeLayer
+	parser := gopacket.NewDecodingLayerParser(LayerTypeRADIUSAttribute, &a)
+	decoded := make([]gopacket.LayerType, 0, 5)
+	if err := parser.DecodeLayers(testRADIUSAccessRequest[radiusMinimumRecordSizeInBytes:], &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 5 {
+		t.Errorf("got %d layers want 5", len(decoded))
+	}
+	if a.Type != RADIUSAttributeTypeMessageAuthenticator || a.NextLayerType() != gopacket.LayerTypeZero {
+		t.Errorf("got %s, next %s", a.RADIUSAttribute, a.NextLayerType())
+	}
+
+	if err := a.DecodeFromBytes(testRADIUSAccessRequest[radiusMinimumRecordSizeInBytes:], gopacket.NilDecodeFeedback); err != nil {
+		t.Fatal(err)
+	}
+	if a.Type != RADIUSAttributeTypeUserName || string(a.Value) != "Admin" || a.NextLayerType() != LayerTypeRADIUSAttribute {
+		t.Errorf("got %s, next %s", a.RADIUSAttribute, a.NextLayerType())
+	}
+
+	if err := a.DecodeFromBytes([]byte{0x01, 0x07, 'A'}, gopacket.NilDecodeFeedback); err == nil {
+		t.Error("truncated attribute: expected error")
+	}
+}
@@ -0,0 +1,372 @@
+package radius
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"time"
+)
+
+// newAttribute returns a RADIUSAttribute of type t with its Length set from value.
+func newAttribute(t RADIUSAttributeType, value []byte) RADIUSAttribute {
+	return RADIUSAttribute{
+		Type:   t,
+		Length: RADIUSAttributeLength(len(value) + 2),
+		Value:  RADIUSAttributeValue(value),
+	}
+}
+
+// attributeIndex returns the index of the first attribute of type t, or -1.
+func (radius *RADIUS) attributeIndex(t RADIUSAttributeType) int {
+	for i, v := range radius.Attributes {
+		if v.Type == t {
+			return i
+		}
+	}
+	return -1
+}
+
+// GetAttribute returns the first attribute of type t.
+func (radius *RADIUS) GetAttribute(t RADIUSAttributeType) (RADIUSAttribute, bool) {
+	if i := radius.attributeIndex(t); i >= 0 {
+		return radius.Attributes[i], true
+	}
+	return RADIUSAttribute{}, false
+}
+
+// GetAttributes returns all attributes of type t in the order of the packet,
+// which is significant for attributes such as Proxy-State and EAP-Message.
+func (radius *RADIUS) GetAttributes(t RADIUSAttributeType) []RADIUSAttribute {
+	var attrs []RADIUSAttribute
+	for _, v := range radius.Attributes {
+		if v.Type == t {
+			attrs = append(attrs, v)
+		}
+	}
+	return attrs
+}
+
+// CountAttribute returns the number of attributes of type t.
+func (radius *RADIUS) CountAttribute(t RADIUSAttributeType) int {
+	n := 0
+	for _, v := range radius.Attributes {
+		if v.Type == t {
+			n++
+		}
+	}
+	return n
+}
+
+// FilterAttributes returns the attributes for which pred reports true, in the
+// order of the packet. The returned slice is new, so that it can be sorted or
+// modified without affecting the Attributes of the packet, but the values
+// still refer to the packet.
+func (radius *RADIUS) FilterAttributes(pred func(RADIUSAttribute) bool) []RADIUSAttribute {
+	var attrs []RADIUSAttribute
+	for _, v := range radius.Attributes {
+		if pred(v) {
+			attrs = append(attrs, v)
+		}
+	}
+	return attrs
+}
+
+// ConcatValues returns the values of the first run of consecutive attributes
+// of type t joined together, the reverse of AddLongString. It returns nil if
+// there is no attribute of type t.
+func (radius *RADIUS) ConcatValues(t RADIUSAttributeType) []byte {
+	i := radius.attributeIndex(t)
+	if i < 0 {
+		return nil
+	}
+	var value []byte
+	for _, v := range radius.Attributes[i:] {
+		if v.Type != t {
+			break
+		}
+		value = append(value, v.Value...)
+	}
+	return value
+}
+
+// RemoveAttribute deletes all attributes of type t, preserving the order of
+// the others, and returns how many were removed, e.g. for a proxy stripping
+// its Proxy-State from a reply. The Length of the packet is left as it is;
+// serialize with FixLengths to update it.
+func (radius *RADIUS) RemoveAttribute(t RADIUSAttributeType) int {
+	attrs := radius.Attributes[:0]
+	for _, v := range radius.Attributes {
+		if v.Type != t {
+			attrs = append(attrs, v)
+		}
+	}
+	n := len(radius.Attributes) - len(attrs)
+	radius.Attributes = attrs
+	return n
+}
+
+// HasAttribute reports whether the packet has an attribute of type t.
+func (radius *RADIUS) HasAttribute(t RADIUSAttributeType) bool {
+	return radius.attributeIndex(t) >= 0
+}
+
+// Uint32 decodes the value as a 32 bit unsigned integer.
+func (v RADIUSAttributeValue) Uint32() (uint32, error) {
+	if len(v) != 4 {
+		return 0, fmt.Errorf("RADIUS integer length %d invalid", len(v))
+	}
+	return binary.BigEndian.Uint32(v), nil
+}
+
+// IP decodes the value as an ipaddr (4 bytes) or ipv6addr (16 bytes).
+func (v RADIUSAttributeValue) IP() (net.IP, error) {
+	if len(v) != net.IPv4len && len(v) != net.IPv6len {
+		return nil, fmt.Errorf("RADIUS address length %d invalid", len(v))
+	}
+	ip := make(net.IP, len(v))
+	copy(ip, v)
+	return ip, nil
+}
+
+// Text decodes the value as text. RADIUS text is UTF-8 and is not NUL
+// terminated (RFC8044 3.4), so the bytes are returned as they are.
+func (v RADIUSAttributeValue) Text() string {
+	return string(v)
+}
+
+// Time decodes the value as a date, the seconds since 00:00:00 UTC, January
+// 1, 1970 (RFC2866 5.).
+func (v RADIUSAttributeValue) Time() (time.Time, error) {
+	n, err := v.Uint32()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("RADIUS date length %d invalid", len(v))
+	}
+	return time.Unix(int64(n), 0).UTC(), nil
+}
+
+// uint32Attribute returns the integer value of the first attribute of type t.
+func (radius *RADIUS) uint32Attribute(t RADIUSAttributeType) (uint32, bool) {
+	attr, ok := radius.GetAttribute(t)
+	if !ok {
+		return 0, false
+	}
+	n, err := attr.Value.Uint32()
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// uint32Value encodes n as a 32 bit big-endian attribute value.
+func uint32Value(n uint32) []byte {
+	v := make([]byte, 4)
+	binary.BigEndian.PutUint32(v, n)
+	return v
+}
+
+// IP decodes an address-typed attribute, returning a 4 byte net.IP for
+// ipaddr attributes and a 16 byte net.IP for ipv6addr attributes.
+func (a RADIUSAttribute) IP() (net.IP, error) {
+	switch a.Type.ValueType() {
+	case AttrValueTypeIPAddr:
+		if len(a.Value) != net.IPv4len {
+			return nil, fmt.Errorf("RADIUS %s length %d invalid", a.Type, len(a.Value))
+		}
+	case AttrValueTypeIPv6Addr:
+		if len(a.Value) != net.IPv6len {
+			return nil, fmt.Errorf("RADIUS %s length %d invalid", a.Type, len(a.Value))
+		}
+	default:
+		return nil, fmt.Errorf("RADIUS %s is not an address", a.Type)
+	}
+	ip := make(net.IP, len(a.Value))
+	copy(ip, a.Value)
+	return ip, nil
+}
+
+// IPMask decodes a Framed-IP-Netmask, which must be a contiguous 4 byte
+// netmask.
+func (a RADIUSAttribute) IPMask() (net.IPMask, error) {
+	if a.Type != RADIUSAttributeTypeFramedIPNetmask {
+		return nil, fmt.Errorf("RADIUS %s is not %s", a.Type, RADIUSAttributeTypeFramedIPNetmask)
+	}
+	if len(a.Value) != net.IPv4len {
+		return nil, fmt.Errorf("RADIUS %s length %d invalid", a.Type, len(a.Value))
+	}
+	mask := make(net.IPMask, len(a.Value))
+	copy(mask, a.Value)
+	if _, bits := mask.Size(); bits == 0 {
+		return nil, fmt.Errorf("RADIUS %s %s is not contiguous", a.Type, net.IP(mask))
+	}
+	return mask, nil
+}
+
+// FramedNetwork returns the Framed-IP-Address with its Framed-IP-Netmask.
+// Without a Framed-IP-Netmask the network is the single host address. It
+// returns false for the 255.255.255.255 and 255.255.255.254 values, which
+// let the user or the NAS select the address (RFC2865 5.8).
+func (radius *RADIUS) FramedNetwork() (*net.IPNet, bool) {
+	attr, ok := radius.GetAttribute(RADIUSAttributeTypeFramedIPAddress)
+	if !ok {
+		return nil, false
+	}
+	ip, err := attr.IP()
+	if err != nil || ip.Equal(net.IPv4bcast) || ip.Equal(net.IPv4(255, 255, 255, 254)) {
+		return nil, false
+	}
+
+	mask := net.CIDRMask(8*net.IPv4len, 8*net.IPv4len)
+	if attr, ok := radius.GetAttribute(RADIUSAttributeTypeFramedIPNetmask); ok {
+		if mask, err = attr.IPMask(); err != nil {
+			return nil, false
+		}
+	}
+	return &net.IPNet{IP: ip, Mask: mask}, true
+}
+
+// AllIPs returns the address of every ipaddr and ipv6addr attribute, keyed
+// by attribute type. The first attribute of each type is used, and
+// attributes that do not decode as an address are skipped.
+func (radius *RADIUS) AllIPs() map[RADIUSAttributeType]net.IP {
+	ips := make(map[RADIUSAttributeType]net.IP)
+	for _, v := range radius.Attributes {
+		if _, ok := ips[v.Type]; ok {
+			continue
+		}
+		if ip, err := v.IP(); err == nil {
+			ips[v.Type] = ip
+		}
+	}
+	return ips
+}
+
+// DecodedValue decodes the value according to the value type of the attribute
+// type: string for string, uint32 or the enumeration type for integer, net.IP
+// for ipaddr and ipv6addr, *net.IPNet for ipv6prefix, time.Time for date, and
+// []byte for octets. The tag of tagged string and integer attributes is
+// stripped, see Tag.
+func (a RADIUSAttribute) DecodedValue() (interface{}, error) {
+	valueType := a.Type.ValueType()
+	value := a.Value
+	if a.Type.IsTagged() && (valueType == AttrValueTypeString || valueType == AttrValueTypeInteger) {
+		_, tagged, ok := a.Tag()
+		if !ok {
+			return nil, fmt.Errorf("RADIUS %s tagged value invalid", a.Type)
+		}
+		value = tagged
+		if valueType == AttrValueTypeInteger {
+			value = append(RADIUSAttributeValue{0x00}, value...)
+		}
+	}
+
+	switch valueType {
+	case AttrValueTypeString:
+		return string(value), nil
+	case AttrValueTypeInteger:
+		n, err := value.Uint32()
+		if err != nil {
+			return nil, err
+		}
+		return decodedEnum(a.Type, n), nil
+	case AttrValueTypeIPAddr, AttrValueTypeIPv6Addr:
+		return a.IP()
+	case AttrValueTypeIPv6Prefix:
+		return value.IPv6Prefix()
+	case AttrValueTypeDate:
+		return value.Time()
+	default:
+		return []byte(value), nil
+	}
+}
+
+// String returns the attribute as "Name = value", with the value decoded as
+// DecodedValue does: strings are quoted and octets are in hex. The tag of a
+// tagged attribute is shown after the name, as in "Tunnel-Type:1 = VLAN",
+// unless it is 0.
+func (a RADIUSAttribute) String() string {
+	name := a.Type.String()
+	if tag, _, ok := a.Tag(); ok && tag != 0 {
+		name = fmt.Sprintf("%s:%d", name, tag)
+	}
+	return name + " = " + a.formatValue("0x")
+}
+
+// formatValue formats the decoded value of the attribute, for String and
+// DiffRADIUS: strings quoted, other decoded values with %v, and octets or
+// values that do not decode in hex after octetsPrefix.
+func (a RADIUSAttribute) formatValue(octetsPrefix string) string {
+	decoded, err := a.DecodedValue()
+	if err != nil {
+		return fmt.Sprintf("%s%x", octetsPrefix, []byte(a.Value))
+	}
+	switch d := decoded.(type) {
+	case []byte:
+		return fmt.Sprintf("%s%x", octetsPrefix, d)
+	case string:
+		return fmt.Sprintf("%q", d)
+	default:
+		return fmt.Sprintf("%v", d)
+	}
+}
+
+// decodedEnum returns n as the enumeration type of the attribute type, if any.
+func decodedEnum(t RADIUSAttributeType, n uint32) interface{} {
+	switch t {
+	case RADIUSAttributeTypeFramedRouting:
+		return FramedRouting(n)
+	case RADIUSAttributeTypeFramedCompression:
+		return FramedCompression(n)
+	case RADIUSAttributeTypeAcctStatusType:
+		return AcctStatusType(n)
+	case RADIUSAttributeTypeTunnelType:
+		return TunnelType(n)
+	case RADIUSAttributeTypeTunnelMediumType:
+		return TunnelMediumType(n)
+	case RADIUSAttributeTypeErrorCause:
+		return ErrorCause(n)
+	case RADIUSAttributeTypeServiceType:
+		return ServiceType(n)
+	case RADIUSAttributeTypeNASPortType:
+		return NASPortType(n)
+	default:
+		return n
+	}
+}
+
+// DecodeAttribute decodes the attribute at the start of data. The Value of
+// the returned attribute refers to data.
+func DecodeAttribute(data []byte) (RADIUSAttribute, error) {
+	if len(data) < 2 {
+		return RADIUSAttribute{}, fmt.Errorf("RADIUS attribute header length %d too short", len(data))
+	}
+	n := int(data[1])
+	if n < 2 {
+		return RADIUSAttribute{}, fmt.Errorf("RADIUS attribute %s length %d too short", RADIUSAttributeType(data[0]), n)
+	}
+	if n > len(data) {
+		return RADIUSAttribute{}, fmt.Errorf("RADIUS attribute %s length %d exceeds remaining %d bytes", RADIUSAttributeType(data[0]), n, len(data))
+	}
+	return RADIUSAttribute{
+		Type:   RADIUSAttributeType(data[0]),
+		Length: RADIUSAttributeLength(data[1]),
+		Value:  RADIUSAttributeValue(data[2:n]),
+	}, nil
+}
+
+// ParseAttributeHex decodes a single attribute from its hex string, e.g.
+// "010741646d696e" for a User-Name of "Admin".
+func ParseAttributeHex(s string) (RADIUSAttribute, error) {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return RADIUSAttribute{}, fmt.Errorf("RADIUS attribute hex invalid: %s", err)
+	}
+	attr, err := DecodeAttribute(data)
+	if err != nil {
+		return RADIUSAttribute{}, err
+	}
+	if int(attr.Length) != len(data) {
+		return RADIUSAttribute{}, fmt.Errorf("RADIUS attribute %s length %d does not match %d bytes", attr.Type, attr.Length, len(data))
+	}
+	return attr, nil
+}
@@ -0,0 +1,361 @@
+package radius
+
+import (
+	"bytes"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRADIUSAttributeIP(t *testing.T) {
+	tests := []struct {
+		desc    string
+		attr    RADIUSAttribute
+		want    net.IP
+		wantErr bool
+	}{
+		{
+			desc: "NAS-IP-Address",
+			attr: newAttribute(RADIUSAttributeTypeNASIPAddress, []byte{0x7f, 0x00, 0x01, 0x01}),
+			want: net.IPv4(127, 0, 1, 1).To4(),
+		},
+		{
+			desc: "Framed-IPv6-Address",
+			attr: newAttribute(RADIUSAttributeTypeFramedIPv6Address, net.ParseIP("2001:db8::1")),
+			want: net.ParseIP("2001:db8::1"),
+		},
+		{
+			desc:    "Framed-IPv6-Address too short",
+			attr:    newAttribute(RADIUSAttributeTypeFramedIPv6Address, []byte{0x20, 0x01, 0x0d, 0xb8}),
+			wantErr: true,
+		},
+		{
+			desc:    "NAS-IP-Address truncated",
+			attr:    newAttribute(RADIUSAttributeTypeNASIPAddress, []byte{0x7f, 0x00, 0x01}),
+			wantErr: true,
+		},
+		{
+			desc:    "User-Name",
+			attr:    newAttribute(RADIUSAttributeTypeUserName, []byte("Admin")),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.attr.IP()
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error", tt.desc)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.desc, err)
+			continue
+		}
+		if !got.Equal(tt.want) || len(got) != len(tt.want) {
+			t.Errorf("%s: got %v (%d bytes) want %v (%d bytes)", tt.desc, got, len(got), tt.want, len(tt.want))
+		}
+	}
+}
+
+func TestRADIUSAttributeValueDecoders(t *testing.T) {
+	radius := decodeTestRADIUS(t, testRADIUSAccessRequest)
+
+	port, ok := radius.GetAttribute(RADIUSAttributeTypeNASPort)
+	if !ok {
+		t.Fatal("NAS-Port missing")
+	}
+	if n, err := port.Value.Uint32(); err != nil || n != 0 {
+		t.Errorf("NAS-Port got %d, %v want 0", n, err)
+	}
+
+	nasIP, ok := radius.GetAttribute(RADIUSAttributeTypeNASIPAddress)
+	if !ok {
+		t.Fatal("NAS-IP-Address missing")
+	}
+	if ip, err := nasIP.Value.IP(); err != nil || !ip.Equal(net.IPv4(127, 0, 1, 1)) {
+		t.Errorf("NAS-IP-Address got %v, %v want 127.0.1.1", ip, err)
+	}
+
+	userName, _ := radius.GetAttribute(RADIUSAttributeTypeUserName)
+	if s := userName.Value.Text(); s != "Admin" {
+		t.Errorf("User-Name got %q want %q", s, "Admin")
+	}
+
+	date := RADIUSAttributeValue{0x59, 0x68, 0x2f, 0x00}
+	if ts, err := date.Time(); err != nil || !ts.Equal(time.Unix(0x59682f00, 0)) {
+		t.Errorf("date got %v, %v want %v", ts, err, time.Unix(0x59682f00, 0).UTC())
+	}
+
+	for _, v := range []RADIUSAttributeValue{{}, {0x00, 0x00, 0x01}, {0x00, 0x00, 0x00, 0x00, 0x01}} {
+		if _, err := v.Uint32(); err == nil {
+			t.Errorf("Uint32 %x: expected error", []byte(v))
+		}
+		if _, err := v.Time(); err == nil {
+			t.Errorf("Time %x: expected error", []byte(v))
+		}
+		if _, err := v.IP(); err == nil {
+			t.Errorf("IP %x: expected error", []byte(v))
+		}
+	}
+}
+
+func TestRADIUSFramedNetwork(t *testing.T) {
+	address := newAttribute(RADIUSAttributeTypeFramedIPAddress, []byte{198, 51, 100, 7})
+	tests := []struct {
+		desc       string
+		attributes []RADIUSAttribute
+		want       string
+		wantOK     bool
+	}{
+		{"Netmask", []RADIUSAttribute{address, newAttribute(RADIUSAttributeTypeFramedIPNetmask, []byte{255, 255, 255, 0})}, "198.51.100.7/24", true},
+		{"Host", []RADIUSAttribute{address}, "198.51.100.7/32", true},
+		{"NotContiguous", []RADIUSAttribute{address, newAttribute(RADIUSAttributeTypeFramedIPNetmask, []byte{255, 0, 255, 0})}, "", false},
+		{"ShortNetmask", []RADIUSAttribute{address, newAttribute(RADIUSAttributeTypeFramedIPNetmask, []byte{255, 255, 255})}, "", false},
+		{"UserSelected", []RADIUSAttribute{newAttribute(RADIUSAttributeTypeFramedIPAddress, []byte{255, 255, 255, 255})}, "", false},
+		{"NASSelected", []RADIUSAttribute{newAttribute(RADIUSAttributeTypeFramedIPAddress, []byte{255, 255, 255, 254})}, "", false},
+		{"Absent", nil, "", false},
+	}
+
+	for _, tt := range tests {
+		radius := &RADIUS{Attributes: tt.attributes}
+		network, ok := radius.FramedNetwork()
+		if ok != tt.wantOK {
+			t.Errorf("%s: got %v, %v want %s, %v", tt.desc, network, ok, tt.want, tt.wantOK)
+			continue
+		}
+		if ok && network.String() != tt.want {
+			t.Errorf("%s: got %s want %s", tt.desc, network, tt.want)
+		}
+	}
+
+	if _, err := address.IPMask(); err == nil {
+		t.Errorf("%s: IPMask expected error", address.Type)
+	}
+}
+
+func TestRADIUSAllIPs(t *testing.T) {
+	radius := &RADIUS{Attributes: []RADIUSAttribute{
+		newAttribute(RADIUSAttributeTypeUserName, []byte("Admin")),
+		newAttribute(RADIUSAttributeTypeNASIPAddress, []byte{192, 0, 2, 1}),
+		newAttribute(RADIUSAttributeTypeFramedIPAddress, []byte{198, 51, 100, 7}),
+		newAttribute(RADIUSAttributeTypeFramedIPAddress, []byte{198, 51, 100, 8}),
+		newAttribute(RADIUSAttributeTypeLoginIPHost, []byte{192, 0, 2}),
+		newAttribute(RADIUSAttributeTypeNASIPv6Address, net.ParseIP("2001:db8::1")),
+	}}
+
+	want := map[RADIUSAttributeType]net.IP{
+		RADIUSAttributeTypeNASIPAddress:    net.IPv4(192, 0, 2, 1).To4(),
+		RADIUSAttributeTypeFramedIPAddress: net.IPv4(198, 51, 100, 7).To4(),
+		RADIUSAttributeTypeNASIPv6Address:  net.ParseIP("2001:db8::1"),
+	}
+	if got := radius.AllIPs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestRADIUSGetAttributes(t *testing.T) {
+	radius := &RADIUS{Attributes: []RADIUSAttribute{
+		newAttribute(RADIUSAttributeTypeProxyState, []byte("first")),
+		newAttribute(RADIUSAttributeTypeUserName, []byte("Admin")),
+		newAttribute(RADIUSAttributeTypeProxyState, []byte("second")),
+		newAttribute(RADIUSAttributeTypeProxyState, []byte("first")),
+	}}
+	want := []RADIUSAttribute{radius.Attributes[0], radius.Attributes[2], radius.Attributes[3]}
+
+	if got := radius.GetAttributes(RADIUSAttributeTypeProxyState); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetAttributes got %v want %v", got, want)
+	}
+	if got := radius.GetAttributes(RADIUSAttributeTypeState); got != nil {
+		t.Errorf("GetAttributes got %v want none", got)
+	}
+	if got, ok := radius.GetAttribute(RADIUSAttributeTypeProxyState); !ok || !reflect.DeepEqual(got, want[0]) {
+		t.Errorf("GetAttribute got %v, %v want %v", got, ok, want[0])
+	}
+	if _, ok := radius.GetAttribute(RADIUSAttributeTypeState); ok {
+		t.Error("GetAttribute of a missing type")
+	}
+	if !radius.HasAttribute(RADIUSAttributeTypeUserName) || radius.HasAttribute(RADIUSAttributeTypeState) {
+		t.Error("HasAttribute")
+	}
+	if len(radius.Attributes) != 4 {
+		t.Errorf("packet modified: %v", radius.Attributes)
+	}
+}
+
+func TestRADIUSCountAttribute(t *testing.T) {
+	radius := decodeTestRADIUS(t, testRADIUSAccessRequest)
+	tests := []struct {
+		t    RADIUSAttributeType
+		want int
+	}{
+		{RADIUSAttributeTypeUserName, 1},
+		{RADIUSAttributeTypeMessageAuthenticator, 1},
+		{RADIUSAttributeTypeState, 0},
+	}
+	for _, tt := range tests {
+		if n := radius.CountAttribute(tt.t); n != tt.want {
+			t.Errorf("%s: got %d want %d", tt.t, n, tt.want)
+		}
+	}
+
+	radius = &RADIUS{Attributes: []RADIUSAttribute{
+		newAttribute(RADIUSAttributeTypeProxyState, []byte("first")),
+		newAttribute(RADIUSAttributeTypeUserName, []byte("Admin")),
+		newAttribute(RADIUSAttributeTypeProxyState, []byte("second")),
+	}}
+	if n := radius.CountAttribute(RADIUSAttributeTypeProxyState); n != 2 {
+		t.Errorf("Proxy-State: got %d want 2", n)
+	}
+}
+
+func TestRADIUSFilterAttributes(t *testing.T) {
+	radius := decodeTestRADIUS(t, testRADIUSAccessRequest)
+	got := radius.FilterAttributes(func(a RADIUSAttribute) bool { return len(a.Value) == 16 })
+	want := []RADIUSAttribute{radius.Attributes[1], radius.Attributes[4]}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v want %v", got, want)
+	}
+
+	got[0], got[1] = got[1], got[0]
+	if radius.Attributes[1].Type != RADIUSAttributeTypeUserPassword || radius.Attributes[2].Type != RADIUSAttributeTypeNASIPAddress {
+		t.Errorf("packet modified: %v", radius.Attributes)
+	}
+
+	if got := radius.FilterAttributes(func(RADIUSAttribute) bool { return false }); got != nil {
+		t.Errorf("no match: got %v", got)
+	}
+}
+
+func TestRADIUSProxyStateOrder(t *testing.T) {
+	radius := NewRADIUS(RADIUSCodeAccessRequest, 0x01).
+		AddRaw(RADIUSAttributeTypeProxyState, []byte("proxy2")).
+		AddString(RADIUSAttributeTypeUserName, "Admin").
+		AddRaw(RADIUSAttributeTypeProxyState, []byte("proxy1")).
+		Build()
+	data, err := radius.serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	radius = decodeTestRADIUS(t, data)
+	got, err := radius.serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("got\n%x\nwant\n%x", got, data)
+	}
+	proxyStates := radius.GetAttributes(RADIUSAttributeTypeProxyState)
+	if len(proxyStates) != 2 || string(proxyStates[0].Value) != "proxy2" || string(proxyStates[1].Value) != "proxy1" {
+		t.Errorf("Proxy-State got %v", proxyStates)
+	}
+}
+
+func TestRADIUSRemoveAttribute(t *testing.T) {
+	radius := &RADIUS{Attributes: []RADIUSAttribute{
+		newAttribute(RADIUSAttributeTypeProxyState, []byte("first")),
+		newAttribute(RADIUSAttributeTypeUserName, []byte("Admin")),
+		newAttribute(RADIUSAttributeTypeProxyState, []byte("second")),
+		newAttribute(RADIUSAttributeTypeNASPort, uint32Value(1)),
+		newAttribute(RADIUSAttributeTypeProxyState, []byte("third")),
+	}}
+	want := []RADIUSAttribute{radius.Attributes[1], radius.Attributes[3]}
+
+	if n := radius.RemoveAttribute(RADIUSAttributeTypeProxyState); n != 3 {
+		t.Errorf("got %d removed want 3", n)
+	}
+	if !reflect.DeepEqual(radius.Attributes, want) {
+		t.Errorf("got %v want %v", radius.Attributes, want)
+	}
+	if n := radius.RemoveAttribute(RADIUSAttributeTypeState); n != 0 {
+		t.Errorf("missing type: got %d removed want 0", n)
+	}
+	if len(radius.Attributes) != 2 {
+		t.Errorf("missing type: got %v", radius.Attributes)
+	}
+}
+
+func TestRADIUSAttributeDecodedValue(t *testing.T) {
+	radius := decodeTestRADIUS(t, testRADIUSAccessRequest)
+	want := []interface{}{
+		"Admin",
+		[]byte("\x4d\x2f\x62\x0b\x33\x9d\x6d\x1f\xe0\xe4\x6d\x1f\x9b\xda\xff\xf0"),
+		net.IPv4(127, 0, 1, 1).To4(),
+		uint32(0),
+		[]byte("\x41\x73\xed\x26\xd3\xb3\xa9\x64\xff\x4d\xc3\x0d\x94\x33\xe8\x2a"),
+	}
+	for i, attr := range radius.Attributes {
+		got, err := attr.DecodedValue()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", attr.Type, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("%s: got %#v want %#v", attr.Type, got, want[i])
+		}
+	}
+
+	ts := newAttribute(RADIUSAttributeTypeEventTimestamp, []byte{0x5f, 0x5e, 0x10, 0x00})
+	if got, err := ts.DecodedValue(); err != nil || !got.(time.Time).Equal(time.Unix(0x5f5e1000, 0)) {
+		t.Errorf("Event-Timestamp: got %v, %v", got, err)
+	}
+
+	if _, err := newAttribute(RADIUSAttributeTypeNASPort, []byte{0x00}).DecodedValue(); err == nil {
+		t.Error("NAS-Port: expected error for truncated integer")
+	}
+}
+
+func TestRADIUSAttributeString(t *testing.T) {
+	tests := []struct {
+		attr RADIUSAttribute
+		want string
+	}{
+		{newAttribute(RADIUSAttributeTypeUserName, []byte("Admin")), `User-Name = "Admin"`},
+		{newAttribute(RADIUSAttributeTypeNASPort, uint32Value(5)), "NAS-Port = 5"},
+		{newAttribute(RADIUSAttributeTypeNASIPAddress, []byte{127, 0, 1, 1}), "NAS-IP-Address = 127.0.1.1"},
+		{newAttribute(RADIUSAttributeTypeState, []byte{0x01, 0x02}), "State = 0x0102"},
+		{newAttribute(RADIUSAttributeTypeTunnelType, []byte{0x01, 0x00, 0x00, 0x0d}), "Tunnel-Type:1 = VLAN"},
+		{newAttribute(RADIUSAttributeTypeTunnelType, []byte{0x00, 0x00, 0x00, 0x0d}), "Tunnel-Type = VLAN"},
+		{newAttribute(RADIUSAttributeTypeTunnelMediumType, []byte{0x02, 0x00, 0x00, 0x06}), "Tunnel-Medium-Type:2 = IEEE-802"},
+		{newAttribute(RADIUSAttributeTypeTunnelPrivateGroupID, []byte("\x01100")), `Tunnel-Private-Group-ID:1 = "100"`},
+		{newAttribute(RADIUSAttributeTypeTunnelPrivateGroupID, []byte("100")), `Tunnel-Private-Group-ID = "100"`},
+		{newAttribute(RADIUSAttributeTypeNASPort, []byte{0x01}), "NAS-Port = 0x01"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.attr.String(); got != tt.want {
+			t.Errorf("got %q want %q", got, tt.want)
+		}
+	}
+}
+
+func TestParseAttributeHex(t *testing.T) {
+	attr, err := ParseAttributeHex("010741646d696e")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := RADIUSAttribute{
+		Type:   RADIUSAttributeTypeUserName,
+		Length: RADIUSAttributeLength(0x07),
+		Value:  RADIUSAttributeValue("Admin"),
+	}
+	if !reflect.DeepEqual(attr, want) {
+		t.Errorf("got %#v want %#v", attr, want)
+	}
+
+	for _, s := range []string{
+		"",                 // empty
+		"01",               // truncated header
+		"0107416d",         // truncated value
+		"0101",             // length too short
+		"010741646d696e00", // trailing bytes
+		"01074164zz696e",   // invalid hex
+	} {
+		if _, err := ParseAttributeHex(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
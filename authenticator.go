@@ -0,0 +1,197 @@
+package radius
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/subtle"
+	"fmt"
+
+	"github.com/google/gopacket"
+)
+
+// ComputeResponseAuthenticator returns the Response Authenticator of a RADIUS
+// response packet, MD5(Code+ID+Length+RequestAuth+Attributes+Secret).
+//
+// This package decodes single packets, so the caller supplies the
+// authenticator of the request this packet responds to.
+func (radius *RADIUS) ComputeResponseAuthenticator(requestAuthenticator RADIUSAuthenticator, secret []byte) (RADIUSAuthenticator, error) {
+	data, err := radius.serialize()
+	if err != nil {
+		return RADIUSAuthenticator{}, err
+	}
+	copy(data[4:20], requestAuthenticator[:])
+
+	var auth RADIUSAuthenticator
+	h := md5.New()
+	h.Write(data)
+	h.Write(secret)
+	copy(auth[:], h.Sum(nil))
+	return auth, nil
+}
+
+// VerifyResponseAuthenticator reports whether the Authenticator of a RADIUS
+// response packet (Access-Accept, Access-Reject, Access-Challenge,
+// Accounting-Response, or the ACK and NAK of Disconnect-Request and
+// CoA-Request) matches the one computed from the authenticator of its
+// request and the shared secret. As for ComputeResponseAuthenticator, the
+// caller supplies the authenticator of the request.
+func (radius *RADIUS) VerifyResponseAuthenticator(requestAuthenticator RADIUSAuthenticator, secret []byte) bool {
+	auth, err := radius.ComputeResponseAuthenticator(requestAuthenticator, secret)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(auth[:], radius.Authenticator[:]) == 1
+}
+
+// VerifyResponseAuthenticatorStrict is VerifyResponseAuthenticator which also
+// requires the response to carry a Message-Authenticator that validates, the
+// recommended mitigation of the BlastRADIUS attack (CVE-2024-3596) on the
+// MD5 based Response Authenticator.
+func (radius *RADIUS) VerifyResponseAuthenticatorStrict(requestAuthenticator RADIUSAuthenticator, secret []byte) bool {
+	attr, ok := radius.GetAttribute(RADIUSAttributeTypeMessageAuthenticator)
+	if !ok {
+		return false
+	}
+	mac, err := radius.messageAuthenticator(requestAuthenticator, secret)
+	if err != nil || !hmac.Equal(mac, attr.Value) {
+		return false
+	}
+	return radius.VerifyResponseAuthenticator(requestAuthenticator, secret)
+}
+
+// VerifyMessageAuthenticator reports whether the Message-Authenticator of a
+// request packet matches the one computed with the shared secret. It returns
+// an error if the packet has no Message-Authenticator or is a response, whose
+// Message-Authenticator depends on the authenticator of its request, see
+// VerifyResponseAuthenticatorStrict.
+func (radius *RADIUS) VerifyMessageAuthenticator(secret []byte) (bool, error) {
+	attr, ok := radius.GetAttribute(RADIUSAttributeTypeMessageAuthenticator)
+	if !ok {
+		return false, fmt.Errorf("RADIUS %s missing", RADIUSAttributeTypeMessageAuthenticator)
+	}
+	authenticator, err := radius.messageAuthenticatorAuthenticator()
+	if err != nil {
+		return false, err
+	}
+	mac, err := radius.messageAuthenticator(authenticator, secret)
+	if err != nil {
+		return false, err
+	}
+	return hmac.Equal(mac, attr.Value), nil
+}
+
+// ComputeMessageAuthenticator sets the value of the Message-Authenticator
+// attribute of a request packet computed with the shared secret, over the
+// serialized form of the packet with all its other attributes. It returns an
+// error if the packet has no Message-Authenticator or is a response. The
+// Request Authenticator of an Accounting-Request covers the
+// Message-Authenticator, so it is computed afterwards.
+func (radius *RADIUS) ComputeMessageAuthenticator(secret []byte) error {
+	i := radius.attributeIndex(RADIUSAttributeTypeMessageAuthenticator)
+	if i < 0 {
+		return fmt.Errorf("RADIUS %s missing", RADIUSAttributeTypeMessageAuthenticator)
+	}
+	authenticator, err := radius.messageAuthenticatorAuthenticator()
+	if err != nil {
+		return err
+	}
+	mac, err := radius.messageAuthenticator(authenticator, secret)
+	if err != nil {
+		return err
+	}
+	radius.Attributes[i] = newAttribute(RADIUSAttributeTypeMessageAuthenticator, mac)
+	return nil
+}
+
+// messageAuthenticatorAuthenticator returns the authenticator the
+// Message-Authenticator of a request is computed with: the Request
+// Authenticator of Access-Request and Status-Server (RFC3579 3.2, RFC5997
+// 3.), and zeroes for Accounting-Request, Disconnect-Request and
+// CoA-Request, whose Request Authenticator is computed over the
+// Message-Authenticator (RFC5176 3.3).
+func (radius *RADIUS) messageAuthenticatorAuthenticator() (RADIUSAuthenticator, error) {
+	switch radius.Code {
+	case RADIUSCodeAccessRequest, RADIUSCodeStatusServer:
+		return radius.Authenticator, nil
+	case RADIUSCodeAccountingRequest, RADIUSCodeDisconnectRequest, RADIUSCodeCoARequest:
+		return RADIUSAuthenticator{}, nil
+	default:
+		return RADIUSAuthenticator{}, fmt.Errorf("RADIUS %s %s requires the authenticator of its request", radius.Code, RADIUSAttributeTypeMessageAuthenticator)
+	}
+}
+
+// messageAuthenticator returns the Message-Authenticator of the packet,
+// HMAC-MD5(Code+ID+Length+Authenticator+Attributes) keyed with the shared
+// secret, computed with the Message-Authenticator value zeroed (RFC3579
+// 3.2). Responses use the authenticator of their request.
+func (radius *RADIUS) messageAuthenticator(authenticator RADIUSAuthenticator, secret []byte) ([]byte, error) {
+	data, err := radius.serialize()
+	if err != nil {
+		return nil, err
+	}
+	copy(data[4:20], authenticator[:])
+	for i := radiusMinimumRecordSizeInBytes; i+2 <= len(data) && data[i+1] >= 2; i += int(data[i+1]) {
+		if RADIUSAttributeType(data[i]) == RADIUSAttributeTypeMessageAuthenticator {
+			end := i + int(data[i+1])
+			if end > len(data) {
+				break
+			}
+			for j := i + 2; j < end; j++ {
+				data[j] = 0
+			}
+		}
+	}
+
+	h := hmac.New(md5.New, secret)
+	h.Write(data)
+	return h.Sum(nil), nil
+}
+
+// ComputeAcctRequestAuthenticator returns the Request Authenticator of an
+// Accounting-Request, MD5(Code+ID+Length+16 zero octets+Attributes+Secret)
+// (RFC2866 3.). A Message-Authenticator must be computed before, as it is
+// covered.
+func (radius *RADIUS) ComputeAcctRequestAuthenticator(secret []byte) (RADIUSAuthenticator, error) {
+	return radius.ComputeResponseAuthenticator(RADIUSAuthenticator{}, secret)
+}
+
+// VerifyAcctRequestAuthenticator reports whether the Authenticator of an
+// Accounting-Request matches the one computed with the shared secret.
+func (radius *RADIUS) VerifyAcctRequestAuthenticator(secret []byte) bool {
+	return radius.VerifyResponseAuthenticator(RADIUSAuthenticator{}, secret)
+}
+
+// ComputeCoARequestAuthenticator returns the Request Authenticator of a
+// Disconnect-Request or CoA-Request, computed as the one of an
+// Accounting-Request (RFC5176 3.3).
+func (radius *RADIUS) ComputeCoARequestAuthenticator(secret []byte) (RADIUSAuthenticator, error) {
+	return radius.ComputeAcctRequestAuthenticator(secret)
+}
+
+// VerifyCoARequestAuthenticator reports whether the Authenticator of a
+// Disconnect-Request or CoA-Request matches the one computed with the shared
+// secret.
+func (radius *RADIUS) VerifyCoARequestAuthenticator(secret []byte) bool {
+	return radius.VerifyAcctRequestAuthenticator(secret)
+}
+
+// DiagnoseSecret tries each candidate shared secret against the Response
+// Authenticator of resp and returns the first one that validates. It is a
+// debugging aid to find which secret a NAS is configured with.
+func DiagnoseSecret(req, resp *RADIUS, candidateSecrets [][]byte) ([]byte, bool) {
+	for _, secret := range candidateSecrets {
+		if resp.VerifyResponseAuthenticator(req.Authenticator, secret) {
+			return secret, true
+		}
+	}
+	return nil, false
+}
+
+// serialize returns the wire format of the packet as it is, without fixing lengths.
+func (radius *RADIUS) serialize() ([]byte, error) {
+	buf := gopacket.NewSerializeBuffer()
+	if err := radius.SerializeTo(buf, gopacket.SerializeOptions{}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
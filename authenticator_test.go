@@ -0,0 +1,257 @@
+package radius
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRADIUSVerifyResponseAuthenticator(t *testing.T) {
+	req := decodeTestRADIUS(t, testRADIUSAccessRequest)
+	resp := decodeTestRADIUS(t, testRADIUSAccessAccept)
+
+	if !resp.VerifyResponseAuthenticator(req.Authenticator, []byte("secret")) {
+		t.Error("Response Authenticator does not validate with the correct secret")
+	}
+	if resp.VerifyResponseAuthenticator(req.Authenticator, []byte("testing123")) {
+		t.Error("Response Authenticator validates with a wrong secret")
+	}
+	if resp.VerifyResponseAuthenticator(RADIUSAuthenticator{}, []byte("secret")) {
+		t.Error("Response Authenticator validates with a wrong request authenticator")
+	}
+}
+
+func TestRADIUSVerifyResponseAuthenticatorCodes(t *testing.T) {
+	secret := []byte("secret")
+	requestAuthenticator := [16]byte{0x3b, 0xbd, 0x22, 0x52, 0xb4, 0xc8, 0xd8, 0x44, 0x1b, 0x46, 0x79, 0xbf, 0x4a, 0x2b, 0x86, 0x01}
+
+	for _, code := range []RADIUSCode{
+		RADIUSCodeAccessAccept,
+		RADIUSCodeAccessReject,
+		RADIUSCodeAccessChallenge,
+		RADIUSCodeAccountingResponse,
+	} {
+		resp := NewRADIUS(code, 0x8d).
+			AddRaw(RADIUSAttributeTypeReplyMessage, []byte("Hello")).
+			Build()
+		auth, err := resp.ComputeResponseAuthenticator(requestAuthenticator, secret)
+		if err != nil {
+			t.Fatalf("%s: %v", code, err)
+		}
+		resp.Authenticator = auth
+
+		if !resp.VerifyResponseAuthenticator(requestAuthenticator, secret) {
+			t.Errorf("%s: does not validate", code)
+		}
+		resp.Attributes[0] = newAttribute(RADIUSAttributeTypeReplyMessage, []byte("Hellp"))
+		if resp.VerifyResponseAuthenticator(requestAuthenticator, secret) {
+			t.Errorf("%s: modified attribute validates", code)
+		}
+	}
+
+	// the Access-Accept of radtest.pcap, as its Code changed
+	resp := decodeTestRADIUS(t, testRADIUSAccessAccept)
+	resp.Code = RADIUSCodeAccessReject
+	if resp.VerifyResponseAuthenticator(requestAuthenticator, secret) {
+		t.Error("Access-Accept turned Access-Reject validates")
+	}
+}
+
+func TestRADIUSAcctRequestAuthenticator(t *testing.T) {
+	secret := []byte("secret")
+	acct := NewRADIUS(RADIUSCodeAccountingRequest, 0x2a).
+		AddRaw(RADIUSAttributeTypeAcctStatusType, uint32Value(uint32(AcctStatusTypeStop))).
+		AddRaw(RADIUSAttributeTypeAcctSessionId, []byte("0001")).
+		AddRaw(RADIUSAttributeTypeAcctInputOctets, uint32Value(1000)).
+		AddRaw(RADIUSAttributeTypeAcctOutputOctets, uint32Value(2000)).
+		Build()
+	auth, err := acct.ComputeAcctRequestAuthenticator(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	acct.Authenticator = auth
+
+	data, err := acct.serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := decodeTestRADIUS(t, data)
+	if roundTrip, err := decoded.serialize(); err != nil || !bytes.Equal(roundTrip, data) {
+		t.Errorf("round trip got %x, %v want %x", roundTrip, err, data)
+	}
+	if decoded.Code != RADIUSCodeAccountingRequest {
+		t.Errorf("Code got %s", decoded.Code)
+	}
+	if in, ok := decoded.AcctInputBytes(); !ok || in != 1000 {
+		t.Errorf("AcctInputBytes got %d, %v", in, ok)
+	}
+
+	if !decoded.VerifyAcctRequestAuthenticator(secret) {
+		t.Error("Request Authenticator does not validate")
+	}
+	if decoded.VerifyAcctRequestAuthenticator([]byte("testing123")) {
+		t.Error("Request Authenticator validates with a wrong secret")
+	}
+	decoded.Attributes[2] = newAttribute(RADIUSAttributeTypeAcctInputOctets, uint32Value(1))
+	if decoded.VerifyAcctRequestAuthenticator(secret) {
+		t.Error("modified Accounting-Request validates")
+	}
+}
+
+func TestRADIUSCoARequestAuthenticator(t *testing.T) {
+	secret := []byte("secret")
+	req := NewRADIUS(RADIUSCodeDisconnectRequest, 0x07).
+		AddRaw(RADIUSAttributeTypeUserName, []byte("Admin")).
+		AddRaw(RADIUSAttributeTypeAcctSessionId, []byte("0001")).
+		AddRaw(RADIUSAttributeTypeMessageAuthenticator, make([]byte, 16)).
+		Build()
+	if err := req.ComputeMessageAuthenticator(secret); err != nil {
+		t.Fatal(err)
+	}
+	auth, err := req.ComputeCoARequestAuthenticator(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Authenticator = auth
+
+	data, err := req.serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := decodeTestRADIUS(t, data)
+	if decoded.Code != RADIUSCodeDisconnectRequest {
+		t.Errorf("Code got %s", decoded.Code)
+	}
+	if !decoded.VerifyCoARequestAuthenticator(secret) {
+		t.Error("Request Authenticator does not validate")
+	}
+	if ok, err := decoded.VerifyMessageAuthenticator(secret); !ok || err != nil {
+		t.Errorf("Message-Authenticator got %v, %v", ok, err)
+	}
+	if decoded.VerifyCoARequestAuthenticator([]byte("testing123")) {
+		t.Error("Request Authenticator validates with a wrong secret")
+	}
+
+	nak := NewRADIUS(RADIUSCodeDisconnectNAK, req.Identifier).
+		AddUint32(RADIUSAttributeTypeErrorCause, uint32(ErrorCauseSessionContextNotFound)).
+		Build()
+	if nak.Authenticator, err = nak.ComputeResponseAuthenticator(req.Authenticator, secret); err != nil {
+		t.Fatal(err)
+	}
+	if !nak.VerifyResponseAuthenticator(req.Authenticator, secret) {
+		t.Error("Disconnect-NAK does not validate")
+	}
+}
+
+func TestDiagnoseSecret(t *testing.T) {
+	req := decodeTestRADIUS(t, testRADIUSAccessRequest)
+	resp := decodeTestRADIUS(t, testRADIUSAccessAccept)
+
+	secret, ok := DiagnoseSecret(req, resp, [][]byte{
+		[]byte("testing123"),
+		[]byte("secret"),
+		[]byte("radius"),
+	})
+	if !ok || !bytes.Equal(secret, []byte("secret")) {
+		t.Errorf("DiagnoseSecret got %q, %v want %q, true", secret, ok, "secret")
+	}
+
+	if secret, ok := DiagnoseSecret(req, resp, [][]byte{[]byte("testing123")}); ok {
+		t.Errorf("DiagnoseSecret got %q, %v want no match", secret, ok)
+	}
+}
+
+func TestRADIUSVerifyResponseAuthenticatorStrict(t *testing.T) {
+	req := decodeTestRADIUS(t, testRADIUSAccessRequest)
+	secret := []byte("secret")
+
+	// the Access-Request was sent with a Message-Authenticator keyed with secret
+	attr, _ := req.GetAttribute(RADIUSAttributeTypeMessageAuthenticator)
+	if mac, err := req.messageAuthenticator(req.Authenticator, secret); err != nil || !bytes.Equal(mac, attr.Value) {
+		t.Errorf("Access-Request Message-Authenticator got %x, %v want %x", mac, err, attr.Value)
+	}
+
+	// testRADIUSAccessAccept has no Message-Authenticator
+	resp := decodeTestRADIUS(t, testRADIUSAccessAccept)
+	if resp.HasMessageAuthenticator() {
+		t.Fatal("testRADIUSAccessAccept has a Message-Authenticator")
+	}
+	if resp.VerifyResponseAuthenticatorStrict(req.Authenticator, secret) {
+		t.Error("Response without Message-Authenticator validates")
+	}
+
+	signed := NewRADIUS(RADIUSCodeAccessAccept, req.Identifier).
+		AddRaw(RADIUSAttributeTypeMessageAuthenticator, make([]byte, 16)).
+		Build()
+	mac, err := signed.messageAuthenticator(req.Authenticator, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed.Attributes[0] = newAttribute(RADIUSAttributeTypeMessageAuthenticator, mac)
+	if signed.Authenticator, err = signed.ComputeResponseAuthenticator(req.Authenticator, secret); err != nil {
+		t.Fatal(err)
+	}
+
+	if !signed.VerifyResponseAuthenticatorStrict(req.Authenticator, secret) {
+		t.Error("Response with Message-Authenticator does not validate")
+	}
+	if signed.VerifyResponseAuthenticatorStrict(req.Authenticator, []byte("testing123")) {
+		t.Error("Response with Message-Authenticator validates with a wrong secret")
+	}
+
+	// a forged Message-Authenticator fails even with a valid Response Authenticator
+	signed.Attributes[0] = newAttribute(RADIUSAttributeTypeMessageAuthenticator, make([]byte, 16))
+	if signed.Authenticator, err = signed.ComputeResponseAuthenticator(req.Authenticator, secret); err != nil {
+		t.Fatal(err)
+	}
+	if signed.VerifyResponseAuthenticatorStrict(req.Authenticator, secret) {
+		t.Error("Response with a forged Message-Authenticator validates")
+	}
+}
+
+func TestRADIUSMessageAuthenticator(t *testing.T) {
+	secret := []byte("secret")
+	req := decodeTestRADIUS(t, testRADIUSAccessRequest)
+
+	if ok, err := req.VerifyMessageAuthenticator(secret); !ok || err != nil {
+		t.Errorf("Access-Request got %v, %v want true", ok, err)
+	}
+	if ok, err := req.VerifyMessageAuthenticator([]byte("testing123")); ok || err != nil {
+		t.Errorf("Access-Request with a wrong secret got %v, %v want false", ok, err)
+	}
+
+	// recomputing over the zeroed value yields the original one
+	want, _ := req.GetAttribute(RADIUSAttributeTypeMessageAuthenticator)
+	i := req.attributeIndex(RADIUSAttributeTypeMessageAuthenticator)
+	req.Attributes[i] = newAttribute(RADIUSAttributeTypeMessageAuthenticator, make([]byte, 16))
+	if err := req.ComputeMessageAuthenticator(secret); err != nil {
+		t.Fatal(err)
+	}
+	if got := req.Attributes[i]; !bytes.Equal(got.Value, want.Value) || got.Length != want.Length {
+		t.Errorf("ComputeMessageAuthenticator got %x want %x", got.Value, want.Value)
+	}
+
+	// any other attribute is covered
+	req.Attributes[0] = newAttribute(RADIUSAttributeTypeUserName, []byte("Admim"))
+	if ok, _ := req.VerifyMessageAuthenticator(secret); ok {
+		t.Error("modified Access-Request validates")
+	}
+
+	acct := MinimalPacket(RADIUSCodeAccountingRequest)
+	acct.Attributes = append(acct.Attributes, newAttribute(RADIUSAttributeTypeMessageAuthenticator, make([]byte, 16)))
+	if err := acct.ComputeMessageAuthenticator(secret); err != nil {
+		t.Fatal(err)
+	}
+	acct.Authenticator = RADIUSAuthenticator{0x01}
+	if ok, err := acct.VerifyMessageAuthenticator(secret); !ok || err != nil {
+		t.Errorf("Accounting-Request got %v, %v want true", ok, err)
+	}
+
+	if _, err := decodeTestRADIUS(t, testRADIUSAccessAccept).VerifyMessageAuthenticator(secret); err == nil {
+		t.Error("Access-Accept without Message-Authenticator: expected error")
+	}
+	resp := MinimalPacket(RADIUSCodeAccessAccept)
+	resp.Attributes = append(resp.Attributes, newAttribute(RADIUSAttributeTypeMessageAuthenticator, make([]byte, 16)))
+	if err := resp.ComputeMessageAuthenticator(secret); err == nil {
+		t.Error("Access-Accept: expected error")
+	}
+}
@@ -0,0 +1,201 @@
+package radius
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net"
+)
+
+// RADIUSBuilder constructs a RADIUS packet attribute by attribute.
+type RADIUSBuilder struct {
+	code          RADIUSCode
+	identifier    RADIUSIdentifier
+	authenticator RADIUSAuthenticator
+	attributes    []RADIUSAttribute
+	secret        []byte
+}
+
+// NewRADIUS returns a RADIUSBuilder for a packet with the given code and identifier.
+func NewRADIUS(code RADIUSCode, id RADIUSIdentifier) *RADIUSBuilder {
+	return &RADIUSBuilder{
+		code:       code,
+		identifier: id,
+	}
+}
+
+// NewAccessRequest returns a RADIUSBuilder for an Access-Request with a
+// random Request Authenticator read from crypto/rand, as RFC2865 3 requires
+// it to be unpredictable.
+func NewAccessRequest(id RADIUSIdentifier) (*RADIUSBuilder, error) {
+	b := NewRADIUS(RADIUSCodeAccessRequest, id)
+	if _, err := rand.Read(b.authenticator[:]); err != nil {
+		return nil, fmt.Errorf("RADIUS Request Authenticator: %s", err)
+	}
+	return b, nil
+}
+
+// NewAccountingRequest returns a RADIUSBuilder for an Accounting-Request
+// whose Request Authenticator, and Message-Authenticator if one is added,
+// are computed with the shared secret by Finalize.
+func NewAccountingRequest(id RADIUSIdentifier, secret []byte) *RADIUSBuilder {
+	b := NewRADIUS(RADIUSCodeAccountingRequest, id)
+	b.secret = append([]byte(nil), secret...)
+	return b
+}
+
+// AddRaw appends an attribute with a copy of value as it is.
+func (b *RADIUSBuilder) AddRaw(t RADIUSAttributeType, value []byte) *RADIUSBuilder {
+	v := make([]byte, len(value))
+	copy(v, value)
+	b.attributes = append(b.attributes, newAttribute(t, v))
+	return b
+}
+
+// AddUint32 appends an integer attribute.
+func (b *RADIUSBuilder) AddUint32(t RADIUSAttributeType, n uint32) *RADIUSBuilder {
+	return b.AddRaw(t, uint32Value(n))
+}
+
+// AddString appends a string attribute.
+func (b *RADIUSBuilder) AddString(t RADIUSAttributeType, s string) *RADIUSBuilder {
+	return b.AddRaw(t, []byte(s))
+}
+
+// AddLongString appends s as attributes of type t of at most 253 bytes each,
+// for values such as a long Cisco-AVPair that receivers concatenate, see
+// ConcatValues. s is split at byte boundaries, as an octet string, and
+// nothing is appended if it is empty.
+func (b *RADIUSBuilder) AddLongString(t RADIUSAttributeType, s string) *RADIUSBuilder {
+	return b.addSplit(t, []byte(s))
+}
+
+// addSplit appends value as attributes of type t of at most 253 bytes each.
+func (b *RADIUSBuilder) addSplit(t RADIUSAttributeType, value []byte) *RADIUSBuilder {
+	for len(value) > radiusMaximumAttributeValueSizeInBytes {
+		b.AddRaw(t, value[:radiusMaximumAttributeValueSizeInBytes])
+		value = value[radiusMaximumAttributeValueSizeInBytes:]
+	}
+	if len(value) > 0 {
+		b.AddRaw(t, value)
+	}
+	return b
+}
+
+// AddIP appends an address attribute: 4 bytes for an IPv4 address, including
+// an IPv4-mapped IPv6 address, and 16 bytes otherwise.
+func (b *RADIUSBuilder) AddIP(t RADIUSAttributeType, ip net.IP) *RADIUSBuilder {
+	if ip4 := ip.To4(); ip4 != nil {
+		return b.AddRaw(t, ip4)
+	}
+	return b.AddRaw(t, ip)
+}
+
+// set replaces the first attribute of type t with a copy of value, or
+// appends it if there is none.
+func (b *RADIUSBuilder) set(t RADIUSAttributeType, value []byte) *RADIUSBuilder {
+	for i, v := range b.attributes {
+		if v.Type == t {
+			b.attributes[i] = newAttribute(t, append([]byte(nil), value...))
+			return b
+		}
+	}
+	return b.AddRaw(t, value)
+}
+
+// SetFramedMTU sets the Framed-MTU attribute. The MTU is set as it is, and
+// Validate of the built packet reports one out of range; check values from
+// configuration up front with ValidateFramedMTU.
+func (b *RADIUSBuilder) SetFramedMTU(mtu uint32) *RADIUSBuilder {
+	return b.set(RADIUSAttributeTypeFramedMTU, uint32Value(mtu))
+}
+
+// ValidateFramedMTU checks that a Framed-MTU is within 64 and 65535 (RFC2865
+// 5.12).
+func ValidateFramedMTU(mtu uint32) error {
+	if mtu < 64 || mtu > 65535 {
+		return fmt.Errorf("RADIUS %s %d out of range 64-65535", RADIUSAttributeTypeFramedMTU, mtu)
+	}
+	return nil
+}
+
+// SetFramedCompression sets the Framed-Compression attribute.
+func (b *RADIUSBuilder) SetFramedCompression(c FramedCompression) *RADIUSBuilder {
+	return b.set(RADIUSAttributeTypeFramedCompression, uint32Value(uint32(c)))
+}
+
+// EncodedLength returns the length of the packet on the wire.
+func (b *RADIUSBuilder) EncodedLength() int {
+	n := radiusMinimumRecordSizeInBytes
+	for _, v := range b.attributes {
+		n += len(v.Value) + 2 // Added Type and Length
+	}
+	return n
+}
+
+// WouldFit reports whether the packet fits in the 4096 bytes maximum RADIUS
+// packet length, and whether any attribute value exceeds 253 bytes and so
+// needs to be fragmented across attributes of the same type.
+func (b *RADIUSBuilder) WouldFit() (fits bool, needsFragmentation bool) {
+	for _, v := range b.attributes {
+		if len(v.Value) > radiusMaximumAttributeValueSizeInBytes {
+			needsFragmentation = true
+		}
+	}
+	return b.EncodedLength() <= radiusMaximumRecordSizeInBytes, needsFragmentation
+}
+
+// Build returns the RADIUS packet with all lengths set. The authenticator is
+// left zeroed for the caller to fill.
+func (b *RADIUSBuilder) Build() *RADIUS {
+	radius := &RADIUS{
+		Code:          b.code,
+		Identifier:    b.identifier,
+		Length:        RADIUSLength(b.EncodedLength()),
+		Authenticator: b.authenticator,
+		Attributes:    make([]RADIUSAttribute, len(b.attributes)),
+	}
+	copy(radius.Attributes, b.attributes)
+	return radius
+}
+
+// Finalize returns the RADIUS packet as Build does, with the authenticators
+// filled for the packets of NewAccessRequest and NewAccountingRequest: the
+// random Request Authenticator of an Access-Request, and for an
+// Accounting-Request the Message-Authenticator, if present, then the Request
+// Authenticator computed with the shared secret.
+func (b *RADIUSBuilder) Finalize() (*RADIUS, error) {
+	radius := b.Build()
+	if b.secret == nil || radius.Code != RADIUSCodeAccountingRequest {
+		return radius, nil
+	}
+	if radius.attributeIndex(RADIUSAttributeTypeMessageAuthenticator) >= 0 {
+		if err := radius.ComputeMessageAuthenticator(b.secret); err != nil {
+			return nil, err
+		}
+	}
+	authenticator, err := radius.ComputeAcctRequestAuthenticator(b.secret)
+	if err != nil {
+		return nil, err
+	}
+	radius.Authenticator = authenticator
+	return radius, nil
+}
+
+// MinimalPacket returns the smallest valid packet for a code, e.g. as a test
+// baseline or a fuzzing seed. Message-Authenticator values are left zeroed
+// for the caller to compute. Codes without required attributes yield the bare
+// 20 bytes header.
+func MinimalPacket(code RADIUSCode) *RADIUS {
+	b := NewRADIUS(code, 0)
+	switch code {
+	case RADIUSCodeAccessRequest:
+		b.AddRaw(RADIUSAttributeTypeMessageAuthenticator, make([]byte, 16))
+	case RADIUSCodeAccountingRequest:
+		b.AddRaw(RADIUSAttributeTypeAcctStatusType, uint32Value(uint32(AcctStatusTypeStart)))
+		b.AddRaw(RADIUSAttributeTypeAcctSessionId, []byte("0"))
+	case RADIUSCodeStatusServer:
+		b.AddRaw(RADIUSAttributeTypeNASIdentifier, []byte("0"))
+		b.AddRaw(RADIUSAttributeTypeMessageAuthenticator, make([]byte, 16))
+	}
+	return b.Build()
+}
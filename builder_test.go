@@ -0,0 +1,236 @@
+package radius
+
+import (
+	"bytes"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestRADIUSBuilderAccessRequest(t *testing.T) {
+	b := NewRADIUS(RADIUSCodeAccessRequest, 0x8d).
+		AddString(RADIUSAttributeTypeUserName, "Admin").
+		AddRaw(RADIUSAttributeTypeUserPassword, testRADIUSAccessRequest[29:45]).
+		AddIP(RADIUSAttributeTypeNASIPAddress, net.ParseIP("127.0.1.1")).
+		AddUint32(RADIUSAttributeTypeNASPort, 0).
+		AddRaw(RADIUSAttributeTypeMessageAuthenticator, testRADIUSAccessRequest[59:75])
+	radius := b.Build()
+	if radius.Length != 0x4b {
+		t.Errorf("Length got %d want %d", radius.Length, 0x4b)
+	}
+	if radius.Authenticator != (RADIUSAuthenticator{}) {
+		t.Errorf("Authenticator got %s want zeroed", radius.Authenticator)
+	}
+
+	copy(radius.Authenticator[:], testRADIUSAccessRequest[4:20])
+	data, err := radius.serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, testRADIUSAccessRequest) {
+		t.Errorf("got\n%x\nwant\n%x", data, testRADIUSAccessRequest)
+	}
+}
+
+func TestNewAccessRequest(t *testing.T) {
+	var authenticators []RADIUSAuthenticator
+	for i := 0; i < 2; i++ {
+		b, err := NewAccessRequest(0x01)
+		if err != nil {
+			t.Fatal(err)
+		}
+		radius, err := b.AddString(RADIUSAttributeTypeUserName, "Admin").Finalize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if radius.Code != RADIUSCodeAccessRequest || radius.Length != 27 {
+			t.Errorf("got %s", radius)
+		}
+		authenticators = append(authenticators, radius.Authenticator)
+	}
+	if authenticators[0] == authenticators[1] {
+		t.Errorf("two Request Authenticators are both %s", authenticators[0])
+	}
+	if authenticators[0] == (RADIUSAuthenticator{}) {
+		t.Error("Request Authenticator is zeroed")
+	}
+}
+
+func TestNewAccountingRequest(t *testing.T) {
+	secret := []byte("secret")
+	radius, err := NewAccountingRequest(0x02, secret).
+		AddUint32(RADIUSAttributeTypeAcctStatusType, uint32(AcctStatusTypeStart)).
+		AddString(RADIUSAttributeTypeAcctSessionId, "0001").
+		AddRaw(RADIUSAttributeTypeMessageAuthenticator, make([]byte, 16)).
+		Finalize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := radius.serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	radius = decodeTestRADIUS(t, data)
+
+	if !radius.VerifyAcctRequestAuthenticator(secret) {
+		t.Error("Request Authenticator invalid")
+	}
+	if ok, err := radius.VerifyMessageAuthenticator(secret); err != nil || !ok {
+		t.Errorf("Message-Authenticator invalid: %v", err)
+	}
+}
+
+func TestRADIUSBuilderAddLongString(t *testing.T) {
+	// 500 bytes, with a multi-byte rune across the 253 bytes boundary
+	long := strings.Repeat("a", 252) + "\u00e9" + strings.Repeat("b", 246)
+	data, err := NewRADIUS(RADIUSCodeAccessAccept, 0x01).
+		AddLongString(RADIUSAttributeTypeClass, long).
+		AddString(RADIUSAttributeTypeReplyMessage, "Welcome").
+		AddString(RADIUSAttributeTypeClass, "other").
+		Build().serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	radius := decodeTestRADIUS(t, data)
+
+	attrs := radius.GetAttributes(RADIUSAttributeTypeClass)
+	if len(attrs) != 3 || len(attrs[0].Value) != 253 || len(attrs[1].Value) != 247 {
+		t.Errorf("got %d Class attributes %v", len(attrs), attrs)
+	}
+	if got := radius.ConcatValues(RADIUSAttributeTypeClass); string(got) != long {
+		t.Errorf("ConcatValues got %d bytes want %d", len(got), len(long))
+	}
+	if got := radius.ConcatValues(RADIUSAttributeTypeState); got != nil {
+		t.Errorf("ConcatValues of a missing type got %q", got)
+	}
+	if n := len(NewRADIUS(RADIUSCodeAccessAccept, 0x01).AddLongString(RADIUSAttributeTypeClass, "").Build().Attributes); n != 0 {
+		t.Errorf("empty string: got %d attributes", n)
+	}
+}
+
+func TestRADIUSBuilderAddIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want []byte
+	}{
+		{"192.0.2.1", []byte{192, 0, 2, 1}},
+		{"::ffff:192.0.2.1", []byte{192, 0, 2, 1}},
+		{"2001:db8::1", net.ParseIP("2001:db8::1")},
+	}
+
+	for _, tt := range tests {
+		radius := NewRADIUS(RADIUSCodeAccessRequest, 0x01).AddIP(RADIUSAttributeTypeNASIPAddress, net.ParseIP(tt.ip)).Build()
+		if got := radius.Attributes[0].Value; !bytes.Equal(got, tt.want) {
+			t.Errorf("%s: got %x want %x", tt.ip, got, tt.want)
+		}
+		if got := radius.Attributes[0].Length; got != RADIUSAttributeLength(len(tt.want)+2) {
+			t.Errorf("%s: Length got %d want %d", tt.ip, got, len(tt.want)+2)
+		}
+	}
+}
+
+func TestRADIUSBuilderWouldFit(t *testing.T) {
+	tests := []struct {
+		desc                  string
+		sizes                 []int
+		fits, needsFragmented bool
+	}{
+		{"Empty", nil, true, false},
+		{"MaximumAttribute", []int{253}, true, false},
+		{"OverlongAttribute", []int{254}, true, true},
+		{"MaximumPacket", append(repeatSizes(253, 15), 249), true, false},
+		{"OverlongPacket", append(repeatSizes(253, 15), 250), false, false},
+	}
+
+	for _, tt := range tests {
+		b := NewRADIUS(RADIUSCodeAccessAccept, 1)
+		for _, n := range tt.sizes {
+			b.AddRaw(RADIUSAttributeTypeReplyMessage, make([]byte, n))
+		}
+		fits, needsFragmentation := b.WouldFit()
+		if fits != tt.fits || needsFragmentation != tt.needsFragmented {
+			t.Errorf("%s: WouldFit() got %v, %v want %v, %v (EncodedLength %d)", tt.desc,
+				fits, needsFragmentation, tt.fits, tt.needsFragmented, b.EncodedLength())
+		}
+	}
+}
+
+func repeatSizes(n, count int) []int {
+	sizes := make([]int, count)
+	for i := range sizes {
+		sizes[i] = n
+	}
+	return sizes
+}
+
+func TestMinimalPacket(t *testing.T) {
+	for _, code := range []RADIUSCode{
+		RADIUSCodeAccessRequest,
+		RADIUSCodeAccessAccept,
+		RADIUSCodeAccessReject,
+		RADIUSCodeAccountingRequest,
+		RADIUSCodeAccountingResponse,
+		RADIUSCodeAccessChallenge,
+		RADIUSCodeStatusServer,
+		RADIUSCodeStatusClient,
+	} {
+		radius := MinimalPacket(code)
+		data, err := radius.serialize()
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", code, err)
+			continue
+		}
+		decoded := decodeTestRADIUS(t, data)
+		if decoded.Code != code || int(decoded.Length) != len(data) || len(decoded.Attributes) != len(radius.Attributes) {
+			t.Errorf("%s: decoded %v length %d with %d attributes from %x", code, decoded.Code, decoded.Length, len(decoded.Attributes), data)
+		}
+		if err := decoded.ValidateOrdering(); err != nil {
+			t.Errorf("%s: unexpected error: %v", code, err)
+		}
+	}
+}
+
+func TestValidateFramedMTU(t *testing.T) {
+	for _, mtu := range []uint32{64, 1500, 65535} {
+		if err := ValidateFramedMTU(mtu); err != nil {
+			t.Errorf("%d: unexpected error: %v", mtu, err)
+		}
+	}
+	for _, mtu := range []uint32{0, 63, 65536} {
+		if err := ValidateFramedMTU(mtu); err == nil {
+			t.Errorf("%d: expected error", mtu)
+		}
+	}
+}
+
+func TestRADIUSBuilderFramedMTUInvalid(t *testing.T) {
+	for _, mtu := range []uint32{0, 70000} {
+		if err := NewRADIUS(RADIUSCodeAccessAccept, 1).SetFramedMTU(mtu).Build().Validate(); err == nil {
+			t.Errorf("%d: expected error", mtu)
+		}
+	}
+	if err := NewRADIUS(RADIUSCodeAccessAccept, 1).SetFramedMTU(1500).Build().Validate(); err != nil {
+		t.Errorf("1500: unexpected error: %v", err)
+	}
+}
+
+func TestRADIUSBuilderFramed(t *testing.T) {
+	radius := NewRADIUS(RADIUSCodeAccessAccept, 1).
+		SetFramedMTU(1500).
+		SetFramedMTU(1492).
+		SetFramedCompression(FramedCompressionVJ).
+		Build()
+
+	if len(radius.Attributes) != 2 {
+		t.Fatalf("got %d attributes want 2", len(radius.Attributes))
+	}
+	if mtu, ok := radius.uint32Attribute(RADIUSAttributeTypeFramedMTU); !ok || mtu != 1492 {
+		t.Errorf("Framed-MTU got %d, %v want 1492", mtu, ok)
+	}
+	if c, ok := radius.FramedCompression(); !ok || c != FramedCompressionVJ {
+		t.Errorf("Framed-Compression got %s, %v want %s", c, ok, FramedCompressionVJ)
+	}
+	if s := radius.Attributes[1].String(); s != "Framed-Compression = Van-Jacobson-TCP-IP" {
+		t.Errorf("got %q", s)
+	}
+}
@@ -0,0 +1,55 @@
+package radius
+
+import (
+	"bytes"
+)
+
+// challengeEchoedAttributeTypes are the attributes of the original
+// Access-Request carried over to the request answering an Access-Challenge.
+var challengeEchoedAttributeTypes = []RADIUSAttributeType{
+	RADIUSAttributeTypeUserName,
+	RADIUSAttributeTypeNASIPAddress,
+	RADIUSAttributeTypeNASIPv6Address,
+	RADIUSAttributeTypeNASIdentifier,
+}
+
+// ContinueRequest returns a new Access-Request answering the Access-Challenge
+// for originalReq. It carries the User-Name and NAS identification of
+// originalReq and echoes the State of the challenge unmodified (RFC2865
+// 4.4, 5.24). The Identifier is the one following originalReq; the
+// authenticator is left zeroed and the challenge response attributes are left
+// for the caller to add.
+func (radius *RADIUS) ContinueRequest(originalReq *RADIUS) *RADIUS {
+	b := NewRADIUS(RADIUSCodeAccessRequest, originalReq.Identifier+1)
+	for _, t := range challengeEchoedAttributeTypes {
+		if attr, ok := originalReq.GetAttribute(t); ok {
+			b.AddRaw(attr.Type, attr.Value)
+		}
+	}
+	if attr, ok := radius.GetAttribute(RADIUSAttributeTypeState); ok {
+		b.AddRaw(attr.Type, attr.Value)
+	}
+	return b.Build()
+}
+
+// State returns the value of the State attribute, which is opaque and may
+// hold any bytes.
+func (radius *RADIUS) State() (RADIUSAttributeValue, bool) {
+	attr, ok := radius.GetAttribute(RADIUSAttributeTypeState)
+	if !ok {
+		return nil, false
+	}
+	return attr.Value, true
+}
+
+// StateMatches reports whether the Access-Request answering challenge echoes
+// its State unmodified (RFC2865 5.24), byte for byte. A packet without State
+// matches only a challenge without State.
+func (radius *RADIUS) StateMatches(challenge *RADIUS) bool {
+	state, ok := radius.State()
+	want, wantOK := challenge.State()
+	if !ok || !wantOK {
+		return ok == wantOK
+	}
+	return bytes.Equal(state, want)
+}
@@ -0,0 +1,81 @@
+package radius
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRADIUSContinueRequest(t *testing.T) {
+	state := []byte{0x00, 0xff, 0x10, 0x20, 0x00, 0x7f}
+	originalReq := decodeTestRADIUS(t, testRADIUSAccessRequest)
+	challenge := NewRADIUS(RADIUSCodeAccessChallenge, originalReq.Identifier).
+		AddRaw(RADIUSAttributeTypeReplyMessage, []byte("Enter PIN")).
+		AddRaw(RADIUSAttributeTypeState, state).
+		Build()
+
+	data, err := challenge.ContinueRequest(originalReq).serialize()
+	if err != nil {
+		t.Fatalf("serialize: %v", err)
+	}
+	req := decodeTestRADIUS(t, data)
+
+	if req.Code != RADIUSCodeAccessRequest {
+		t.Errorf("Code got %s want %s", req.Code, RADIUSCodeAccessRequest)
+	}
+	if req.Identifier != originalReq.Identifier+1 {
+		t.Errorf("Identifier got %d want %d", req.Identifier, originalReq.Identifier+1)
+	}
+	if attr, ok := req.GetAttribute(RADIUSAttributeTypeState); !ok || !bytes.Equal(attr.Value, state) {
+		t.Errorf("State got %x, %v want %x", attr.Value, ok, state)
+	}
+	want, _ := originalReq.GetAttribute(RADIUSAttributeTypeUserName)
+	if attr, ok := req.GetAttribute(RADIUSAttributeTypeUserName); !ok || !bytes.Equal(attr.Value, want.Value) {
+		t.Errorf("User-Name got %q, %v want %q", attr.Value, ok, want.Value)
+	}
+	if _, ok := req.GetAttribute(RADIUSAttributeTypeReplyMessage); ok {
+		t.Error("Reply-Message must not be echoed")
+	}
+	if _, ok := req.GetAttribute(RADIUSAttributeTypeUserPassword); ok {
+		t.Error("User-Password must not be copied")
+	}
+}
+
+func TestRADIUSStateMatches(t *testing.T) {
+	state := []byte{0x00, 0x01, 0x02, 0x00, 0xff, 0xfe, 0x00, 0x00, 0x10, 0x20, 0x30, 0x40, 0x00, 0x7f, 0x80, 0x00}
+	originalReq := decodeTestRADIUS(t, testRADIUSAccessRequest)
+	challenge := NewRADIUS(RADIUSCodeAccessChallenge, originalReq.Identifier).
+		AddRaw(RADIUSAttributeTypeState, state).
+		Build()
+	data, err := challenge.ContinueRequest(originalReq).serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := decodeTestRADIUS(t, data)
+
+	if got, ok := req.State(); !ok || !bytes.Equal(got, state) {
+		t.Errorf("State got %x, %v want %x", got, ok, state)
+	}
+	if !req.StateMatches(challenge) {
+		t.Error("echoed State does not match")
+	}
+
+	altered := append([]byte(nil), state...)
+	altered[15] = 0x01
+	tests := []struct {
+		desc      string
+		req       *RADIUS
+		challenge *RADIUS
+		want      bool
+	}{
+		{"Altered", NewRADIUS(RADIUSCodeAccessRequest, 1).AddRaw(RADIUSAttributeTypeState, altered).Build(), challenge, false},
+		{"Truncated", NewRADIUS(RADIUSCodeAccessRequest, 1).AddRaw(RADIUSAttributeTypeState, state[:15]).Build(), challenge, false},
+		{"Missing", NewRADIUS(RADIUSCodeAccessRequest, 1).Build(), challenge, false},
+		{"Unexpected", req, NewRADIUS(RADIUSCodeAccessChallenge, 1).Build(), false},
+		{"NoState", NewRADIUS(RADIUSCodeAccessRequest, 1).Build(), NewRADIUS(RADIUSCodeAccessChallenge, 1).Build(), true},
+	}
+	for _, tt := range tests {
+		if got := tt.req.StateMatches(tt.challenge); got != tt.want {
+			t.Errorf("%s: got %v want %v", tt.desc, got, tt.want)
+		}
+	}
+}
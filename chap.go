@@ -0,0 +1,52 @@
+package radius
+
+import (
+	"crypto/md5"
+	"crypto/subtle"
+	"fmt"
+)
+
+// radiusCHAPPasswordLength is the length of the CHAP-Password value, the
+// CHAP Ident followed by the 16 bytes MD5 response (RFC2865 5.3).
+const radiusCHAPPasswordLength = 17
+
+// chapResponse returns the CHAP response MD5(Ident+Password+Challenge),
+// RFC1994 4.1.
+func chapResponse(ident byte, password string, challenge []byte) []byte {
+	h := md5.New()
+	h.Write([]byte{ident})
+	h.Write([]byte(password))
+	h.Write(challenge)
+	return h.Sum(nil)
+}
+
+// NewCHAPPassword returns the CHAP-Password attribute answering challenge
+// with password, the CHAP Ident followed by the MD5 response.
+func NewCHAPPassword(ident byte, password string, challenge []byte) RADIUSAttribute {
+	return newAttribute(RADIUSAttributeTypeCHAPPassword, append([]byte{ident}, chapResponse(ident, password, challenge)...))
+}
+
+// CHAPChallenge returns the challenge of the CHAP-Password: the
+// CHAP-Challenge attribute, or the Request Authenticator without it (RFC2865
+// 2.2).
+func (radius *RADIUS) CHAPChallenge() []byte {
+	if attr, ok := radius.GetAttribute(RADIUSAttributeTypeCHAPChallenge); ok {
+		return attr.Value
+	}
+	return radius.Authenticator[:]
+}
+
+// VerifyCHAPPassword reports whether the CHAP-Password of an Access-Request
+// is the response to CHAPChallenge with password. It returns an error if the
+// packet has no CHAP-Password or if its value is not 17 bytes.
+func (radius *RADIUS) VerifyCHAPPassword(password string) (bool, error) {
+	attr, ok := radius.GetAttribute(RADIUSAttributeTypeCHAPPassword)
+	if !ok {
+		return false, fmt.Errorf("RADIUS %s missing", RADIUSAttributeTypeCHAPPassword)
+	}
+	if len(attr.Value) != radiusCHAPPasswordLength {
+		return false, fmt.Errorf("RADIUS %s length %d invalid", attr.Type, len(attr.Value))
+	}
+	want := chapResponse(attr.Value[0], password, radius.CHAPChallenge())
+	return subtle.ConstantTimeCompare(want, attr.Value[1:]) == 1, nil
+}
@@ -0,0 +1,53 @@
+package radius
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestNewCHAPPassword(t *testing.T) {
+	challenge := make([]byte, 16)
+	for i := range challenge {
+		challenge[i] = byte(i)
+	}
+	attr := NewCHAPPassword(0x01, "P@ssW0rd", challenge)
+	if got, want := hex.EncodeToString(attr.Value), "0141e1e0f7ded9b39c189ae6060f834787"; got != want || attr.Length != 19 {
+		t.Errorf("got %s (Length %d) want %s (Length 19)", got, attr.Length, want)
+	}
+}
+
+func TestRADIUSVerifyCHAPPassword(t *testing.T) {
+	var auth RADIUSAuthenticator
+	for i := range auth {
+		auth[i] = byte(i)
+	}
+	challenge := []byte("0123456789abcdef")
+
+	tests := []struct {
+		desc       string
+		attributes []RADIUSAttribute
+		want       bool
+		wantErr    bool
+	}{
+		{"Request Authenticator", []RADIUSAttribute{NewCHAPPassword(0x07, "P@ssW0rd", auth[:])}, true, false},
+		{"CHAP-Challenge", []RADIUSAttribute{
+			NewCHAPPassword(0x07, "P@ssW0rd", challenge),
+			newAttribute(RADIUSAttributeTypeCHAPChallenge, challenge),
+		}, true, false},
+		{"CHAP-Challenge ignored", []RADIUSAttribute{
+			NewCHAPPassword(0x07, "P@ssW0rd", auth[:]),
+			newAttribute(RADIUSAttributeTypeCHAPChallenge, challenge),
+		}, false, false},
+		{"wrong password", []RADIUSAttribute{NewCHAPPassword(0x07, "password", auth[:])}, false, false},
+		{"missing", nil, false, true},
+		{"truncated", []RADIUSAttribute{newAttribute(RADIUSAttributeTypeCHAPPassword, make([]byte, 16))}, false, true},
+	}
+
+	for _, tt := range tests {
+		radius := &RADIUS{Code: RADIUSCodeAccessRequest, Authenticator: auth, Attributes: tt.attributes}
+		got, err := radius.VerifyCHAPPassword("P@ssW0rd")
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("%s: got %v, %v want %v, error %v", tt.desc, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package radius
+
+import (
+	"sync"
+)
+
+// RADIUSClient holds the client side state of the requests sent to RADIUS
+// servers, kept apart for each server. The zero value is ready to use, and
+// it is safe for concurrent use.
+type RADIUSClient struct {
+	mu          sync.Mutex
+	identifiers map[string]uint8
+}
+
+// NextIdentifier returns the Identifier of the next request to the server
+// dst, wrapping after 255. dst names the server, typically its "host:port"
+// address, and each server has its own sequence. Identifiers must be unique
+// among the requests in flight to a server (RFC2865 3.), so the caller must
+// not have more than 256 of them outstanding to one server.
+func (c *RADIUSClient) NextIdentifier(dst string) RADIUSIdentifier {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.identifiers == nil {
+		c.identifiers = make(map[string]uint8)
+	}
+	id := c.identifiers[dst]
+	c.identifiers[dst] = id + 1
+	return RADIUSIdentifier(id)
+}
@@ -0,0 +1,54 @@
+package radius
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestRADIUSClientNextIdentifier(t *testing.T) {
+	c := &RADIUSClient{}
+	for i := 0; i < 300; i++ {
+		if id := c.NextIdentifier("192.0.2.1:1812"); id != RADIUSIdentifier(i) {
+			t.Fatalf("%d: got %d want %d", i, id, RADIUSIdentifier(i))
+		}
+	}
+
+	// each server has its own sequence
+	if id := c.NextIdentifier("192.0.2.2:1812"); id != 0 {
+		t.Errorf("second server: got %d want 0", id)
+	}
+	if id := c.NextIdentifier("192.0.2.1:1812"); id != RADIUSIdentifier(300%256) {
+		t.Errorf("first server: got %d want %d", id, RADIUSIdentifier(300%256))
+	}
+
+	c = &RADIUSClient{}
+	dsts := []string{"192.0.2.1:1812", "192.0.2.2:1812"}
+	var mu sync.Mutex
+	counts := make(map[string]map[RADIUSIdentifier]int)
+	for _, dst := range dsts {
+		counts[dst] = make(map[RADIUSIdentifier]int)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(dst string) {
+			defer wg.Done()
+			for j := 0; j < 128; j++ {
+				id := c.NextIdentifier(dst)
+				mu.Lock()
+				counts[dst][id]++
+				mu.Unlock()
+			}
+		}(dsts[i%len(dsts)])
+	}
+	wg.Wait()
+
+	// 512 identifiers per server wrap exactly twice
+	for _, dst := range dsts {
+		for id := 0; id < 256; id++ {
+			if n := counts[dst][RADIUSIdentifier(id)]; n != 2 {
+				t.Errorf("%s: identifier %d used %d times want 2", dst, id, n)
+			}
+		}
+	}
+}
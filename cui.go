@@ -0,0 +1,22 @@
+package radius
+
+// radiusCUIRequest is the Chargeable-User-Identity value of an Access-Request
+// asking the server for the CUI of the user, RFC4372 2.1.
+var radiusCUIRequest = []byte{0x00}
+
+// RequestsCUI reports whether an Access-Request asks the server to return a
+// Chargeable-User-Identity in its Access-Accept, by carrying one with the
+// single NUL byte value rather than an actual CUI.
+func (radius *RADIUS) RequestsCUI() bool {
+	if radius.Code != RADIUSCodeAccessRequest {
+		return false
+	}
+	attr, ok := radius.GetAttribute(RADIUSAttributeTypeChargeableUserIdentity)
+	return ok && len(attr.Value) == 1 && attr.Value[0] == radiusCUIRequest[0]
+}
+
+// AddCUIRequest appends a Chargeable-User-Identity with the single NUL byte
+// value, asking the server to return the CUI of the user.
+func (b *RADIUSBuilder) AddCUIRequest() *RADIUSBuilder {
+	return b.AddRaw(RADIUSAttributeTypeChargeableUserIdentity, radiusCUIRequest)
+}
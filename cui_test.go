@@ -0,0 +1,26 @@
+package radius
+
+import (
+	"testing"
+)
+
+func TestRADIUSRequestsCUI(t *testing.T) {
+	tests := []struct {
+		desc   string
+		radius *RADIUS
+		want   bool
+	}{
+		{"Request", NewRADIUS(RADIUSCodeAccessRequest, 1).AddCUIRequest().Build(), true},
+		{"Absent", NewRADIUS(RADIUSCodeAccessRequest, 1).Build(), false},
+		{"Value", NewRADIUS(RADIUSCodeAccessRequest, 1).AddRaw(RADIUSAttributeTypeChargeableUserIdentity, []byte("a1b2c3")).Build(), false},
+		{"NULPrefixedValue", NewRADIUS(RADIUSCodeAccessRequest, 1).AddRaw(RADIUSAttributeTypeChargeableUserIdentity, []byte{0x00, 0x41}).Build(), false},
+		{"Empty", NewRADIUS(RADIUSCodeAccessRequest, 1).AddRaw(RADIUSAttributeTypeChargeableUserIdentity, nil).Build(), false},
+		{"Accept", NewRADIUS(RADIUSCodeAccessAccept, 1).AddCUIRequest().Build(), false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.radius.RequestsCUI(); got != tt.want {
+			t.Errorf("%s: got %v want %v", tt.desc, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package radius
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/google/gopacket"
+)
+
+// DecodeOptions holds RADIUS specific decoding options. It is a
+// gopacket.Decoder, so that gopacket.NewPacket decodes a bare RADIUS payload
+// with them:
+//
+//	packet := gopacket.NewPacket(data, radius.DecodeOptions{AttributeLayers: true}, gopacket.Default)
+//
+// LayerTypeRADIUS, which the UDP ports decode to, uses the options set with
+// SetDefaultDecodeOptions, and DecodeFromBytes the Options of the layer.
+type DecodeOptions struct {
+	// AttributeLayers adds a RADIUSAttributeLayer for each attribute after
+	// the RADIUS layer, so that packet.Layers() enumerates the attributes
+	// for generic gopacket tooling. The Attributes of the RADIUS layer are
+	// decoded as usual.
+	AttributeLayers bool
+
+	// CopyValues copies each attribute value, and the Contents and Payload
+	// of the layer, into allocations of their own instead of referring to
+	// the decoded bytes. It costs an allocation per attribute but keeps the
+	// packet valid after the buffer is reused, e.g. by a zero-copy capture
+	// such as pcap.Handle.ZeroCopyReadPacketData. By default decoding does
+	// not copy.
+	CopyValues bool
+
+	// RejectUnknownCodes fails decoding of packets whose Code is not
+	// IsKnown, so that a non-RADIUS payload on a RADIUS port is reported as
+	// a decoding error. By default any Code is decoded, so that packet types
+	// newer than this package are not rejected.
+	RejectUnknownCodes bool
+}
+
+// defaultDecodeOptions are the options LayerTypeRADIUS decodes with.
+var defaultDecodeOptions DecodeOptions
+
+// SetDefaultDecodeOptions sets the options LayerTypeRADIUS decodes with, such
+// as the RADIUS payloads of the UDP ports in a capture decoded with
+// gopacket.NewPacket. Like the layer registrations of gopacket, it is not
+// safe for concurrent use with decoding and belongs in program
+// initialization.
+func SetDefaultDecodeOptions(opts DecodeOptions) {
+	defaultDecodeOptions = opts
+}
+
+// Decode decodes a RADIUS layer with the options, implementing
+// gopacket.Decoder.
+func (opts DecodeOptions) Decode(data []byte, p gopacket.PacketBuilder) error {
+	radius := &RADIUS{}
+	if err := radius.DecodeFromBytesWithOptions(data, p, opts); err != nil {
+		return err
+	}
+	p.AddLayer(radius)
+	p.SetApplicationLayer(radius)
+	if opts.AttributeLayers && len(radius.Attributes) > 0 {
+		radius.addAttributeLayers(p)
+	}
+	next := radius.NextLayerType()
+	if next == gopacket.LayerTypeZero {
+		return nil
+	}
+	return p.NextDecoder(next)
+}
+
+// DecodeHex decodes a bare RADIUS payload from a hex string, such as copied
+// from Wireshark. Whitespace, newlines, colons and commas between bytes and
+// 0x prefixes are ignored.
+func DecodeHex(hexString string) (*RADIUS, error) {
+	fields := strings.FieldsFunc(hexString, func(r rune) bool {
+		return r == ' ' || r == '\t' || r == '\r' || r == '\n' || r == ':' || r == ','
+	})
+	var b strings.Builder
+	for _, f := range fields {
+		if strings.HasPrefix(f, "0x") || strings.HasPrefix(f, "0X") {
+			f = f[2:]
+		}
+		b.WriteString(f)
+	}
+
+	data, err := hex.DecodeString(b.String())
+	if err != nil {
+		return nil, fmt.Errorf("RADIUS hex invalid: %s", err)
+	}
+	radius := &RADIUS{}
+	if err := radius.DecodeFromBytes(data, gopacket.NilDecodeFeedback); err != nil {
+		return nil, err
+	}
+	return radius, nil
+}
+
+// DecodeStream decodes the consecutive RADIUS packets of a stream transport
+// read, such as RadSec (RFC6614), framed by their Length field. It returns
+// the complete packets and the number of bytes they consumed; a trailing
+// partial packet is left for the caller to complete with the next read. The
+// packets are decoded with DecodeOptions.CopyValues, so that they stay valid
+// when the caller shifts or reuses data for that read. An invalid Length
+// cannot be resynchronized and is returned as an error.
+func DecodeStream(data []byte) ([]*RADIUS, int, error) {
+	var packets []*RADIUS
+	consumed := 0
+	for len(data)-consumed >= 4 {
+		n := int(binary.BigEndian.Uint16(data[consumed+2 : consumed+4]))
+		if n < radiusMinimumRecordSizeInBytes || n > radiusMaximumRecordSizeInBytes {
+			return packets, consumed, fmt.Errorf("RADIUS length %d invalid at offset %d", n, consumed)
+		}
+		if len(data)-consumed < n {
+			break
+		}
+
+		radius := &RADIUS{}
+		if err := radius.DecodeFromBytesWithOptions(data[consumed:consumed+n], gopacket.NilDecodeFeedback, DecodeOptions{CopyValues: true}); err != nil {
+			return packets, consumed, err
+		}
+		packets = append(packets, radius)
+		consumed += n
+	}
+	return packets, consumed, nil
+}
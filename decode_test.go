@@ -0,0 +1,139 @@
+package radius
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/gopacket"
+)
+
+func TestDecodeHex(t *testing.T) {
+	tests := []struct {
+		desc string
+		s    string
+	}{
+		{"Stream", "028d001486a8d5cd693c075e9e18a22ddd5f2bff"},
+		{"Spaces", "02 8d 00 14 86 a8 d5 cd 69 3c 07 5e 9e 18 a2 2d\ndd 5f 2b ff\n"},
+		{"Colons", "02:8d:00:14:86:a8:d5:cd:69:3c:07:5e:9e:18:a2:2d:dd:5f:2b:ff"},
+		{"GoBytes", "0x02, 0x8d, 0x00, 0x14, 0x86, 0xa8, 0xd5, 0xcd, 0x69, 0x3c, 0x07, 0x5e, 0x9e, 0x18, 0xa2, 0x2d,\r\n\t0xdd, 0x5f, 0x2b, 0xff,"},
+		{"Prefixed", "0x028d001486a8d5cd693c075e9e18a22ddd5f2bff"},
+	}
+
+	for _, tt := range tests {
+		radius, err := DecodeHex(tt.s)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.desc, err)
+			continue
+		}
+		if radius.Code != RADIUSCodeAccessAccept || !bytes.Equal(radius.Contents, testRADIUSAccessAccept) {
+			t.Errorf("%s: got %v %x", tt.desc, radius.Code, radius.Contents)
+		}
+	}
+
+	for _, s := range []string{"028d0014zz", "028d0014"} {
+		if _, err := DecodeHex(s); err == nil {
+			t.Errorf("%q: expected error", s)
+		}
+	}
+}
+
+func TestDecodeStream(t *testing.T) {
+	stream := append(append([]byte{}, testRADIUSAccessRequest...), testRADIUSAccessAccept...)
+
+	tests := []struct {
+		desc         string
+		data         []byte
+		wantCodes    []RADIUSCode
+		wantConsumed int
+		wantErr      bool
+	}{
+		{"Empty", nil, nil, 0, false},
+		{"Two", stream, []RADIUSCode{RADIUSCodeAccessRequest, RADIUSCodeAccessAccept}, len(stream), false},
+		{"PartialHeader", stream[:len(testRADIUSAccessRequest)+3], []RADIUSCode{RADIUSCodeAccessRequest}, len(testRADIUSAccessRequest), false},
+		{"PartialPacket", stream[:len(stream)-1], []RADIUSCode{RADIUSCodeAccessRequest}, len(testRADIUSAccessRequest), false},
+		{"InvalidLength", append(append([]byte{}, testRADIUSAccessAccept...), 0x02, 0x01, 0x00, 0x13), []RADIUSCode{RADIUSCodeAccessAccept}, len(testRADIUSAccessAccept), true},
+	}
+
+	for _, tt := range tests {
+		packets, consumed, err := DecodeStream(tt.data)
+		if tt.wantErr != (err != nil) {
+			t.Errorf("%s: got error %v want error %v", tt.desc, err, tt.wantErr)
+		}
+		if consumed != tt.wantConsumed {
+			t.Errorf("%s: consumed got %d want %d", tt.desc, consumed, tt.wantConsumed)
+		}
+		if len(packets) != len(tt.wantCodes) {
+			t.Errorf("%s: got %d packets want %d", tt.desc, len(packets), len(tt.wantCodes))
+			continue
+		}
+		for i, radius := range packets {
+			if radius.Code != tt.wantCodes[i] {
+				t.Errorf("%s: packet %d got %s want %s", tt.desc, i, radius.Code, tt.wantCodes[i])
+			}
+		}
+	}
+
+	packets, _, _ := DecodeStream(stream)
+	if attrs := packets[0].Attributes; len(attrs) == 0 || attrs[0].Type != RADIUSAttributeTypeUserName {
+		t.Errorf("Access-Request attributes got %v", attrs)
+	}
+	if len(packets[1].Attributes) != 0 {
+		t.Errorf("Access-Accept attributes got %v", packets[1].Attributes)
+	}
+
+	// the packets outlive the read buffer
+	buf := append([]byte(nil), stream...)
+	packets, _, err := DecodeStream(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range buf {
+		buf[i] = 0xff
+	}
+	if got := packets[0].Contents; !bytes.Equal(got, testRADIUSAccessRequest) {
+		t.Errorf("Access-Request contents after reuse got %x", got)
+	}
+	if got := packets[0].Attributes[0].Value; string(got) != "Admin" {
+		t.Errorf("User-Name after reuse got %q", got)
+	}
+	if got := packets[1].Contents; !bytes.Equal(got, testRADIUSAccessAccept) {
+		t.Errorf("Access-Accept contents after reuse got %x", got)
+	}
+}
+
+func TestDecodeOptionsCopyValues(t *testing.T) {
+	data := append([]byte(nil), testRADIUSAccessRequest...)
+	var copied, aliased RADIUS
+	if err := copied.DecodeFromBytesWithOptions(data, gopacket.NilDecodeFeedback, DecodeOptions{CopyValues: true}); err != nil {
+		t.Fatal(err)
+	}
+	parsed := RADIUS{Options: DecodeOptions{CopyValues: true}}
+	parser := gopacket.NewDecodingLayerParser(LayerTypeRADIUS, &parsed)
+	decoded := make([]gopacket.LayerType, 0, 1)
+	if err := parser.DecodeLayers(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if err := aliased.DecodeFromBytes(data, gopacket.NilDecodeFeedback); err != nil {
+		t.Fatal(err)
+	}
+
+	// reuse the buffer for the next packet
+	copy(data, bytes.Repeat([]byte{0xff}, len(data)))
+
+	want := decodeTestRADIUS(t, testRADIUSAccessRequest)
+	if !copied.Equal(want) || !bytes.Equal(copied.Contents, testRADIUSAccessRequest) {
+		t.Errorf("copied values got %v want %v", copied.Attributes, want.Attributes)
+	}
+	if !parsed.Equal(want) {
+		t.Errorf("DecodingLayerParser copied values got %v want %v", parsed.Attributes, want.Attributes)
+	}
+	if string(aliased.Attributes[0].Value) == "Admin" {
+		t.Error("default decoding copied the values")
+	}
+
+	// each value has an allocation of its own
+	copied.Attributes[0].Value = append(copied.Attributes[0].Value, '!')
+	if !bytes.Equal(copied.Attributes[1].Value, want.Attributes[1].Value) {
+		t.Errorf("appending to a value overwrote the next one: got %x", copied.Attributes[1].Value)
+	}
+}
@@ -0,0 +1,257 @@
+package radius
+
+import (
+	"fmt"
+)
+
+// AttrValueType represents the data type of an attribute value.
+type AttrValueType uint8
+
+// constants that define AttrValueType.
+const (
+	AttrValueTypeOctets     AttrValueType = iota // RFC8044 3.7.  octets
+	AttrValueTypeString                          // RFC8044 3.5.  text
+	AttrValueTypeInteger                         // RFC8044 3.1.  integer
+	AttrValueTypeIPAddr                          // RFC8044 3.8.  ipv4addr
+	AttrValueTypeDate                            // RFC8044 3.3.  time
+	AttrValueTypeIPv6Addr                        // RFC8044 3.9.  ipv6addr
+	AttrValueTypeIPv6Prefix                      // RFC8044 3.10. ipv6prefix
+)
+
+// String returns a string version of a AttrValueType.
+func (t AttrValueType) String() (s string) {
+	switch t {
+	case AttrValueTypeOctets:
+		s = "octets"
+	case AttrValueTypeString:
+		s = "string"
+	case AttrValueTypeInteger:
+		s = "integer"
+	case AttrValueTypeIPAddr:
+		s = "ipaddr"
+	case AttrValueTypeDate:
+		s = "date"
+	case AttrValueTypeIPv6Addr:
+		s = "ipv6addr"
+	case AttrValueTypeIPv6Prefix:
+		s = "ipv6prefix"
+	default:
+		s = fmt.Sprintf("Unknown(%d)", t)
+	}
+	return
+}
+
+// ValueType returns the data type of the value carried by a RADIUSAttributeType.
+// Unknown attribute types which are not registered with RegisterAttribute are
+// reported as AttrValueTypeOctets.
+func (t RADIUSAttributeType) ValueType() (v AttrValueType) {
+	switch t {
+	case RADIUSAttributeTypeUserName,
+		RADIUSAttributeTypeFilterId,
+		RADIUSAttributeTypeReplyMessage,
+		RADIUSAttributeTypeCallbackNumber,
+		RADIUSAttributeTypeCallbackId,
+		RADIUSAttributeTypeFramedRoute,
+		RADIUSAttributeTypeCalledStationId,
+		RADIUSAttributeTypeCallingStationId,
+		RADIUSAttributeTypeNASIdentifier,
+		RADIUSAttributeTypeLoginLATService,
+		RADIUSAttributeTypeLoginLATNode,
+		RADIUSAttributeTypeLoginLATPort,
+		RADIUSAttributeTypeFramedAppleTalkZone,
+		RADIUSAttributeTypeAcctSessionId,
+		RADIUSAttributeTypeAcctMultiSessionId,
+		RADIUSAttributeTypeTunnelClientEndpoint,
+		RADIUSAttributeTypeTunnelServerEndpoint,
+		RADIUSAttributeTypeAcctTunnelConnection,
+		RADIUSAttributeTypeConnectInfo,
+		RADIUSAttributeTypeConfigurationToken,
+		RADIUSAttributeTypeTunnelPrivateGroupID,
+		RADIUSAttributeTypeTunnelAssignmentID,
+		RADIUSAttributeTypeNASPortId,
+		RADIUSAttributeTypeFramedPool,
+		RADIUSAttributeTypeEgressVLANName,
+		RADIUSAttributeTypeDelegatedIPv6PrefixPool,
+		RADIUSAttributeTypeStatefulIPv6AddressPool,
+		RADIUSAttributeTypeTunnelClientAuthID,
+		RADIUSAttributeTypeTunnelServerAuthID,
+		RADIUSAttributeTypeNASFilterRule,
+		RADIUSAttributeTypeFramedIPv6Route,
+		RADIUSAttributeTypeFramedIPv6Pool,
+		RADIUSAttributeTypeDigestResponse,
+		RADIUSAttributeTypeDigestRealm,
+		RADIUSAttributeTypeDigestNonce,
+		RADIUSAttributeTypeDigestResponseAuth,
+		RADIUSAttributeTypeDigestNextnonce,
+		RADIUSAttributeTypeDigestMethod,
+		RADIUSAttributeTypeDigestURI,
+		RADIUSAttributeTypeDigestQop,
+		RADIUSAttributeTypeDigestAlgorithm,
+		RADIUSAttributeTypeDigestEntityBodyHash,
+		RADIUSAttributeTypeDigestCNonce,
+		RADIUSAttributeTypeDigestNonceCount,
+		RADIUSAttributeTypeDigestUsername,
+		RADIUSAttributeTypeDigestOpaque,
+		RADIUSAttributeTypeDigestAuthParam,
+		RADIUSAttributeTypeDigestAKAAuts,
+		RADIUSAttributeTypeDigestDomain,
+		RADIUSAttributeTypeDigestStale,
+		RADIUSAttributeTypeDigestHA1:
+		v = AttrValueTypeString
+	case RADIUSAttributeTypeNASPort,
+		RADIUSAttributeTypeServiceType,
+		RADIUSAttributeTypeFramedProtocol,
+		RADIUSAttributeTypeFramedRouting,
+		RADIUSAttributeTypeFramedMTU,
+		RADIUSAttributeTypeFramedCompression,
+		RADIUSAttributeTypeLoginService,
+		RADIUSAttributeTypeLoginTCPPort,
+		RADIUSAttributeTypeFramedIPXNetwork,
+		RADIUSAttributeTypeSessionTimeout,
+		RADIUSAttributeTypeIdleTimeout,
+		RADIUSAttributeTypeTerminationAction,
+		RADIUSAttributeTypeFramedAppleTalkLink,
+		RADIUSAttributeTypeFramedAppleTalkNetwork,
+		RADIUSAttributeTypeAcctStatusType,
+		RADIUSAttributeTypeAcctDelayTime,
+		RADIUSAttributeTypeAcctInputOctets,
+		RADIUSAttributeTypeAcctOutputOctets,
+		RADIUSAttributeTypeAcctAuthentic,
+		RADIUSAttributeTypeAcctSessionTime,
+		RADIUSAttributeTypeAcctInputPackets,
+		RADIUSAttributeTypeAcctOutputPackets,
+		RADIUSAttributeTypeAcctTerminateCause,
+		RADIUSAttributeTypeAcctLinkCount,
+		RADIUSAttributeTypeAcctInputGigawords,
+		RADIUSAttributeTypeAcctOutputGigawords,
+		RADIUSAttributeTypeEgressVLANID,
+		RADIUSAttributeTypeIngressFilters,
+		RADIUSAttributeTypeNASPortType,
+		RADIUSAttributeTypePortLimit,
+		RADIUSAttributeTypeTunnelType,
+		RADIUSAttributeTypeTunnelMediumType,
+		RADIUSAttributeTypeARAPZoneAccess,
+		RADIUSAttributeTypeARAPSecurity,
+		RADIUSAttributeTypePasswordRetry,
+		RADIUSAttributeTypePrompt,
+		RADIUSAttributeTypeTunnelPreference,
+		RADIUSAttributeTypeAcctInterimInterval,
+		RADIUSAttributeTypeAcctTunnelPacketsLost,
+		RADIUSAttributeTypeErrorCause:
+		v = AttrValueTypeInteger
+	case RADIUSAttributeTypeNASIPAddress,
+		RADIUSAttributeTypeFramedIPAddress,
+		RADIUSAttributeTypeFramedIPNetmask,
+		RADIUSAttributeTypeLoginIPHost:
+		v = AttrValueTypeIPAddr
+	case RADIUSAttributeTypeNASIPv6Address,
+		RADIUSAttributeTypeFramedIPv6Address,
+		RADIUSAttributeTypeDNSServerIPv6Address,
+		RADIUSAttributeTypeLoginIPv6Host:
+		v = AttrValueTypeIPv6Addr
+	case RADIUSAttributeTypeFramedIPv6Prefix,
+		RADIUSAttributeTypeRouteIPv6Information:
+		v = AttrValueTypeIPv6Prefix
+	case RADIUSAttributeTypeEventTimestamp:
+		v = AttrValueTypeDate
+	default:
+		if r, ok := registeredAttribute(t); ok {
+			v = r.valueType
+		} else {
+			v = AttrValueTypeOctets
+		}
+	}
+	return
+}
+
+// IsTagged reports whether a RADIUSAttributeType carries the RFC2868 tag
+// field grouping attributes that belong to the same tunnel.
+func (t RADIUSAttributeType) IsTagged() bool {
+	switch t {
+	case RADIUSAttributeTypeTunnelType,
+		RADIUSAttributeTypeTunnelMediumType,
+		RADIUSAttributeTypeTunnelClientEndpoint,
+		RADIUSAttributeTypeTunnelServerEndpoint,
+		RADIUSAttributeTypeTunnelPassword,
+		RADIUSAttributeTypeTunnelPrivateGroupID,
+		RADIUSAttributeTypeTunnelAssignmentID,
+		RADIUSAttributeTypeTunnelPreference,
+		RADIUSAttributeTypeTunnelClientAuthID,
+		RADIUSAttributeTypeTunnelServerAuthID:
+		return true
+	default:
+		return false
+	}
+}
+
+// validateValueType checks the value length against the value type of the
+// attribute type.
+func (a RADIUSAttribute) validateValueType() error {
+	var want int
+	switch a.Type.ValueType() {
+	case AttrValueTypeInteger, AttrValueTypeIPAddr, AttrValueTypeDate:
+		want = 4
+	case AttrValueTypeIPv6Addr:
+		want = 16
+	case AttrValueTypeIPv6Prefix:
+		if len(a.Value) < 2 || len(a.Value) > 18 {
+			return fmt.Errorf("RADIUS %s %s length %d invalid", a.Type, a.Type.ValueType(), len(a.Value))
+		}
+		return nil
+	default:
+		return nil
+	}
+	if len(a.Value) != want {
+		return fmt.Errorf("RADIUS %s %s length %d invalid, want %d", a.Type, a.Type.ValueType(), len(a.Value), want)
+	}
+	return nil
+}
+
+// lengthRange returns the minimum and maximum value length of a
+// RADIUSAttributeType: fixed for integer, ipaddr, date and ipv6addr values
+// and the attributes of RFC2865 with a fixed length, and 1 to 253 bytes for
+// variable length values.
+func (t RADIUSAttributeType) lengthRange() (min, max int) {
+	switch t {
+	case RADIUSAttributeTypeUserPassword:
+		return 16, 128 // RFC2865 5.2
+	case RADIUSAttributeTypeCHAPPassword:
+		return 17, 17 // RFC2865 5.3
+	case RADIUSAttributeTypeCHAPChallenge:
+		return 5, radiusMaximumAttributeValueSizeInBytes // RFC2865 5.40
+	case RADIUSAttributeTypeMessageAuthenticator:
+		return 16, 16 // RFC2869 5.14
+	case RADIUSAttributeTypeTunnelPassword:
+		return 3, radiusMaximumAttributeValueSizeInBytes // RFC2868 3.5
+	case RADIUSAttributeTypeFramedInterfaceId:
+		return 8, 8 // RFC3162 2.2
+	}
+
+	switch t.ValueType() {
+	case AttrValueTypeInteger, AttrValueTypeIPAddr, AttrValueTypeDate:
+		return 4, 4
+	case AttrValueTypeIPv6Addr:
+		return 16, 16
+	case AttrValueTypeIPv6Prefix:
+		return 2, 18
+	default:
+		return 1, radiusMaximumAttributeValueSizeInBytes
+	}
+}
+
+// ValidateLength checks the value length against the expected length of the
+// attribute type, telling a truncated value, such as a 3 bytes
+// NAS-IP-Address, from a legitimately short one.
+func (a RADIUSAttribute) ValidateLength() error {
+	min, max := a.Type.lengthRange()
+	n := len(a.Value)
+	switch {
+	case min == max && n != min:
+		return fmt.Errorf("RADIUS %s length %d invalid, want %d", a.Type, n, min)
+	case n < min:
+		return fmt.Errorf("RADIUS %s length %d too short, want at least %d", a.Type, n, min)
+	case n > max:
+		return fmt.Errorf("RADIUS %s length %d too long, want at most %d", a.Type, n, max)
+	}
+	return nil
+}
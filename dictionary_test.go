@@ -0,0 +1,58 @@
+package radius
+
+import (
+	"testing"
+)
+
+func TestRADIUSAttributeTypeLoginLAT(t *testing.T) {
+	tests := []struct {
+		attr      RADIUSAttributeType
+		name      string
+		valueType AttrValueType
+	}{
+		{RADIUSAttributeTypeLoginLATService, "Login-LAT-Service", AttrValueTypeString},
+		{RADIUSAttributeTypeLoginLATNode, "Login-LAT-Node", AttrValueTypeString},
+		{RADIUSAttributeTypeLoginLATGroup, "Login-LAT-Group", AttrValueTypeOctets},
+		{RADIUSAttributeTypeLoginLATPort, "Login-LAT-Port", AttrValueTypeString},
+	}
+
+	for _, tt := range tests {
+		if got := tt.attr.String(); got != tt.name {
+			t.Errorf("RADIUSAttributeType(%d).String() got %q want %q", tt.attr, got, tt.name)
+		}
+		if got := tt.attr.ValueType(); got != tt.valueType {
+			t.Errorf("RADIUSAttributeType(%d).ValueType() got %v want %v", tt.attr, got, tt.valueType)
+		}
+	}
+}
+
+func TestRADIUSAttributeValidateLength(t *testing.T) {
+	tests := []struct {
+		desc    string
+		attr    RADIUSAttribute
+		wantErr bool
+	}{
+		{"NAS-IP-Address", newAttribute(RADIUSAttributeTypeNASIPAddress, []byte{127, 0, 1, 1}), false},
+		{"NAS-IP-Address truncated", newAttribute(RADIUSAttributeTypeNASIPAddress, []byte{127, 0, 1}), true},
+		{"Tunnel-Preference", newAttribute(RADIUSAttributeTypeTunnelPreference, []byte{0x01, 0x00, 0x00, 0x0a}), false},
+		{"Framed-IPv6-Address truncated", newAttribute(RADIUSAttributeTypeFramedIPv6Address, make([]byte, 8)), true},
+		{"Route-IPv6-Information", newAttribute(RADIUSAttributeTypeRouteIPv6Information, []byte{0x00, 0x00}), false},
+		{"User-Name", newAttribute(RADIUSAttributeTypeUserName, []byte("A")), false},
+		{"User-Name empty", newAttribute(RADIUSAttributeTypeUserName, nil), true},
+		{"User-Password", newAttribute(RADIUSAttributeTypeUserPassword, make([]byte, 32)), false},
+		{"User-Password short", newAttribute(RADIUSAttributeTypeUserPassword, make([]byte, 8)), true},
+		{"User-Password long", newAttribute(RADIUSAttributeTypeUserPassword, make([]byte, 144)), true},
+		{"CHAP-Password", newAttribute(RADIUSAttributeTypeCHAPPassword, make([]byte, 16)), true},
+		{"Message-Authenticator", newAttribute(RADIUSAttributeTypeMessageAuthenticator, make([]byte, 16)), false},
+		{"State long", newAttribute(RADIUSAttributeTypeState, make([]byte, 254)), true},
+	}
+
+	for _, tt := range tests {
+		err := tt.attr.ValidateLength()
+		if tt.wantErr && err == nil {
+			t.Errorf("%s: expected error", tt.desc)
+		} else if !tt.wantErr && err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.desc, err)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package radius
+
+import (
+	"fmt"
+)
+
+// diffAttribute is an attribute, or a Vendor-Specific sub-attribute,
+// flattened for DiffRADIUS.
+type diffAttribute struct {
+	key   string
+	name  string
+	value string
+}
+
+// DiffRADIUS returns the differences between the packets a and b, one line
+// per changed header field or attribute, e.g. when comparing a request before
+// and after a proxy. Attributes are matched by type and occurrence.
+// Vendor-Specific attributes are compared by the vendor and vendor type of
+// their sub-attributes, so a single modified sub-attribute is reported as
+// e.g. `Cisco-AVPair changed: "shell:priv-lvl=1" -> "shell:priv-lvl=15"`.
+func DiffRADIUS(a, b *RADIUS) []string {
+	var diffs []string
+	if a.Code != b.Code {
+		diffs = append(diffs, fmt.Sprintf("Code changed: %s -> %s", a.Code, b.Code))
+	}
+	if a.Identifier != b.Identifier {
+		diffs = append(diffs, fmt.Sprintf("Identifier changed: %d -> %d", a.Identifier, b.Identifier))
+	}
+	if a.Authenticator != b.Authenticator {
+		diffs = append(diffs, fmt.Sprintf("Authenticator changed: %s -> %s", a.Authenticator, b.Authenticator))
+	}
+
+	before, after := diffAttributes(a), diffAttributes(b)
+	var keys []string
+	seen := make(map[string]bool)
+	for _, attrs := range [][]diffAttribute{before, after} {
+		for _, v := range attrs {
+			if !seen[v.key] {
+				seen[v.key] = true
+				keys = append(keys, v.key)
+			}
+		}
+	}
+
+	for _, key := range keys {
+		x, y := diffAttributesByKey(before, key), diffAttributesByKey(after, key)
+		for i := 0; i < len(x) || i < len(y); i++ {
+			switch {
+			case i >= len(y):
+				diffs = append(diffs, fmt.Sprintf("%s removed: %s", x[i].name, x[i].value))
+			case i >= len(x):
+				diffs = append(diffs, fmt.Sprintf("%s added: %s", y[i].name, y[i].value))
+			case x[i].value != y[i].value:
+				diffs = append(diffs, fmt.Sprintf("%s changed: %s -> %s", x[i].name, x[i].value, y[i].value))
+			}
+		}
+	}
+	return diffs
+}
+
+// diffAttributes flattens the attributes of radius, replacing well-formed
+// Vendor-Specific attributes with their sub-attributes.
+func diffAttributes(radius *RADIUS) []diffAttribute {
+	var attrs []diffAttribute
+	for _, v := range radius.Attributes {
+		if v.Type == RADIUSAttributeTypeVendorSpecific {
+			if vsa, err := v.VendorSpecific(); err == nil {
+				for _, sub := range vsa.Attributes {
+					attrs = append(attrs, diffAttribute{
+						key:   fmt.Sprintf("%d:%d:%d", v.Type, vsa.VendorID, sub.Type),
+						name:  VendorAttributeName(vsa.VendorID, sub.Type),
+						value: diffValue(VendorAttributeValueType(vsa.VendorID, sub.Type), sub.Value),
+					})
+				}
+				continue
+			}
+		}
+
+		attrs = append(attrs, diffAttribute{
+			key:   fmt.Sprintf("%d", v.Type),
+			name:  v.Type.String(),
+			value: v.formatValue(""),
+		})
+	}
+	return attrs
+}
+
+// diffValue formats a vendor sub-attribute value for DiffRADIUS.
+func diffValue(valueType AttrValueType, value RADIUSAttributeValue) string {
+	if valueType == AttrValueTypeString {
+		return fmt.Sprintf("%q", string(value))
+	}
+	return fmt.Sprintf("%x", []byte(value))
+}
+
+// diffAttributesByKey returns the attributes of attrs with the given key.
+func diffAttributesByKey(attrs []diffAttribute, key string) []diffAttribute {
+	var matched []diffAttribute
+	for _, v := range attrs {
+		if v.key == key {
+			matched = append(matched, v)
+		}
+	}
+	return matched
+}
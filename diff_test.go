@@ -0,0 +1,45 @@
+package radius
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffRADIUS(t *testing.T) {
+	vsa := func(pairs ...string) []byte {
+		value := []byte{0x00, 0x00, 0x00, 0x09}
+		for _, pair := range pairs {
+			value = append(value, RADIUSCiscoAttributeTypeAVPair, byte(len(pair)+2))
+			value = append(value, pair...)
+		}
+		return value
+	}
+
+	a := NewRADIUS(RADIUSCodeAccessAccept, 1).
+		AddRaw(RADIUSAttributeTypeUserName, []byte("alice")).
+		AddRaw(RADIUSAttributeTypeSessionTimeout, uint32Value(3600)).
+		AddRaw(RADIUSAttributeTypeVendorSpecific, vsa("shell:priv-lvl=1", "ip:inacl#1=permit")).
+		AddRaw(RADIUSAttributeTypeClass, []byte{0x01, 0x02}).
+		Build()
+	b := NewRADIUS(RADIUSCodeAccessAccept, 2).
+		AddRaw(RADIUSAttributeTypeUserName, []byte("alice")).
+		AddRaw(RADIUSAttributeTypeSessionTimeout, uint32Value(600)).
+		AddRaw(RADIUSAttributeTypeVendorSpecific, vsa("shell:priv-lvl=15", "ip:inacl#1=permit")).
+		AddRaw(RADIUSAttributeTypeReplyMessage, []byte("Welcome")).
+		Build()
+
+	want := []string{
+		"Identifier changed: 1 -> 2",
+		"Session-Timeout changed: 3600 -> 600",
+		`Cisco-AVPair changed: "shell:priv-lvl=1" -> "shell:priv-lvl=15"`,
+		"Class removed: 0102",
+		`Reply-Message added: "Welcome"`,
+	}
+	if got := DiffRADIUS(a, b); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q want %q", got, want)
+	}
+
+	if got := DiffRADIUS(a, a); len(got) != 0 {
+		t.Errorf("Same: got %q want none", got)
+	}
+}
@@ -0,0 +1,100 @@
+package radius
+
+import (
+	"fmt"
+)
+
+// constants that define Digest-Attributes sub-attribute types.
+const (
+	RADIUSDigestAttributeTypeRealm      uint8 = 1  // draft-sterman-aaa-sip-00 Digest-Realm
+	RADIUSDigestAttributeTypeNonce      uint8 = 2  // draft-sterman-aaa-sip-00 Digest-Nonce
+	RADIUSDigestAttributeTypeMethod     uint8 = 3  // draft-sterman-aaa-sip-00 Digest-Method
+	RADIUSDigestAttributeTypeURI        uint8 = 4  // draft-sterman-aaa-sip-00 Digest-URI
+	RADIUSDigestAttributeTypeQOP        uint8 = 5  // draft-sterman-aaa-sip-00 Digest-QOP
+	RADIUSDigestAttributeTypeAlgorithm  uint8 = 6  // draft-sterman-aaa-sip-00 Digest-Algorithm
+	RADIUSDigestAttributeTypeBodyDigest uint8 = 7  // draft-sterman-aaa-sip-00 Digest-Body-Digest
+	RADIUSDigestAttributeTypeCNonce     uint8 = 8  // draft-sterman-aaa-sip-00 Digest-CNonce
+	RADIUSDigestAttributeTypeNonceCount uint8 = 9  // draft-sterman-aaa-sip-00 Digest-Nonce-Count
+	RADIUSDigestAttributeTypeUserName   uint8 = 10 // draft-sterman-aaa-sip-00 Digest-User-Name
+)
+
+// DigestAttributeName returns the name of a Digest-Attributes sub-attribute
+// type.
+func DigestAttributeName(t uint8) (s string) {
+	switch t {
+	case RADIUSDigestAttributeTypeRealm:
+		s = "Digest-Realm"
+	case RADIUSDigestAttributeTypeNonce:
+		s = "Digest-Nonce"
+	case RADIUSDigestAttributeTypeMethod:
+		s = "Digest-Method"
+	case RADIUSDigestAttributeTypeURI:
+		s = "Digest-URI"
+	case RADIUSDigestAttributeTypeQOP:
+		s = "Digest-QOP"
+	case RADIUSDigestAttributeTypeAlgorithm:
+		s = "Digest-Algorithm"
+	case RADIUSDigestAttributeTypeBodyDigest:
+		s = "Digest-Body-Digest"
+	case RADIUSDigestAttributeTypeCNonce:
+		s = "Digest-CNonce"
+	case RADIUSDigestAttributeTypeNonceCount:
+		s = "Digest-Nonce-Count"
+	case RADIUSDigestAttributeTypeUserName:
+		s = "Digest-User-Name"
+	default:
+		s = fmt.Sprintf("Unknown(%d)", t)
+	}
+	return
+}
+
+// RADIUSDigestAttribute represents a sub-attribute of a Digest-Attributes
+// attribute.
+type RADIUSDigestAttribute struct {
+	Type   uint8
+	Length uint8
+	Value  RADIUSAttributeValue
+}
+
+// DigestSubAttributes decodes a Digest-Attributes attribute, the container of
+// the SIP Digest parameters used before RFC5090, into its Type/Length/Value
+// sub-attributes. The values refer to the attribute value.
+func (a RADIUSAttribute) DigestSubAttributes() ([]RADIUSDigestAttribute, error) {
+	if a.Type != RADIUSAttributeTypeDigestAttributes {
+		return nil, fmt.Errorf("RADIUS attribute %s is not %s", a.Type, RADIUSAttributeTypeDigestAttributes)
+	}
+	var subs []RADIUSDigestAttribute
+	data := a.Value
+	for len(data) > 0 {
+		if len(data) < 2 {
+			return nil, fmt.Errorf("RADIUS %s sub-attribute header truncated", a.Type)
+		}
+		n := int(data[1])
+		if n < 2 || n > len(data) {
+			return nil, fmt.Errorf("RADIUS %s sub-attribute length %d invalid", a.Type, n)
+		}
+		subs = append(subs, RADIUSDigestAttribute{
+			Type:   data[0],
+			Length: data[1],
+			Value:  data[2:n],
+		})
+		data = data[n:]
+	}
+	return subs, nil
+}
+
+// DigestAttributes returns the text values of the RFC5090 attributes of the
+// packet, Digest-Response through Digest-HA1, keyed by attribute type. The
+// first attribute of each type is used, e.g. of several Digest-Domain.
+func (radius *RADIUS) DigestAttributes() map[RADIUSAttributeType]string {
+	values := make(map[RADIUSAttributeType]string)
+	for _, v := range radius.Attributes {
+		if v.Type < RADIUSAttributeTypeDigestResponse || v.Type > RADIUSAttributeTypeDigestHA1 {
+			continue
+		}
+		if _, ok := values[v.Type]; !ok {
+			values[v.Type] = v.Value.Text()
+		}
+	}
+	return values
+}
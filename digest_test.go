@@ -0,0 +1,80 @@
+package radius
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRADIUSDigestAttributes(t *testing.T) {
+	data, err := NewRADIUS(RADIUSCodeAccessRequest, 0x01).
+		AddString(RADIUSAttributeTypeUserName, "alice").
+		AddString(RADIUSAttributeTypeDigestRealm, "example.com").
+		AddString(RADIUSAttributeTypeDigestNonce, "dcd98b7102dd2f0e8b11d0f600bfb0c093").
+		AddString(RADIUSAttributeTypeDigestDomain, "sip:example.com").
+		AddString(RADIUSAttributeTypeDigestDomain, "sip:example.net").
+		Build().serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	radius := decodeTestRADIUS(t, data)
+
+	want := map[RADIUSAttributeType]string{
+		RADIUSAttributeTypeDigestRealm:  "example.com",
+		RADIUSAttributeTypeDigestNonce:  "dcd98b7102dd2f0e8b11d0f600bfb0c093",
+		RADIUSAttributeTypeDigestDomain: "sip:example.com",
+	}
+	if got := radius.DigestAttributes(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+	if got := (&RADIUS{}).DigestAttributes(); len(got) != 0 {
+		t.Errorf("no attributes: got %v", got)
+	}
+
+	for _, tt := range []struct {
+		t    RADIUSAttributeType
+		want string
+	}{
+		{RADIUSAttributeTypeDigestResponse, "Digest-Response"},
+		{RADIUSAttributeTypeDigestRealm, "Digest-Realm"},
+		{RADIUSAttributeTypeDigestNonce, "Digest-Nonce"},
+		{RADIUSAttributeTypeDigestHA1, "Digest-HA1"},
+		{RADIUSAttributeTypeDigestAttributes, "Digest-Attributes"},
+	} {
+		if s := tt.t.String(); s != tt.want {
+			t.Errorf("RADIUSAttributeType(%d).String() got %q want %q", uint8(tt.t), s, tt.want)
+		}
+	}
+	if v := RADIUSAttributeTypeDigestNonce.ValueType(); v != AttrValueTypeString {
+		t.Errorf("Digest-Nonce value type got %s want string", v)
+	}
+}
+
+func TestRADIUSAttributeDigestSubAttributes(t *testing.T) {
+	attr := newAttribute(RADIUSAttributeTypeDigestAttributes, []byte("\x01\x0dexample.com\x02\x05abc\x03\x08INVITE"))
+	subs, err := attr.DigestSubAttributes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []RADIUSDigestAttribute{
+		{Type: RADIUSDigestAttributeTypeRealm, Length: 13, Value: RADIUSAttributeValue("example.com")},
+		{Type: RADIUSDigestAttributeTypeNonce, Length: 5, Value: RADIUSAttributeValue("abc")},
+		{Type: RADIUSDigestAttributeTypeMethod, Length: 8, Value: RADIUSAttributeValue("INVITE")},
+	}
+	if !reflect.DeepEqual(subs, want) {
+		t.Errorf("got %v want %v", subs, want)
+	}
+	if s := DigestAttributeName(subs[0].Type); s != "Digest-Realm" {
+		t.Errorf("name got %q want Digest-Realm", s)
+	}
+
+	for _, a := range []RADIUSAttribute{
+		newAttribute(RADIUSAttributeTypeDigestAttributes, []byte("\x01")),
+		newAttribute(RADIUSAttributeTypeDigestAttributes, []byte("\x01\x0dexample")),
+		newAttribute(RADIUSAttributeTypeDigestAttributes, []byte("\x01\x01")),
+		newAttribute(RADIUSAttributeTypeDigestRealm, []byte("\x01\x03a")),
+	} {
+		if _, err := a.DigestSubAttributes(); err == nil {
+			t.Errorf("%s: expected error", a)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package radius
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+const hexDumpBytesPerLine = 16
+
+// HexDump returns an annotated hex dump of the packet in its wire format,
+// with the header fields and each attribute on their own lines. Values longer
+// than a line wrap on the following lines.
+func (radius *RADIUS) HexDump() string {
+	var b bytes.Buffer
+	offset := 0
+	dump := func(data []byte, label string) {
+		for len(data) > 0 || label != "" {
+			n := len(data)
+			if n > hexDumpBytesPerLine {
+				n = hexDumpBytesPerLine
+			}
+			line := fmt.Sprintf("% x", data[:n])
+			if label == "" {
+				fmt.Fprintf(&b, "%04x  %s\n", offset, line)
+			} else {
+				fmt.Fprintf(&b, "%04x  %-*s  %s\n", offset, hexDumpBytesPerLine*3-1, line, label)
+			}
+			offset += n
+			data = data[n:]
+			label = ""
+		}
+	}
+
+	length := make([]byte, 2)
+	binary.BigEndian.PutUint16(length, uint16(radius.Length))
+	dump([]byte{byte(radius.Code)}, fmt.Sprintf("Code: %s (%d)", radius.Code, radius.Code))
+	dump([]byte{byte(radius.Identifier)}, fmt.Sprintf("Identifier: %d", radius.Identifier))
+	dump(length, fmt.Sprintf("Length: %d", radius.Length))
+	dump(radius.Authenticator[:], "Authenticator")
+	for _, v := range radius.Attributes {
+		data := append([]byte{byte(v.Type), byte(v.Length)}, v.Value...)
+		dump(data, fmt.Sprintf("%s (%d), Length: %d", v.Type, v.Type, v.Length))
+	}
+	return b.String()
+}
@@ -0,0 +1,24 @@
+package radius
+
+import (
+	"testing"
+)
+
+func TestRADIUSHexDump(t *testing.T) {
+	radius := decodeTestRADIUS(t, testRADIUSAccessRequest)
+	want := "" +
+		"0000  01                                               Code: Access-Request (1)\n" +
+		"0001  8d                                               Identifier: 141\n" +
+		"0002  00 4b                                            Length: 75\n" +
+		"0004  3b bd 22 52 b4 c8 d8 44 1b 46 79 bf 4a 2b 86 01  Authenticator\n" +
+		"0014  01 07 41 64 6d 69 6e                             User-Name (1), Length: 7\n" +
+		"001b  02 12 4d 2f 62 0b 33 9d 6d 1f e0 e4 6d 1f 9b da  User-Password (2), Length: 18\n" +
+		"002b  ff f0\n" +
+		"002d  04 06 7f 00 01 01                                NAS-IP-Address (4), Length: 6\n" +
+		"0033  05 06 00 00 00 00                                NAS-Port (5), Length: 6\n" +
+		"0039  50 12 41 73 ed 26 d3 b3 a9 64 ff 4d c3 0d 94 33  Message-Authenticator (80), Length: 18\n" +
+		"0049  e8 2a\n"
+	if got := radius.HexDump(); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
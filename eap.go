@@ -0,0 +1,113 @@
+package radius
+
+import (
+	"fmt"
+)
+
+// EAPType represents the method Type of an EAP Request or Response.
+type EAPType uint8
+
+// constants that define EAPType.
+const (
+	EAPTypeIdentity     EAPType = 1   // RFC3748 5.1.  Identity
+	EAPTypeNotification EAPType = 2   // RFC3748 5.2.  Notification
+	EAPTypeNak          EAPType = 3   // RFC3748 5.3.  Nak
+	EAPTypeMD5Challenge EAPType = 4   // RFC3748 5.4.  MD5-Challenge
+	EAPTypeOTP          EAPType = 5   // RFC3748 5.5.  One-Time Password
+	EAPTypeGTC          EAPType = 6   // RFC3748 5.6.  Generic Token Card
+	EAPTypeTLS          EAPType = 13  // RFC5216       EAP-TLS
+	EAPTypeLEAP         EAPType = 17  //               Cisco LEAP
+	EAPTypeSIM          EAPType = 18  // RFC4186       EAP-SIM
+	EAPTypeTTLS         EAPType = 21  // RFC5281       EAP-TTLS
+	EAPTypeAKA          EAPType = 23  // RFC4187       EAP-AKA
+	EAPTypePEAP         EAPType = 25  //               PEAP
+	EAPTypeMSCHAPv2     EAPType = 26  //               EAP-MSCHAPv2
+	EAPTypeFAST         EAPType = 43  // RFC4851       EAP-FAST
+	EAPTypeAKAPrime     EAPType = 50  // RFC5448       EAP-AKA'
+	EAPTypeTEAP         EAPType = 55  // RFC7170       TEAP
+	EAPTypeExpanded     EAPType = 254 // RFC3748 5.7.  Expanded Types
+)
+
+// String returns a string version of a EAPType.
+func (t EAPType) String() (s string) {
+	switch t {
+	case EAPTypeIdentity:
+		s = "Identity"
+	case EAPTypeNotification:
+		s = "Notification"
+	case EAPTypeNak:
+		s = "Nak"
+	case EAPTypeMD5Challenge:
+		s = "MD5-Challenge"
+	case EAPTypeOTP:
+		s = "OTP"
+	case EAPTypeGTC:
+		s = "GTC"
+	case EAPTypeTLS:
+		s = "TLS"
+	case EAPTypeLEAP:
+		s = "LEAP"
+	case EAPTypeSIM:
+		s = "SIM"
+	case EAPTypeTTLS:
+		s = "TTLS"
+	case EAPTypeAKA:
+		s = "AKA"
+	case EAPTypePEAP:
+		s = "PEAP"
+	case EAPTypeMSCHAPv2:
+		s = "MSCHAPv2"
+	case EAPTypeFAST:
+		s = "FAST"
+	case EAPTypeAKAPrime:
+		s = "AKA'"
+	case EAPTypeTEAP:
+		s = "TEAP"
+	case EAPTypeExpanded:
+		s = "Expanded"
+	default:
+		s = fmt.Sprintf("Unknown(%d)", t)
+	}
+	return
+}
+
+// EAP Codes, RFC3748 4.
+const (
+	eapCodeRequest  = 1
+	eapCodeResponse = 2
+)
+
+// EAPMessage returns the EAP packet carried by the packet, reassembled from
+// its EAP-Message attributes in the order of the packet (RFC3579 3.1). It
+// returns false if there are none.
+func (radius *RADIUS) EAPMessage() ([]byte, bool) {
+	var eap []byte
+	var ok bool
+	for _, v := range radius.Attributes {
+		if v.Type == RADIUSAttributeTypeEAPMessage {
+			eap = append(eap, v.Value...)
+			ok = true
+		}
+	}
+	return eap, ok
+}
+
+// AddEAPMessage appends eap as EAP-Message attributes of at most 253 bytes
+// each, the reverse of EAPMessage.
+func (b *RADIUSBuilder) AddEAPMessage(eap []byte) *RADIUSBuilder {
+	return b.addSplit(RADIUSAttributeTypeEAPMessage, eap)
+}
+
+// EAPType returns the method Type of the EAP Request or Response carried by
+// the packet. It returns false without an EAP-Message, and for EAP Success
+// and Failure packets, which have no Type.
+func (radius *RADIUS) EAPType() (EAPType, bool) {
+	eap, _ := radius.EAPMessage()
+	if len(eap) < 5 {
+		return 0, false
+	}
+	if eap[0] != eapCodeRequest && eap[0] != eapCodeResponse {
+		return 0, false
+	}
+	return EAPType(eap[4]), true
+}
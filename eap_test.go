@@ -0,0 +1,68 @@
+package radius
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestRADIUSEAPType(t *testing.T) {
+	tests := []struct {
+		desc        string
+		eapMessages [][]byte
+		want        EAPType
+		wantOK      bool
+	}{
+		{"Identity", [][]byte{{0x02, 0x01, 0x00, 0x0a, 0x01, 'A', 'd', 'm', 'i', 'n'}}, EAPTypeIdentity, true},
+		{"PEAP", [][]byte{{0x01, 0x02, 0x00, 0x06, 0x19, 0x20}}, EAPTypePEAP, true},
+		{"Fragmented", [][]byte{{0x01, 0x03, 0x00}, {0x06, 0x0d, 0x20}}, EAPTypeTLS, true},
+		{"Success", [][]byte{{0x03, 0x04, 0x00, 0x04}}, 0, false},
+		{"Failure", [][]byte{{0x04, 0x04, 0x00, 0x04}}, 0, false},
+		{"Truncated", [][]byte{{0x02, 0x01, 0x00, 0x05}}, 0, false},
+		{"None", nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		b := NewRADIUS(RADIUSCodeAccessRequest, 1).AddRaw(RADIUSAttributeTypeUserName, []byte("Admin"))
+		for _, eap := range tt.eapMessages {
+			b.AddRaw(RADIUSAttributeTypeEAPMessage, eap)
+		}
+		radius := b.Build()
+
+		if got, ok := radius.EAPMessage(); !bytes.Equal(got, bytes.Join(tt.eapMessages, nil)) || ok != (tt.eapMessages != nil) {
+			t.Errorf("%s: EAPMessage got %x, %v", tt.desc, got, ok)
+		}
+		got, ok := radius.EAPType()
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("%s: got %s, %v want %s, %v", tt.desc, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestRADIUSBuilderAddEAPMessage(t *testing.T) {
+	eap := make([]byte, 600)
+	eap[0], eap[1], eap[2], eap[3], eap[4] = 0x01, 0x07, 0x02, 0x58, byte(EAPTypePEAP)
+	for i := 5; i < len(eap); i++ {
+		eap[i] = byte(i)
+	}
+
+	data, err := NewRADIUS(RADIUSCodeAccessChallenge, 1).AddEAPMessage(eap).Build().serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	radius := decodeTestRADIUS(t, data)
+
+	var sizes []int
+	for _, v := range radius.GetAttributes(RADIUSAttributeTypeEAPMessage) {
+		sizes = append(sizes, len(v.Value))
+	}
+	if want := []int{253, 253, 94}; !reflect.DeepEqual(sizes, want) {
+		t.Errorf("EAP-Message sizes got %v want %v", sizes, want)
+	}
+	if got, ok := radius.EAPMessage(); !ok || !bytes.Equal(got, eap) {
+		t.Errorf("EAPMessage got %x, %v want %x", got, ok, eap)
+	}
+	if got, ok := radius.EAPType(); !ok || got != EAPTypePEAP {
+		t.Errorf("EAPType got %s, %v", got, ok)
+	}
+}
@@ -0,0 +1,381 @@
+package radius
+
+import (
+	"fmt"
+)
+
+// AcctStatusType represents the value of Acct-Status-Type.
+type AcctStatusType uint32
+
+// constants that define AcctStatusType.
+const (
+	AcctStatusTypeStart            AcctStatusType = 1  // RFC2866 5.1.  Start
+	AcctStatusTypeStop             AcctStatusType = 2  // RFC2866 5.1.  Stop
+	AcctStatusTypeInterimUpdate    AcctStatusType = 3  // RFC2866 5.1.  Interim-Update
+	AcctStatusTypeAccountingOn     AcctStatusType = 7  // RFC2866 5.1.  Accounting-On
+	AcctStatusTypeAccountingOff    AcctStatusType = 8  // RFC2866 5.1.  Accounting-Off
+	AcctStatusTypeTunnelStart      AcctStatusType = 9  // RFC2867 4.1.  Tunnel-Start
+	AcctStatusTypeTunnelStop       AcctStatusType = 10 // RFC2867 4.1.  Tunnel-Stop
+	AcctStatusTypeTunnelReject     AcctStatusType = 11 // RFC2867 4.1.  Tunnel-Reject
+	AcctStatusTypeTunnelLinkStart  AcctStatusType = 12 // RFC2867 4.1.  Tunnel-Link-Start
+	AcctStatusTypeTunnelLinkStop   AcctStatusType = 13 // RFC2867 4.1.  Tunnel-Link-Stop
+	AcctStatusTypeTunnelLinkReject AcctStatusType = 14 // RFC2867 4.1.  Tunnel-Link-Reject
+	AcctStatusTypeFailed           AcctStatusType = 15 // RFC2866 5.1.  Failed
+)
+
+// String returns a string version of a AcctStatusType.
+func (t AcctStatusType) String() (s string) {
+	switch t {
+	case AcctStatusTypeStart:
+		s = "Start"
+	case AcctStatusTypeStop:
+		s = "Stop"
+	case AcctStatusTypeInterimUpdate:
+		s = "Interim-Update"
+	case AcctStatusTypeAccountingOn:
+		s = "Accounting-On"
+	case AcctStatusTypeAccountingOff:
+		s = "Accounting-Off"
+	case AcctStatusTypeTunnelStart:
+		s = "Tunnel-Start"
+	case AcctStatusTypeTunnelStop:
+		s = "Tunnel-Stop"
+	case AcctStatusTypeTunnelReject:
+		s = "Tunnel-Reject"
+	case AcctStatusTypeTunnelLinkStart:
+		s = "Tunnel-Link-Start"
+	case AcctStatusTypeTunnelLinkStop:
+		s = "Tunnel-Link-Stop"
+	case AcctStatusTypeTunnelLinkReject:
+		s = "Tunnel-Link-Reject"
+	case AcctStatusTypeFailed:
+		s = "Failed"
+	default:
+		s = fmt.Sprintf("Unknown(%d)", t)
+	}
+	return
+}
+
+// AcctStatusType returns the Acct-Status-Type attribute.
+func (radius *RADIUS) AcctStatusType() (AcctStatusType, bool) {
+	n, ok := radius.uint32Attribute(RADIUSAttributeTypeAcctStatusType)
+	return AcctStatusType(n), ok
+}
+
+// AcctStatusType decodes the value as an Acct-Status-Type.
+func (v RADIUSAttributeValue) AcctStatusType() (AcctStatusType, error) {
+	n, err := v.Uint32()
+	return AcctStatusType(n), err
+}
+
+// FramedRouting represents the value of Framed-Routing.
+type FramedRouting uint32
+
+// constants that define FramedRouting.
+const (
+	FramedRoutingNone            FramedRouting = 0 // RFC2865 5.10.  None
+	FramedRoutingBroadcast       FramedRouting = 1 // RFC2865 5.10.  Send routing packets
+	FramedRoutingListen          FramedRouting = 2 // RFC2865 5.10.  Listen for routing packets
+	FramedRoutingBroadcastListen FramedRouting = 3 // RFC2865 5.10.  Send and Listen
+)
+
+// String returns a string version of a FramedRouting.
+func (t FramedRouting) String() (s string) {
+	switch t {
+	case FramedRoutingNone:
+		s = "None"
+	case FramedRoutingBroadcast:
+		s = "Broadcast"
+	case FramedRoutingListen:
+		s = "Listen"
+	case FramedRoutingBroadcastListen:
+		s = "Broadcast-Listen"
+	default:
+		s = fmt.Sprintf("Unknown(%d)", t)
+	}
+	return
+}
+
+// FramedRouting returns the Framed-Routing attribute.
+func (radius *RADIUS) FramedRouting() (FramedRouting, bool) {
+	n, ok := radius.uint32Attribute(RADIUSAttributeTypeFramedRouting)
+	return FramedRouting(n), ok
+}
+
+// FramedCompression represents the value of Framed-Compression.
+type FramedCompression uint32
+
+// constants that define FramedCompression.
+const (
+	FramedCompressionNone    FramedCompression = 0 // RFC2865 5.13.  None
+	FramedCompressionVJ      FramedCompression = 1 // RFC2865 5.13.  VJ TCP/IP header compression
+	FramedCompressionIPX     FramedCompression = 2 // RFC2865 5.13.  IPX header compression
+	FramedCompressionStacLZS FramedCompression = 3 // RFC2865 5.13.  Stac-LZS compression
+)
+
+// String returns a string version of a FramedCompression.
+func (t FramedCompression) String() (s string) {
+	switch t {
+	case FramedCompressionNone:
+		s = "None"
+	case FramedCompressionVJ:
+		s = "Van-Jacobson-TCP-IP"
+	case FramedCompressionIPX:
+		s = "IPX-Header-Compression"
+	case FramedCompressionStacLZS:
+		s = "Stac-LZS"
+	default:
+		s = fmt.Sprintf("Unknown(%d)", t)
+	}
+	return
+}
+
+// FramedCompression returns the Framed-Compression attribute.
+func (radius *RADIUS) FramedCompression() (FramedCompression, bool) {
+	n, ok := radius.uint32Attribute(RADIUSAttributeTypeFramedCompression)
+	return FramedCompression(n), ok
+}
+
+// ErrorCause represents the value of Error-Cause.
+type ErrorCause uint32
+
+// constants that define ErrorCause.
+const (
+	ErrorCauseResidualSessionContextRemoved       ErrorCause = 201 // RFC5176 3.6.  Residual-Session-Context-Removed
+	ErrorCauseInvalidEAPPacket                    ErrorCause = 202 // RFC5176 3.6.  Invalid-EAP-Packet
+	ErrorCauseUnsupportedAttribute                ErrorCause = 401 // RFC5176 3.6.  Unsupported-Attribute
+	ErrorCauseMissingAttribute                    ErrorCause = 402 // RFC5176 3.6.  Missing-Attribute
+	ErrorCauseNASIdentificationMismatch           ErrorCause = 403 // RFC5176 3.6.  NAS-Identification-Mismatch
+	ErrorCauseInvalidRequest                      ErrorCause = 404 // RFC5176 3.6.  Invalid-Request
+	ErrorCauseUnsupportedService                  ErrorCause = 405 // RFC5176 3.6.  Unsupported-Service
+	ErrorCauseUnsupportedExtension                ErrorCause = 406 // RFC5176 3.6.  Unsupported-Extension
+	ErrorCauseInvalidAttributeValue               ErrorCause = 407 // RFC5176 3.6.  Invalid-Attribute-Value
+	ErrorCauseAdministrativelyProhibited          ErrorCause = 501 // RFC5176 3.6.  Administratively-Prohibited
+	ErrorCauseRequestNotRoutable                  ErrorCause = 502 // RFC5176 3.6.  Request-Not-Routable
+	ErrorCauseSessionContextNotFound              ErrorCause = 503 // RFC5176 3.6.  Session-Context-Not-Found
+	ErrorCauseSessionContextNotRemovable          ErrorCause = 504 // RFC5176 3.6.  Session-Context-Not-Removable
+	ErrorCauseOtherProxyProcessingError           ErrorCause = 505 // RFC5176 3.6.  Other-Proxy-Processing-Error
+	ErrorCauseResourcesUnavailable                ErrorCause = 506 // RFC5176 3.6.  Resources-Unavailable
+	ErrorCauseRequestInitiated                    ErrorCause = 507 // RFC5176 3.6.  Request-Initiated
+	ErrorCauseMultipleSessionSelectionUnsupported ErrorCause = 508 // RFC5176 3.6.  Multiple-Session-Selection-Unsupported
+)
+
+// String returns a string version of a ErrorCause.
+func (t ErrorCause) String() (s string) {
+	switch t {
+	case ErrorCauseResidualSessionContextRemoved:
+		s = "Residual-Session-Context-Removed"
+	case ErrorCauseInvalidEAPPacket:
+		s = "Invalid-EAP-Packet"
+	case ErrorCauseUnsupportedAttribute:
+		s = "Unsupported-Attribute"
+	case ErrorCauseMissingAttribute:
+		s = "Missing-Attribute"
+	case ErrorCauseNASIdentificationMismatch:
+		s = "NAS-Identification-Mismatch"
+	case ErrorCauseInvalidRequest:
+		s = "Invalid-Request"
+	case ErrorCauseUnsupportedService:
+		s = "Unsupported-Service"
+	case ErrorCauseUnsupportedExtension:
+		s = "Unsupported-Extension"
+	case ErrorCauseInvalidAttributeValue:
+		s = "Invalid-Attribute-Value"
+	case ErrorCauseAdministrativelyProhibited:
+		s = "Administratively-Prohibited"
+	case ErrorCauseRequestNotRoutable:
+		s = "Request-Not-Routable"
+	case ErrorCauseSessionContextNotFound:
+		s = "Session-Context-Not-Found"
+	case ErrorCauseSessionContextNotRemovable:
+		s = "Session-Context-Not-Removable"
+	case ErrorCauseOtherProxyProcessingError:
+		s = "Other-Proxy-Processing-Error"
+	case ErrorCauseResourcesUnavailable:
+		s = "Resources-Unavailable"
+	case ErrorCauseRequestInitiated:
+		s = "Request-Initiated"
+	case ErrorCauseMultipleSessionSelectionUnsupported:
+		s = "Multiple-Session-Selection-Unsupported"
+	default:
+		s = fmt.Sprintf("Unknown(%d)", t)
+	}
+	return
+}
+
+// ErrorCause returns the Error-Cause attribute of a Disconnect-ACK,
+// Disconnect-NAK, CoA-ACK or CoA-NAK.
+func (radius *RADIUS) ErrorCause() (ErrorCause, bool) {
+	n, ok := radius.uint32Attribute(RADIUSAttributeTypeErrorCause)
+	return ErrorCause(n), ok
+}
+
+// ServiceType represents the value of Service-Type.
+type ServiceType uint32
+
+// constants that define ServiceType.
+const (
+	ServiceTypeLogin                  ServiceType = 1  // RFC2865 5.6.  Login
+	ServiceTypeFramed                 ServiceType = 2  // RFC2865 5.6.  Framed
+	ServiceTypeCallbackLogin          ServiceType = 3  // RFC2865 5.6.  Callback-Login
+	ServiceTypeCallbackFramed         ServiceType = 4  // RFC2865 5.6.  Callback-Framed
+	ServiceTypeOutbound               ServiceType = 5  // RFC2865 5.6.  Outbound
+	ServiceTypeAdministrative         ServiceType = 6  // RFC2865 5.6.  Administrative
+	ServiceTypeNASPrompt              ServiceType = 7  // RFC2865 5.6.  NAS-Prompt
+	ServiceTypeAuthenticateOnly       ServiceType = 8  // RFC2865 5.6.  Authenticate-Only
+	ServiceTypeCallbackNASPrompt      ServiceType = 9  // RFC2865 5.6.  Callback-NAS-Prompt
+	ServiceTypeCallCheck              ServiceType = 10 // RFC2865 5.6.  Call-Check
+	ServiceTypeCallbackAdministrative ServiceType = 11 // RFC2865 5.6.  Callback-Administrative
+	ServiceTypeAuthorizeOnly          ServiceType = 17 // RFC5176 3.1.  Authorize-Only
+	ServiceTypeFramedManagement       ServiceType = 18 // RFC5607 4.   Framed-Management
+)
+
+// String returns a string version of a ServiceType.
+func (t ServiceType) String() (s string) {
+	switch t {
+	case ServiceTypeLogin:
+		s = "Login"
+	case ServiceTypeFramed:
+		s = "Framed"
+	case ServiceTypeCallbackLogin:
+		s = "Callback-Login"
+	case ServiceTypeCallbackFramed:
+		s = "Callback-Framed"
+	case ServiceTypeOutbound:
+		s = "Outbound"
+	case ServiceTypeAdministrative:
+		s = "Administrative"
+	case ServiceTypeNASPrompt:
+		s = "NAS-Prompt"
+	case ServiceTypeAuthenticateOnly:
+		s = "Authenticate-Only"
+	case ServiceTypeCallbackNASPrompt:
+		s = "Callback-NAS-Prompt"
+	case ServiceTypeCallCheck:
+		s = "Call-Check"
+	case ServiceTypeCallbackAdministrative:
+		s = "Callback-Administrative"
+	case ServiceTypeAuthorizeOnly:
+		s = "Authorize-Only"
+	case ServiceTypeFramedManagement:
+		s = "Framed-Management"
+	default:
+		s = fmt.Sprintf("Unknown(%d)", t)
+	}
+	return
+}
+
+// ServiceType returns the Service-Type attribute.
+func (radius *RADIUS) ServiceType() (ServiceType, bool) {
+	n, ok := radius.uint32Attribute(RADIUSAttributeTypeServiceType)
+	return ServiceType(n), ok
+}
+
+// ServiceType decodes the value as a Service-Type.
+func (v RADIUSAttributeValue) ServiceType() (ServiceType, error) {
+	n, err := v.Uint32()
+	return ServiceType(n), err
+}
+
+// NASPortType represents the value of NAS-Port-Type.
+type NASPortType uint32
+
+// constants that define NASPortType.
+const (
+	NASPortTypeAsync            NASPortType = 0  // RFC2865 5.41. Async
+	NASPortTypeSync             NASPortType = 1  // RFC2865 5.41. Sync
+	NASPortTypeISDN             NASPortType = 2  // RFC2865 5.41. ISDN
+	NASPortTypeISDNV120         NASPortType = 3  // RFC2865 5.41. ISDN-V120
+	NASPortTypeISDNV110         NASPortType = 4  // RFC2865 5.41. ISDN-V110
+	NASPortTypeVirtual          NASPortType = 5  // RFC2865 5.41. Virtual
+	NASPortTypePIAFS            NASPortType = 6  // RFC2865 5.41. PIAFS
+	NASPortTypeHDLCClearChannel NASPortType = 7  // RFC2865 5.41. HDLC-Clear-Channel
+	NASPortTypeX25              NASPortType = 8  // RFC2865 5.41. X.25
+	NASPortTypeX75              NASPortType = 9  // RFC2865 5.41. X.75
+	NASPortTypeG3Fax            NASPortType = 10 // RFC2865 5.41. G.3-Fax
+	NASPortTypeSDSL             NASPortType = 11 // RFC2865 5.41. SDSL
+	NASPortTypeADSLCAP          NASPortType = 12 // RFC2865 5.41. ADSL-CAP
+	NASPortTypeADSLDMT          NASPortType = 13 // RFC2865 5.41. ADSL-DMT
+	NASPortTypeIDSL             NASPortType = 14 // RFC2865 5.41. IDSL
+	NASPortTypeEthernet         NASPortType = 15 // RFC2865 5.41. Ethernet
+	NASPortTypeXDSL             NASPortType = 16 // RFC2865 5.41. xDSL
+	NASPortTypeCable            NASPortType = 17 // RFC2865 5.41. Cable
+	NASPortTypeWirelessOther    NASPortType = 18 // RFC2865 5.41. Wireless-Other
+	NASPortTypeWireless80211    NASPortType = 19 // RFC2865 5.41. Wireless-802.11
+	NASPortTypePPPoA            NASPortType = 30 // RFC4603 2.   PPPoA
+	NASPortTypePPPoEoA          NASPortType = 31 // RFC4603 2.   PPPoEoA
+	NASPortTypePPPoEoE          NASPortType = 32 // RFC4603 2.   PPPoEoE
+	NASPortTypePPPoEoVLAN       NASPortType = 33 // RFC4603 2.   PPPoEoVLAN
+	NASPortTypePPPoEoQinQ       NASPortType = 34 // RFC4603 2.   PPPoEoQinQ
+)
+
+// String returns a string version of a NASPortType.
+func (t NASPortType) String() (s string) {
+	switch t {
+	case NASPortTypeAsync:
+		s = "Async"
+	case NASPortTypeSync:
+		s = "Sync"
+	case NASPortTypeISDN:
+		s = "ISDN"
+	case NASPortTypeISDNV120:
+		s = "ISDN-V120"
+	case NASPortTypeISDNV110:
+		s = "ISDN-V110"
+	case NASPortTypeVirtual:
+		s = "Virtual"
+	case NASPortTypePIAFS:
+		s = "PIAFS"
+	case NASPortTypeHDLCClearChannel:
+		s = "HDLC-Clear-Channel"
+	case NASPortTypeX25:
+		s = "X.25"
+	case NASPortTypeX75:
+		s = "X.75"
+	case NASPortTypeG3Fax:
+		s = "G.3-Fax"
+	case NASPortTypeSDSL:
+		s = "SDSL"
+	case NASPortTypeADSLCAP:
+		s = "ADSL-CAP"
+	case NASPortTypeADSLDMT:
+		s = "ADSL-DMT"
+	case NASPortTypeIDSL:
+		s = "IDSL"
+	case NASPortTypeEthernet:
+		s = "Ethernet"
+	case NASPortTypeXDSL:
+		s = "xDSL"
+	case NASPortTypeCable:
+		s = "Cable"
+	case NASPortTypeWirelessOther:
+		s = "Wireless-Other"
+	case NASPortTypeWireless80211:
+		s = "Wireless-802.11"
+	case NASPortTypePPPoA:
+		s = "PPPoA"
+	case NASPortTypePPPoEoA:
+		s = "PPPoEoA"
+	case NASPortTypePPPoEoE:
+		s = "PPPoEoE"
+	case NASPortTypePPPoEoVLAN:
+		s = "PPPoEoVLAN"
+	case NASPortTypePPPoEoQinQ:
+		s = "PPPoEoQinQ"
+	default:
+		s = fmt.Sprintf("Unknown(%d)", t)
+	}
+	return
+}
+
+// NASPortType returns the NAS-Port-Type attribute.
+func (radius *RADIUS) NASPortType() (NASPortType, bool) {
+	n, ok := radius.uint32Attribute(RADIUSAttributeTypeNASPortType)
+	return NASPortType(n), ok
+}
+
+// NASPortType decodes the value as a NAS-Port-Type.
+func (v RADIUSAttributeValue) NASPortType() (NASPortType, error) {
+	n, err := v.Uint32()
+	return NASPortType(n), err
+}
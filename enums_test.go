@@ -0,0 +1,98 @@
+package radius
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRADIUSFramedRouting(t *testing.T) {
+	radius := &RADIUS{Attributes: []RADIUSAttribute{
+		newAttribute(RADIUSAttributeTypeFramedRouting, uint32Value(3)),
+	}}
+	if v, ok := radius.FramedRouting(); v != FramedRoutingBroadcastListen || !ok {
+		t.Errorf("got %v, %v want %v, true", v, ok, FramedRoutingBroadcastListen)
+	}
+	if v, err := radius.Attributes[0].DecodedValue(); err != nil || v != FramedRoutingBroadcastListen {
+		t.Errorf("DecodedValue() got %#v, %v", v, err)
+	}
+	if s := FramedRoutingBroadcastListen.String(); s != "Broadcast-Listen" {
+		t.Errorf("got %q want %q", s, "Broadcast-Listen")
+	}
+	if s := FramedRouting(4).String(); s != "Unknown(4)" {
+		t.Errorf("got %q want %q", s, "Unknown(4)")
+	}
+	if _, ok := (&RADIUS{}).FramedRouting(); ok {
+		t.Error("expected no Framed-Routing")
+	}
+}
+
+func TestRADIUSErrorCause(t *testing.T) {
+	radius := &RADIUS{Code: RADIUSCodeCoANAK, Attributes: []RADIUSAttribute{
+		newAttribute(RADIUSAttributeTypeErrorCause, uint32Value(401)),
+	}}
+	if v, ok := radius.ErrorCause(); v != ErrorCauseUnsupportedAttribute || !ok {
+		t.Errorf("got %v, %v want %v, true", v, ok, ErrorCauseUnsupportedAttribute)
+	}
+	if s := radius.Attributes[0].String(); s != "Error-Cause = Unsupported-Attribute" {
+		t.Errorf("String() got %q", s)
+	}
+	if s := ErrorCauseResidualSessionContextRemoved.String(); s != "Residual-Session-Context-Removed" {
+		t.Errorf("got %q want %q", s, "Residual-Session-Context-Removed")
+	}
+	if s := ErrorCause(600).String(); s != "Unknown(600)" {
+		t.Errorf("got %q want %q", s, "Unknown(600)")
+	}
+	if _, ok := (&RADIUS{}).ErrorCause(); ok {
+		t.Error("expected no Error-Cause")
+	}
+}
+
+func TestRADIUSAttributeValueEnums(t *testing.T) {
+	tests := []struct {
+		desc   string
+		decode func(RADIUSAttributeValue) (fmt.Stringer, error)
+		n      uint32
+		want   string
+	}{
+		{"Service-Type", func(v RADIUSAttributeValue) (fmt.Stringer, error) { return v.ServiceType() }, 2, "Framed"},
+		{"Service-Type", func(v RADIUSAttributeValue) (fmt.Stringer, error) { return v.ServiceType() }, 10, "Call-Check"},
+		{"Service-Type", func(v RADIUSAttributeValue) (fmt.Stringer, error) { return v.ServiceType() }, 99, "Unknown(99)"},
+		{"NAS-Port-Type", func(v RADIUSAttributeValue) (fmt.Stringer, error) { return v.NASPortType() }, 15, "Ethernet"},
+		{"NAS-Port-Type", func(v RADIUSAttributeValue) (fmt.Stringer, error) { return v.NASPortType() }, 19, "Wireless-802.11"},
+		{"NAS-Port-Type", func(v RADIUSAttributeValue) (fmt.Stringer, error) { return v.NASPortType() }, 99, "Unknown(99)"},
+		{"Acct-Status-Type", func(v RADIUSAttributeValue) (fmt.Stringer, error) { return v.AcctStatusType() }, 1, "Start"},
+		{"Acct-Status-Type", func(v RADIUSAttributeValue) (fmt.Stringer, error) { return v.AcctStatusType() }, 3, "Interim-Update"},
+		{"Acct-Status-Type", func(v RADIUSAttributeValue) (fmt.Stringer, error) { return v.AcctStatusType() }, 99, "Unknown(99)"},
+	}
+
+	for _, tt := range tests {
+		v, err := tt.decode(uint32Value(tt.n))
+		if err != nil {
+			t.Errorf("%s %d: unexpected error: %v", tt.desc, tt.n, err)
+		} else if s := v.String(); s != tt.want {
+			t.Errorf("%s %d: got %q want %q", tt.desc, tt.n, s, tt.want)
+		}
+		if _, err := tt.decode(RADIUSAttributeValue{0x00, 0x02}); err == nil {
+			t.Errorf("%s of 2 bytes: expected error", tt.desc)
+		}
+	}
+}
+
+func TestRADIUSServiceTypeNASPortType(t *testing.T) {
+	radius := &RADIUS{Attributes: []RADIUSAttribute{
+		newAttribute(RADIUSAttributeTypeServiceType, uint32Value(uint32(ServiceTypeFramed))),
+		newAttribute(RADIUSAttributeTypeNASPortType, uint32Value(uint32(NASPortTypeWireless80211))),
+	}}
+	if v, ok := radius.ServiceType(); v != ServiceTypeFramed || !ok {
+		t.Errorf("ServiceType got %v, %v want %v, true", v, ok, ServiceTypeFramed)
+	}
+	if v, ok := radius.NASPortType(); v != NASPortTypeWireless80211 || !ok {
+		t.Errorf("NASPortType got %v, %v want %v, true", v, ok, NASPortTypeWireless80211)
+	}
+	if s := radius.Attributes[1].String(); s != "NAS-Port-Type = Wireless-802.11" {
+		t.Errorf("String() got %q", s)
+	}
+	if _, ok := (&RADIUS{}).ServiceType(); ok {
+		t.Error("expected no Service-Type")
+	}
+}
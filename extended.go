@@ -0,0 +1,158 @@
+package radius
+
+import (
+	"fmt"
+)
+
+// radiusLongExtendedMore is the More flag of a Long Extended attribute,
+// RFC6929 2.2.
+const radiusLongExtendedMore byte = 0x80
+
+// RADIUSExtendedAttribute represents an attribute of the RFC6929 extended
+// attribute space: Extended-Type-1 to -4 and Long-Extended-Type-1 and -2.
+// More is the flag chaining the fragments of a Long Extended attribute, and
+// is always false for the others.
+type RADIUSExtendedAttribute struct {
+	Type         RADIUSAttributeType
+	ExtendedType uint8
+	More         bool
+	Value        RADIUSAttributeValue
+}
+
+// IsExtended reports whether a RADIUSAttributeType is one of the RFC6929
+// Extended-Type or Long-Extended-Type attributes.
+func (t RADIUSAttributeType) IsExtended() bool {
+	return t >= RADIUSAttributeTypeExtendedType1 && t <= RADIUSAttributeTypeLongExtendedType2
+}
+
+// isLongExtended reports whether a RADIUSAttributeType is one of the RFC6929
+// Long-Extended-Type attributes, which have a flags byte.
+func (t RADIUSAttributeType) isLongExtended() bool {
+	return t == RADIUSAttributeTypeLongExtendedType1 || t == RADIUSAttributeTypeLongExtendedType2
+}
+
+// extendedHeaderLength returns the length of the header preceding the value
+// of an extended attribute: the Extended-Type, and the flags of Long
+// Extended attributes.
+func (t RADIUSAttributeType) extendedHeaderLength() int {
+	if t.isLongExtended() {
+		return 2
+	}
+	return 1
+}
+
+// Extended decodes an attribute of the extended attribute space, whose value
+// starts with an Extended-Type byte, followed by a flags byte for Long
+// Extended attributes (RFC6929 2.). The Value of the returned attribute
+// refers to the value of a.
+func (a RADIUSAttribute) Extended() (*RADIUSExtendedAttribute, error) {
+	if !a.Type.IsExtended() {
+		return nil, fmt.Errorf("RADIUS attribute %s is not extended", a.Type)
+	}
+	n := a.Type.extendedHeaderLength()
+	if len(a.Value) < n {
+		return nil, fmt.Errorf("RADIUS %s length %d shorter than the %d bytes extended header", a.Type, len(a.Value), n)
+	}
+	e := &RADIUSExtendedAttribute{
+		Type:         a.Type,
+		ExtendedType: a.Value[0],
+		Value:        a.Value[n:],
+	}
+	if a.Type.isLongExtended() {
+		e.More = a.Value[1]&radiusLongExtendedMore != 0
+	}
+	return e, nil
+}
+
+// SerializeExtended encodes e as an attribute, the reverse of Extended.
+func SerializeExtended(e *RADIUSExtendedAttribute) (RADIUSAttribute, error) {
+	if !e.Type.IsExtended() {
+		return RADIUSAttribute{}, fmt.Errorf("RADIUS attribute %s is not extended", e.Type)
+	}
+	if e.More && !e.Type.isLongExtended() {
+		return RADIUSAttribute{}, fmt.Errorf("RADIUS %s has no More flag", e.Type)
+	}
+	n := e.Type.extendedHeaderLength()
+	if n+len(e.Value) > radiusMaximumAttributeValueSizeInBytes {
+		return RADIUSAttribute{}, fmt.Errorf("RADIUS %s length %d exceeds %d", e.Type, n+len(e.Value), radiusMaximumAttributeValueSizeInBytes)
+	}
+
+	value := make([]byte, n, n+len(e.Value))
+	value[0] = e.ExtendedType
+	if e.More {
+		value[1] = radiusLongExtendedMore
+	}
+	return newAttribute(e.Type, append(value, e.Value...)), nil
+}
+
+// LongExtendedValue returns the value of the Long Extended attribute of type
+// t, Long-Extended-Type-1 or -2, with the given Extended-Type, reassembled
+// from the consecutive fragments chained by the More flag (RFC6929 2.2). It
+// returns false if the attribute is absent, or if its fragments end without
+// clearing the More flag.
+func (radius *RADIUS) LongExtendedValue(t RADIUSAttributeType, extType uint8) ([]byte, bool) {
+	if !t.isLongExtended() {
+		return nil, false
+	}
+	for i, v := range radius.Attributes {
+		if v.Type != t || len(v.Value) < 2 || v.Value[0] != extType {
+			continue
+		}
+
+		var value []byte
+		for _, fragment := range radius.Attributes[i:] {
+			if fragment.Type != t || len(fragment.Value) < 2 || fragment.Value[0] != extType {
+				return nil, false
+			}
+			value = append(value, fragment.Value[2:]...)
+			if fragment.Value[1]&radiusLongExtendedMore == 0 {
+				return value, true
+			}
+		}
+		return nil, false
+	}
+	return nil, false
+}
+
+// SerializeLongExtended encodes value as the Long Extended attributes of type
+// t with the given Extended-Type, the reverse of LongExtendedValue: value is
+// split into fragments of at most 251 bytes, with the More flag set on all
+// but the last.
+func SerializeLongExtended(t RADIUSAttributeType, extType uint8, value []byte) ([]RADIUSAttribute, error) {
+	if !t.isLongExtended() {
+		return nil, fmt.Errorf("RADIUS attribute %s is not long extended", t)
+	}
+	size := radiusMaximumAttributeValueSizeInBytes - t.extendedHeaderLength()
+	var attrs []RADIUSAttribute
+	for {
+		n := len(value)
+		if n > size {
+			n = size
+		}
+		attr, err := SerializeExtended(&RADIUSExtendedAttribute{
+			Type:         t,
+			ExtendedType: extType,
+			More:         n < len(value),
+			Value:        value[:n],
+		})
+		if err != nil {
+			return nil, err
+		}
+		attrs = append(attrs, attr)
+		value = value[n:]
+		if len(value) == 0 {
+			return attrs, nil
+		}
+	}
+}
+
+// AddLongExtended appends the fragments of a Long Extended attribute, see
+// SerializeLongExtended.
+func (b *RADIUSBuilder) AddLongExtended(t RADIUSAttributeType, extType uint8, value []byte) error {
+	attrs, err := SerializeLongExtended(t, extType, value)
+	if err != nil {
+		return err
+	}
+	b.attributes = append(b.attributes, attrs...)
+	return nil
+}
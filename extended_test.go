@@ -0,0 +1,206 @@
+package radius
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/gopacket"
+)
+
+func TestRADIUSLongExtendedValue(t *testing.T) {
+	long := bytes.Repeat([]byte("0123456789"), 60)
+	fragment := func(t RADIUSAttributeType, extType uint8, more bool, value []byte) RADIUSAttribute {
+		flags := byte(0)
+		if more {
+			flags = radiusLongExtendedMore
+		}
+		return newAttribute(t, append([]byte{extType, flags}, value...))
+	}
+
+	tests := []struct {
+		desc       string
+		attributes []RADIUSAttribute
+		t          RADIUSAttributeType
+		extType    uint8
+		want       []byte
+		wantOK     bool
+	}{
+		{
+			desc: "Fragmented",
+			attributes: []RADIUSAttribute{
+				newAttribute(RADIUSAttributeTypeUserName, []byte("Admin")),
+				fragment(RADIUSAttributeTypeLongExtendedType1, 1, true, long[:251]),
+				fragment(RADIUSAttributeTypeLongExtendedType1, 1, true, long[251:502]),
+				fragment(RADIUSAttributeTypeLongExtendedType1, 1, false, long[502:]),
+				newAttribute(RADIUSAttributeTypeNASIdentifier, []byte("nas01")),
+			},
+			t:       RADIUSAttributeTypeLongExtendedType1,
+			extType: 1,
+			want:    long,
+			wantOK:  true,
+		},
+		{
+			desc: "Single",
+			attributes: []RADIUSAttribute{
+				fragment(RADIUSAttributeTypeLongExtendedType2, 3, false, []byte("short")),
+				fragment(RADIUSAttributeTypeLongExtendedType2, 4, false, []byte("other")),
+			},
+			t:       RADIUSAttributeTypeLongExtendedType2,
+			extType: 4,
+			want:    []byte("other"),
+			wantOK:  true,
+		},
+		{
+			desc: "Truncated",
+			attributes: []RADIUSAttribute{
+				fragment(RADIUSAttributeTypeLongExtendedType1, 1, true, long[:251]),
+			},
+			t:       RADIUSAttributeTypeLongExtendedType1,
+			extType: 1,
+		},
+		{
+			desc: "Interrupted",
+			attributes: []RADIUSAttribute{
+				fragment(RADIUSAttributeTypeLongExtendedType1, 1, true, long[:251]),
+				newAttribute(RADIUSAttributeTypeUserName, []byte("Admin")),
+				fragment(RADIUSAttributeTypeLongExtendedType1, 1, false, long[251:]),
+			},
+			t:       RADIUSAttributeTypeLongExtendedType1,
+			extType: 1,
+		},
+		{
+			desc: "OtherType",
+			attributes: []RADIUSAttribute{
+				fragment(RADIUSAttributeTypeLongExtendedType1, 4, false, []byte("other")),
+			},
+			t:       RADIUSAttributeTypeLongExtendedType2,
+			extType: 4,
+		},
+		{
+			desc: "Absent",
+			attributes: []RADIUSAttribute{
+				fragment(RADIUSAttributeTypeLongExtendedType1, 2, false, []byte("other")),
+			},
+			t:       RADIUSAttributeTypeLongExtendedType1,
+			extType: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		radius := &RADIUS{Attributes: tt.attributes}
+		got, ok := radius.LongExtendedValue(tt.t, tt.extType)
+		if !bytes.Equal(got, tt.want) || ok != tt.wantOK {
+			t.Errorf("%s: got %d bytes, %v want %d bytes, %v", tt.desc, len(got), ok, len(tt.want), tt.wantOK)
+		}
+	}
+}
+
+func TestSerializeLongExtended(t *testing.T) {
+	long := bytes.Repeat([]byte("0123456789"), 60)
+	b := NewRADIUS(RADIUSCodeAccessAccept, 0x01).AddString(RADIUSAttributeTypeReplyMessage, "Welcome")
+	if err := b.AddLongExtended(RADIUSAttributeTypeLongExtendedType1, 1, long); err != nil {
+		t.Fatal(err)
+	}
+	data, err := b.Build().serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	radius := decodeTestRADIUS(t, data)
+
+	fragments := radius.GetAttributes(RADIUSAttributeTypeLongExtendedType1)
+	if len(fragments) != 3 {
+		t.Fatalf("got %d fragments want 3", len(fragments))
+	}
+	for i, v := range fragments {
+		e, err := v.Extended()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := i < 2; e.More != want {
+			t.Errorf("fragment %d: More got %v want %v", i, e.More, want)
+		}
+		if i < 2 && len(v.Value) != radiusMaximumAttributeValueSizeInBytes {
+			t.Errorf("fragment %d: length %d want 253", i, len(v.Value))
+		}
+	}
+	if got, ok := radius.LongExtendedValue(RADIUSAttributeTypeLongExtendedType1, 1); !ok || !bytes.Equal(got, long) {
+		t.Errorf("got %d bytes, %v want %d bytes", len(got), ok, len(long))
+	}
+
+	attrs, err := SerializeLongExtended(RADIUSAttributeTypeLongExtendedType2, 2, nil)
+	if err != nil || len(attrs) != 1 || !bytes.Equal(attrs[0].Value, []byte{0x02, 0x00}) {
+		t.Errorf("empty value got %v, %v", attrs, err)
+	}
+	if _, err := SerializeLongExtended(RADIUSAttributeTypeExtendedType1, 1, long); err == nil {
+		t.Error("Extended-Type-1: expected error")
+	}
+}
+
+func TestRADIUSAttributeExtended(t *testing.T) {
+	// Extended-Type-1, Extended-Type 1 (Frag-Status), Length 7
+	data := []byte{
+		0x01, 0x00, 0x00, 0x1b, 0x0d, 0xbe, 0x70, 0x8d, 0x93, 0xd4, 0x13, 0xce, 0x31, 0x96, 0xe4, 0x3f,
+		0x78, 0x2a, 0x0a, 0xee, 0xf1, 0x07, 0x01, 0x00, 0x00, 0x00, 0x01,
+	}
+	radius := decodeTestRADIUS(t, data)
+	if len(radius.Attributes) != 1 {
+		t.Fatalf("got %d attributes want 1", len(radius.Attributes))
+	}
+	e, err := radius.Attributes[0].Extended()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if e.Type != RADIUSAttributeTypeExtendedType1 || e.ExtendedType != 1 || e.More || !bytes.Equal(e.Value, []byte{0x00, 0x00, 0x00, 0x01}) {
+		t.Errorf("got %+v", e)
+	}
+
+	attr, err := SerializeExtended(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attr.Length != 7 || !bytes.Equal(attr.Value, radius.Attributes[0].Value) {
+		t.Errorf("SerializeExtended got %+v want %+v", attr, radius.Attributes[0])
+	}
+
+	long := &RADIUSExtendedAttribute{Type: RADIUSAttributeTypeLongExtendedType1, ExtendedType: 2, More: true, Value: RADIUSAttributeValue("abc")}
+	attr, err = SerializeExtended(long)
+	if err != nil || string(attr.Value) != "\x02\x80abc" {
+		t.Errorf("Long-Extended-Type-1 got %q, %v", attr.Value, err)
+	}
+	if got, err := attr.Extended(); err != nil || !got.More || string(got.Value) != "abc" {
+		t.Errorf("Long-Extended-Type-1 round trip got %+v, %v", got, err)
+	}
+
+	for _, attr := range []RADIUSAttribute{
+		newAttribute(RADIUSAttributeTypeExtendedType1, nil),
+		newAttribute(RADIUSAttributeTypeLongExtendedType1, []byte{0x02}),
+		newAttribute(RADIUSAttributeTypeUserName, []byte("Admin")),
+	} {
+		if _, err := attr.Extended(); err == nil {
+			t.Errorf("%s %x: expected error", attr.Type, []byte(attr.Value))
+		}
+	}
+	for _, e := range []*RADIUSExtendedAttribute{
+		{Type: RADIUSAttributeTypeExtendedType1, More: true},
+		{Type: RADIUSAttributeTypeExtendedType2, Value: make(RADIUSAttributeValue, 253)},
+		{Type: RADIUSAttributeTypeVendorSpecific},
+	} {
+		if _, err := SerializeExtended(e); err == nil {
+			t.Errorf("%+v: expected error", e)
+		}
+	}
+}
+
+func TestRADIUSDecodeExtendedHeader(t *testing.T) {
+	for _, attr := range [][]byte{
+		{0xf1, 0x02, 0x01, 0x03, 'A'},
+		{0xf5, 0x03, 0x01, 0x01, 0x03, 'A'},
+	} {
+		data := append([]byte{0x01, 0x00, 0x00, byte(20 + len(attr))}, make([]byte, 16)...)
+		data = append(data, attr...)
+		radius := &RADIUS{}
+		if err := radius.DecodeFromBytes(data, gopacket.NilDecodeFeedback); err == nil {
+			t.Errorf("%x: expected error", attr)
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package radius
+
+import (
+	"bytes"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// FilterAction represents the action of an IPFilterRule.
+type FilterAction uint8
+
+// constants that define FilterAction.
+const (
+	FilterActionPermit FilterAction = iota // RFC6733 4.3.1.  permit
+	FilterActionDeny                       // RFC6733 4.3.1.  deny
+)
+
+// String returns a string version of a FilterAction.
+func (a FilterAction) String() (s string) {
+	switch a {
+	case FilterActionPermit:
+		s = "permit"
+	case FilterActionDeny:
+		s = "deny"
+	default:
+		s = fmt.Sprintf("Unknown(%d)", a)
+	}
+	return
+}
+
+// FilterDirection represents the direction of an IPFilterRule.
+type FilterDirection uint8
+
+// constants that define FilterDirection.
+const (
+	FilterDirectionIn  FilterDirection = iota // RFC6733 4.3.1.  in
+	FilterDirectionOut                        // RFC6733 4.3.1.  out
+)
+
+// String returns a string version of a FilterDirection.
+func (d FilterDirection) String() (s string) {
+	switch d {
+	case FilterDirectionIn:
+		s = "in"
+	case FilterDirectionOut:
+		s = "out"
+	default:
+		s = fmt.Sprintf("Unknown(%d)", d)
+	}
+	return
+}
+
+// FilterPortRange represents an inclusive range of ports of an IPFilterRule
+// endpoint. A single port has Low equal to High.
+type FilterPortRange struct {
+	Low  uint16
+	High uint16
+}
+
+// FilterEndpoint represents the source or destination of an IPFilterRule.
+// Network is nil for the "any" and "assigned" keywords.
+type FilterEndpoint struct {
+	Not      bool
+	Any      bool
+	Assigned bool
+	Network  *net.IPNet
+	Ports    []FilterPortRange
+}
+
+// FilterRule represents a parsed IPFilterRule, the format of NAS-Filter-Rule
+// (RFC4849), e.g. "permit in ip from any to 10.0.0.0/8". Protocol is "ip"
+// for any protocol, or as it is written. Options holds the tokens following
+// the destination, such as "established" or "tcpflags syn,!ack".
+type FilterRule struct {
+	Action      FilterAction
+	Direction   FilterDirection
+	Protocol    string
+	Source      FilterEndpoint
+	Destination FilterEndpoint
+	Options     []string
+}
+
+// ParseFilterRule parses an IPFilterRule (RFC6733 4.3.1):
+//
+//	action dir proto from src to dst [options]
+//
+// where src and dst are "[!] any|assigned|addr[/mask] [ports]".
+func ParseFilterRule(s string) (*FilterRule, error) {
+	fields := strings.Fields(s)
+	if len(fields) < 7 {
+		return nil, fmt.Errorf("RADIUS IPFilterRule %q too short", s)
+	}
+
+	rule := &FilterRule{}
+	switch fields[0] {
+	case "permit":
+		rule.Action = FilterActionPermit
+	case "deny":
+		rule.Action = FilterActionDeny
+	default:
+		return nil, fmt.Errorf("RADIUS IPFilterRule %q action %q invalid", s, fields[0])
+	}
+	switch fields[1] {
+	case "in":
+		rule.Direction = FilterDirectionIn
+	case "out":
+		rule.Direction = FilterDirectionOut
+	default:
+		return nil, fmt.Errorf("RADIUS IPFilterRule %q direction %q invalid", s, fields[1])
+	}
+	if !validFilterProtocol(fields[2]) {
+		return nil, fmt.Errorf("RADIUS IPFilterRule %q protocol %q invalid", s, fields[2])
+	}
+	rule.Protocol = fields[2]
+	if fields[3] != "from" {
+		return nil, fmt.Errorf("RADIUS IPFilterRule %q expected from, got %q", s, fields[3])
+	}
+
+	rest, err := parseFilterEndpoint(fields[4:], &rule.Source)
+	if err != nil {
+		return nil, fmt.Errorf("RADIUS IPFilterRule %q source: %s", s, err)
+	}
+	if len(rest) == 0 || rest[0] != "to" {
+		return nil, fmt.Errorf("RADIUS IPFilterRule %q expected to", s)
+	}
+	rest, err = parseFilterEndpoint(rest[1:], &rule.Destination)
+	if err != nil {
+		return nil, fmt.Errorf("RADIUS IPFilterRule %q destination: %s", s, err)
+	}
+	if len(rest) > 0 {
+		rule.Options = rest
+	}
+	return rule, nil
+}
+
+// validFilterProtocol reports whether proto is "ip", a protocol number, or a
+// common protocol name.
+func validFilterProtocol(proto string) bool {
+	switch proto {
+	case "ip", "icmp", "tcp", "udp", "sctp", "ipv6-icmp":
+		return true
+	}
+	_, err := strconv.ParseUint(proto, 10, 8)
+	return err == nil
+}
+
+// parseFilterEndpoint parses an endpoint from the start of fields and returns
+// the remaining fields.
+func parseFilterEndpoint(fields []string, e *FilterEndpoint) ([]string, error) {
+	if len(fields) > 0 && fields[0] == "!" {
+		e.Not = true
+		fields = fields[1:]
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("address missing")
+	}
+
+	switch addr := fields[0]; addr {
+	case "any":
+		e.Any = true
+	case "assigned":
+		e.Assigned = true
+	default:
+		if !strings.Contains(addr, "/") {
+			ip := net.ParseIP(addr)
+			if ip == nil {
+				return nil, fmt.Errorf("address %q invalid", addr)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			e.Network = &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+		} else {
+			_, network, err := net.ParseCIDR(addr)
+			if err != nil {
+				return nil, fmt.Errorf("address %q invalid", addr)
+			}
+			e.Network = network
+		}
+	}
+	fields = fields[1:]
+
+	if len(fields) > 0 && fields[0] != "to" {
+		if ports, err := parseFilterPorts(fields[0]); err == nil {
+			e.Ports = ports
+			fields = fields[1:]
+		}
+	}
+	return fields, nil
+}
+
+// parseFilterPorts parses a comma separated list of ports and port ranges,
+// e.g. "80,443,8000-8080".
+func parseFilterPorts(s string) ([]FilterPortRange, error) {
+	var ports []FilterPortRange
+	for _, p := range strings.Split(s, ",") {
+		low, high := p, p
+		if i := strings.Index(p, "-"); i >= 0 {
+			low, high = p[:i], p[i+1:]
+		}
+		l, err := strconv.ParseUint(low, 10, 16)
+		if err != nil {
+			return nil, err
+		}
+		h, err := strconv.ParseUint(high, 10, 16)
+		if err != nil {
+			return nil, err
+		}
+		if l > h {
+			return nil, fmt.Errorf("port range %q invalid", p)
+		}
+		ports = append(ports, FilterPortRange{Low: uint16(l), High: uint16(h)})
+	}
+	return ports, nil
+}
+
+// NASFilterRules returns the rules of the NAS-Filter-Rule attributes. Rules
+// are separated by NUL and may span consecutive attributes (RFC4849 2.).
+func (radius *RADIUS) NASFilterRules() []string {
+	var data []byte
+	for _, v := range radius.Attributes {
+		if v.Type == RADIUSAttributeTypeNASFilterRule {
+			data = append(data, v.Value...)
+		}
+	}
+
+	var rules []string
+	for _, rule := range bytes.Split(data, []byte{0x00}) {
+		if len(rule) > 0 {
+			rules = append(rules, string(rule))
+		}
+	}
+	return rules
+}
+
+// FilterRules parses the rules of the NAS-Filter-Rule attributes. Malformed
+// rules are skipped and the first of their errors is returned along with the
+// valid rules.
+func (radius *RADIUS) FilterRules() ([]FilterRule, error) {
+	var rules []FilterRule
+	var firstErr error
+	for _, s := range radius.NASFilterRules() {
+		rule, err := ParseFilterRule(s)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		rules = append(rules, *rule)
+	}
+	return rules, firstErr
+}
@@ -0,0 +1,115 @@
+package radius
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestParseFilterRule(t *testing.T) {
+	mustCIDR := func(s string) *net.IPNet {
+		_, network, err := net.ParseCIDR(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return network
+	}
+
+	tests := []struct {
+		desc    string
+		s       string
+		want    *FilterRule
+		wantErr bool
+	}{
+		{
+			desc: "Network",
+			s:    "permit in ip from any to 10.0.0.0/8",
+			want: &FilterRule{
+				Action:      FilterActionPermit,
+				Direction:   FilterDirectionIn,
+				Protocol:    "ip",
+				Source:      FilterEndpoint{Any: true},
+				Destination: FilterEndpoint{Network: mustCIDR("10.0.0.0/8")},
+			},
+		},
+		{
+			desc: "PortsAndOptions",
+			s:    "deny out 6 from ! 192.0.2.1 1024-65535 to assigned 80,443 established",
+			want: &FilterRule{
+				Action:    FilterActionDeny,
+				Direction: FilterDirectionOut,
+				Protocol:  "6",
+				Source: FilterEndpoint{
+					Not:     true,
+					Network: mustCIDR("192.0.2.1/32"),
+					Ports:   []FilterPortRange{{1024, 65535}},
+				},
+				Destination: FilterEndpoint{
+					Assigned: true,
+					Ports:    []FilterPortRange{{80, 80}, {443, 443}},
+				},
+				Options: []string{"established"},
+			},
+		},
+		{
+			desc: "IPv6",
+			s:    "permit out udp from 2001:db8::/32 to 2001:db8::1 53",
+			want: &FilterRule{
+				Action:      FilterActionPermit,
+				Direction:   FilterDirectionOut,
+				Protocol:    "udp",
+				Source:      FilterEndpoint{Network: mustCIDR("2001:db8::/32")},
+				Destination: FilterEndpoint{Network: mustCIDR("2001:db8::1/128"), Ports: []FilterPortRange{{53, 53}}},
+			},
+		},
+		{desc: "Action", s: "allow in ip from any to any", wantErr: true},
+		{desc: "Direction", s: "permit both ip from any to any", wantErr: true},
+		{desc: "Protocol", s: "permit in 256 from any to any", wantErr: true},
+		{desc: "From", s: "permit in ip any to any any", wantErr: true},
+		{desc: "To", s: "permit in ip from any any any", wantErr: true},
+		{desc: "Address", s: "permit in ip from 10.0.0.256 to any", wantErr: true},
+		{desc: "MissingDestination", s: "permit in ip from any 80 to", wantErr: true},
+		{desc: "Short", s: "permit in ip", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFilterRule(tt.s)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error, got %+v", tt.desc, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.desc, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v want %+v", tt.desc, got, tt.want)
+		}
+	}
+}
+
+func TestRADIUSFilterRules(t *testing.T) {
+	radius := &RADIUS{Attributes: []RADIUSAttribute{
+		newAttribute(RADIUSAttributeTypeNASFilterRule, []byte("permit in ip from any to 10.0.0.0/8\x00deny in ip fr")),
+		newAttribute(RADIUSAttributeTypeNASFilterRule, []byte("om any to any\x00drop in ip from any to any\x00")),
+	}}
+
+	want := []string{
+		"permit in ip from any to 10.0.0.0/8",
+		"deny in ip from any to any",
+		"drop in ip from any to any",
+	}
+	if got := radius.NASFilterRules(); !reflect.DeepEqual(got, want) {
+		t.Errorf("NASFilterRules got %q want %q", got, want)
+	}
+
+	rules, err := radius.FilterRules()
+	if err == nil {
+		t.Error("FilterRules expected error for the malformed rule")
+	}
+	if len(rules) != 2 || rules[0].Action != FilterActionPermit || rules[1].Action != FilterActionDeny {
+		t.Errorf("FilterRules got %+v", rules)
+	}
+}
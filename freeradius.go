@@ -0,0 +1,317 @@
+package radius
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// radiusDictionaryMaxIncludeDepth bounds the nesting of $INCLUDE lines, so
+// that an include loop fails instead of recursing forever.
+const radiusDictionaryMaxIncludeDepth = 16
+
+// Dictionary holds attribute names, value types and value names loaded from
+// dictionary files in the FreeRADIUS format. The zero value is an empty
+// dictionary ready to use.
+type Dictionary struct {
+	attributes map[dictionaryKey]*dictionaryAttribute
+	names      map[string]*dictionaryAttribute
+	vendors    map[string]uint32
+	values     map[string]map[uint64]string
+}
+
+// dictionaryKey identifies an attribute, with a zero vendor ID for the
+// standard attributes.
+type dictionaryKey struct {
+	vendorID uint32
+	t        uint8
+}
+
+// dictionaryAttribute is an ATTRIBUTE line of a dictionary.
+type dictionaryAttribute struct {
+	name      string
+	key       dictionaryKey
+	valueType string
+	tagged    bool
+}
+
+// LoadFile loads the ATTRIBUTE, VALUE and VENDOR lines of a dictionary file,
+// following its $INCLUDE lines, and vendor blocks delimited by BEGIN-VENDOR
+// and END-VENDOR. Later definitions replace earlier ones. Attributes whose
+// number does not fit the RFC2865 format, such as the extended attributes of
+// FreeRADIUS 3 ("241.1"), and other keywords are skipped.
+func (d *Dictionary) LoadFile(path string) error {
+	return d.loadFile(path, 0)
+}
+
+func (d *Dictionary) loadFile(path string, depth int) error {
+	if depth > radiusDictionaryMaxIncludeDepth {
+		return fmt.Errorf("RADIUS dictionary %s: $INCLUDE nested too deeply", path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var vendorID uint32
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+
+		var err error
+		switch fields[0] {
+		case "$INCLUDE":
+			if len(fields) < 2 {
+				return fmt.Errorf("RADIUS dictionary %s:%d: $INCLUDE without file", path, line)
+			}
+			include := fields[1]
+			if !filepath.IsAbs(include) {
+				include = filepath.Join(filepath.Dir(path), include)
+			}
+			if err = d.loadFile(include, depth+1); err != nil {
+				return err
+			}
+		case "ATTRIBUTE":
+			err = d.parseAttribute(fields, vendorID)
+		case "VALUE":
+			err = d.parseValue(fields)
+		case "VENDOR":
+			err = d.parseVendor(fields)
+		case "BEGIN-VENDOR":
+			if len(fields) < 2 {
+				err = fmt.Errorf("BEGIN-VENDOR without vendor")
+			} else if id, ok := d.vendors[fields[1]]; ok {
+				vendorID = id
+			} else {
+				err = fmt.Errorf("vendor %s unknown", fields[1])
+			}
+		case "END-VENDOR":
+			vendorID = 0
+		}
+		if err != nil {
+			return fmt.Errorf("RADIUS dictionary %s:%d: %s", path, line, err)
+		}
+	}
+	return scanner.Err()
+}
+
+// parseAttribute parses "ATTRIBUTE name number type [vendor|flags]".
+func (d *Dictionary) parseAttribute(fields []string, vendorID uint32) error {
+	if len(fields) < 4 {
+		return fmt.Errorf("ATTRIBUTE needs a name, a number and a type")
+	}
+	if strings.Contains(fields[2], ".") {
+		return nil
+	}
+	n, err := strconv.ParseUint(fields[2], 0, 32)
+	if err != nil {
+		return fmt.Errorf("ATTRIBUTE %s number %q invalid", fields[1], fields[2])
+	}
+	if n > 255 {
+		return nil
+	}
+
+	attr := &dictionaryAttribute{
+		name:      fields[1],
+		key:       dictionaryKey{vendorID: vendorID, t: uint8(n)},
+		valueType: fields[3],
+	}
+	if i := strings.IndexByte(attr.valueType, '['); i >= 0 {
+		attr.valueType = attr.valueType[:i]
+	}
+	if len(fields) > 4 {
+		if id, ok := d.vendors[fields[4]]; ok {
+			attr.key.vendorID = id
+		} else {
+			for _, flag := range strings.Split(fields[4], ",") {
+				if flag == "has_tag" {
+					attr.tagged = true
+				}
+			}
+		}
+	}
+
+	if d.attributes == nil {
+		d.attributes = make(map[dictionaryKey]*dictionaryAttribute)
+		d.names = make(map[string]*dictionaryAttribute)
+	}
+	d.attributes[attr.key] = attr
+	d.names[attr.name] = attr
+	return nil
+}
+
+// parseValue parses "VALUE attribute-name value-name number".
+func (d *Dictionary) parseValue(fields []string) error {
+	if len(fields) < 4 {
+		return fmt.Errorf("VALUE needs an attribute, a name and a number")
+	}
+	n, err := strconv.ParseUint(fields[3], 0, 64)
+	if err != nil {
+		return fmt.Errorf("VALUE %s %s number %q invalid", fields[1], fields[2], fields[3])
+	}
+	if d.values == nil {
+		d.values = make(map[string]map[uint64]string)
+	}
+	if d.values[fields[1]] == nil {
+		d.values[fields[1]] = make(map[uint64]string)
+	}
+	d.values[fields[1]][n] = fields[2]
+	return nil
+}
+
+// parseVendor parses "VENDOR name number [format]".
+func (d *Dictionary) parseVendor(fields []string) error {
+	if len(fields) < 3 {
+		return fmt.Errorf("VENDOR needs a name and a number")
+	}
+	n, err := strconv.ParseUint(fields[2], 0, 32)
+	if err != nil {
+		return fmt.Errorf("VENDOR %s number %q invalid", fields[1], fields[2])
+	}
+	if d.vendors == nil {
+		d.vendors = make(map[string]uint32)
+	}
+	d.vendors[fields[1]] = uint32(n)
+	return nil
+}
+
+// NameForAttribute returns the name of an attribute type in the dictionary,
+// or its built-in name if the dictionary does not define it.
+func (d *Dictionary) NameForAttribute(t RADIUSAttributeType) string {
+	if attr, ok := d.attributes[dictionaryKey{t: uint8(t)}]; ok {
+		return attr.name
+	}
+	return t.String()
+}
+
+// AttributeForName returns the attribute type of a name in the dictionary,
+// or of a built-in name if the dictionary does not define it. It reports
+// false for the names of vendor attributes.
+func (d *Dictionary) AttributeForName(name string) (RADIUSAttributeType, bool) {
+	if attr, ok := d.names[name]; ok {
+		if attr.key.vendorID != 0 {
+			return 0, false
+		}
+		return RADIUSAttributeType(attr.key.t), true
+	}
+	return attributeTypeForName(name)
+}
+
+// NameForVendorAttribute returns the name of a vendor attribute type in the
+// dictionary, or its built-in name if the dictionary does not define it.
+func (d *Dictionary) NameForVendorAttribute(vendorID uint32, t uint8) string {
+	if attr, ok := d.attributes[dictionaryKey{vendorID: vendorID, t: t}]; ok {
+		return attr.name
+	}
+	return VendorAttributeName(vendorID, t)
+}
+
+// VendorForName returns the vendor ID of a VENDOR name in the dictionary.
+func (d *Dictionary) VendorForName(name string) (uint32, bool) {
+	id, ok := d.vendors[name]
+	return id, ok
+}
+
+// ValueName returns the name the VALUE lines of the dictionary give to an
+// integer value of an attribute type, e.g. "Framed-User" for Service-Type 2.
+func (d *Dictionary) ValueName(t RADIUSAttributeType, n uint32) (string, bool) {
+	s, ok := d.values[d.NameForAttribute(t)][uint64(n)]
+	return s, ok
+}
+
+// DecodeValue decodes the value of an attribute according to the type the
+// dictionary declares for it: string for string, uint8, uint16, uint32 and
+// uint64 for byte, short, integer and integer64, net.IP for ipaddr and
+// ipv6addr, *net.IPNet for ipv6prefix, time.Time for date,
+// net.HardwareAddr for ether, and []byte for octets and the other types. The
+// tag of has_tag attributes is stripped. Attributes the dictionary does not
+// define are decoded by DecodedValue.
+func (d *Dictionary) DecodeValue(attr RADIUSAttribute) (interface{}, error) {
+	a, ok := d.attributes[dictionaryKey{t: uint8(attr.Type)}]
+	if !ok {
+		return attr.DecodedValue()
+	}
+	return a.decode(attr.Value)
+}
+
+// DecodeVendorValue decodes the value of a vendor sub-attribute according to
+// the type the dictionary declares for it, as DecodeValue does. Sub-attributes
+// the dictionary does not define are decoded according to
+// VendorAttributeValueType.
+func (d *Dictionary) DecodeVendorValue(vendorID uint32, sub RADIUSVendorAttribute) (interface{}, error) {
+	a, ok := d.attributes[dictionaryKey{vendorID: vendorID, t: sub.Type}]
+	if !ok {
+		a = &dictionaryAttribute{
+			name:      VendorAttributeName(vendorID, sub.Type),
+			valueType: VendorAttributeValueType(vendorID, sub.Type).String(),
+		}
+	}
+	return a.decode(sub.Value)
+}
+
+// decode decodes a value according to the declared type of the attribute.
+func (a *dictionaryAttribute) decode(v RADIUSAttributeValue) (interface{}, error) {
+	if a.tagged && len(v) > 0 {
+		switch {
+		case a.valueType == "integer":
+			v = append(RADIUSAttributeValue{0x00}, v[1:]...)
+		case v[0] <= 0x1f:
+			v = v[1:]
+		}
+	}
+
+	want := 0
+	switch a.valueType {
+	case "byte":
+		want = 1
+	case "short":
+		want = 2
+	case "integer", "ipaddr", "date":
+		want = 4
+	case "ether":
+		want = 6
+	case "integer64":
+		want = 8
+	}
+	if want != 0 && len(v) != want {
+		return nil, fmt.Errorf("RADIUS %s %s length %d invalid, want %d", a.name, a.valueType, len(v), want)
+	}
+
+	switch a.valueType {
+	case "string":
+		return string(v), nil
+	case "byte":
+		return v[0], nil
+	case "short":
+		return binary.BigEndian.Uint16(v), nil
+	case "integer":
+		return binary.BigEndian.Uint32(v), nil
+	case "integer64":
+		return binary.BigEndian.Uint64(v), nil
+	case "ipaddr":
+		return net.IP(append([]byte(nil), v...)), nil
+	case "ipv6addr":
+		return v.IPv6()
+	case "ipv6prefix":
+		return v.IPv6Prefix()
+	case "date":
+		return v.Time()
+	case "ether":
+		return net.HardwareAddr(append([]byte(nil), v...)), nil
+	default:
+		return []byte(v), nil
+	}
+}
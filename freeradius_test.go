@@ -0,0 +1,163 @@
+package radius
+
+import (
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testDictionary = `# site dictionary
+ATTRIBUTE	User-Name		1	string
+ATTRIBUTE	Service-Type		6	integer
+ATTRIBUTE	Tunnel-Private-Group-Id	81	string	has_tag
+ATTRIBUTE	Site-Counter		200	integer64
+ATTRIBUTE	Site-Address		201	ipaddr
+ATTRIBUTE	Site-Extended		241.1	integer
+
+VALUE	Service-Type		Login-User		1
+VALUE	Service-Type		Framed-User		2
+
+$INCLUDE dictionary.acme
+`
+
+const testDictionaryAcme = `VENDOR		Acme		99999
+BEGIN-VENDOR	Acme
+ATTRIBUTE	Acme-Group		1	string
+ATTRIBUTE	Acme-Level		2	short
+END-VENDOR	Acme
+ATTRIBUTE	Acme-Old-Style		3	byte	Acme
+`
+
+func loadTestDictionary(t *testing.T) *Dictionary {
+	dir, err := ioutil.TempDir("", "dictionary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	for name, content := range map[string]string{
+		"dictionary":      testDictionary,
+		"dictionary.acme": testDictionaryAcme,
+	} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d := &Dictionary{}
+	if err := d.LoadFile(filepath.Join(dir, "dictionary")); err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestDictionaryNames(t *testing.T) {
+	d := loadTestDictionary(t)
+
+	if s := d.NameForAttribute(200); s != "Site-Counter" {
+		t.Errorf("NameForAttribute(200) got %q want Site-Counter", s)
+	}
+	if s := d.NameForAttribute(RADIUSAttributeTypeNASPort); s != "NAS-Port" {
+		t.Errorf("NameForAttribute(NAS-Port) got %q want NAS-Port", s)
+	}
+	if s := d.NameForVendorAttribute(99999, 2); s != "Acme-Level" {
+		t.Errorf("NameForVendorAttribute(99999, 2) got %q want Acme-Level", s)
+	}
+	if s := d.NameForVendorAttribute(99999, 3); s != "Acme-Old-Style" {
+		t.Errorf("NameForVendorAttribute(99999, 3) got %q want Acme-Old-Style", s)
+	}
+
+	tests := []struct {
+		name string
+		t    RADIUSAttributeType
+		ok   bool
+	}{
+		{"Site-Address", 201, true},
+		{"User-Name", RADIUSAttributeTypeUserName, true},
+		{"NAS-Port", RADIUSAttributeTypeNASPort, true},
+		{"Acme-Group", 0, false},
+		{"Site-Extended", 0, false},
+		{"Nonexistent", 0, false},
+	}
+	for _, tt := range tests {
+		if got, ok := d.AttributeForName(tt.name); got != tt.t || ok != tt.ok {
+			t.Errorf("AttributeForName(%q) got %d, %v want %d, %v", tt.name, got, ok, tt.t, tt.ok)
+		}
+	}
+
+	if id, ok := d.VendorForName("Acme"); !ok || id != 99999 {
+		t.Errorf("VendorForName got %d, %v want 99999", id, ok)
+	}
+	if s, ok := d.ValueName(RADIUSAttributeTypeServiceType, 2); !ok || s != "Framed-User" {
+		t.Errorf("ValueName got %q, %v want Framed-User", s, ok)
+	}
+	if _, ok := d.ValueName(RADIUSAttributeTypeServiceType, 3); ok {
+		t.Error("ValueName(3): expected no name")
+	}
+}
+
+func TestDictionaryDecodeValue(t *testing.T) {
+	d := loadTestDictionary(t)
+
+	tests := []struct {
+		desc string
+		attr RADIUSAttribute
+		want interface{}
+	}{
+		{"integer64", newAttribute(200, []byte{0, 0, 0, 1, 0, 0, 0, 2}), uint64(1<<32 + 2)},
+		{"ipaddr", newAttribute(201, []byte{192, 0, 2, 1}), net.IP{192, 0, 2, 1}},
+		{"has_tag", newAttribute(RADIUSAttributeTypeTunnelPrivateGroupID, []byte("\x01100")), "100"},
+		{"has_tag without tag", newAttribute(RADIUSAttributeTypeTunnelPrivateGroupID, []byte("100")), "100"},
+		{"not in dictionary", newAttribute(RADIUSAttributeTypeNASPort, uint32Value(7)), uint32(7)},
+	}
+	for _, tt := range tests {
+		got, err := d.DecodeValue(tt.attr)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.desc, err)
+		} else if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %#v want %#v", tt.desc, got, tt.want)
+		}
+	}
+
+	if _, err := d.DecodeValue(newAttribute(200, []byte{0, 1})); err == nil {
+		t.Error("integer64 of 2 bytes: expected error")
+	}
+
+	got, err := d.DecodeVendorValue(99999, RADIUSVendorAttribute{Type: 2, Value: RADIUSAttributeValue{0x01, 0x00}})
+	if err != nil || got != uint16(256) {
+		t.Errorf("DecodeVendorValue(Acme-Level) got %#v, %v want 256", got, err)
+	}
+	got, err = d.DecodeVendorValue(RADIUSVendorIDCisco, RADIUSVendorAttribute{Type: RADIUSCiscoAttributeTypeAVPair, Value: RADIUSAttributeValue("shell:priv-lvl=15")})
+	if err != nil || got != "shell:priv-lvl=15" {
+		t.Errorf("DecodeVendorValue(Cisco-AVPair) got %#v, %v", got, err)
+	}
+}
+
+func TestDictionaryLoadFileErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dictionary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, content := range []string{
+		"ATTRIBUTE User-Name 1\n",
+		"ATTRIBUTE User-Name one string\n",
+		"VALUE Service-Type Framed-User\n",
+		"VENDOR Acme acme\n",
+		"BEGIN-VENDOR Acme\n",
+		"$INCLUDE missing\n",
+		"$INCLUDE dictionary\n",
+	} {
+		path := filepath.Join(dir, "dictionary")
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := (&Dictionary{}).LoadFile(path); err == nil {
+			t.Errorf("%q: expected error", content)
+		}
+	}
+}
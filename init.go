@@ -1,9 +1,19 @@
 package radius
 
 import (
+	"github.com/google/gopacket"
 	"github.com/google/gopacket/layers"
 )
 
+// radiusUDPPorts are the UDP ports RADIUS is decoded on: authentication and
+// accounting (RFC2865 3., RFC2866 3.), their legacy ports 1645 and 1646, and
+// Dynamic Authorization (RFC5176 3.).
+var radiusUDPPorts = []uint16{1812, 1813, 1645, 1646, 3799}
+
 func init() {
-	layers.RegisterUDPPortLayerType(1812, LayerTypeRADIUS)
+	LayerTypeRADIUSAttribute = gopacket.RegisterLayerType(1813, gopacket.LayerTypeMetadata{Name: "RADIUSAttribute", Decoder: gopacket.DecodeFunc(decodeRADIUSAttributeLayer)})
+
+	for _, port := range radiusUDPPorts {
+		layers.RegisterUDPPortLayerType(layers.UDPPort(port), LayerTypeRADIUS)
+	}
 }
@@ -0,0 +1,56 @@
+package radius
+
+import (
+	"fmt"
+	"net"
+)
+
+// IPv6 decodes the value as an ipv6addr, such as NAS-IPv6-Address.
+func (v RADIUSAttributeValue) IPv6() (net.IP, error) {
+	if len(v) != net.IPv6len {
+		return nil, fmt.Errorf("RADIUS ipv6addr length %d invalid", len(v))
+	}
+	ip := make(net.IP, net.IPv6len)
+	copy(ip, v)
+	return ip, nil
+}
+
+// IPv6Prefix decodes the value as an ipv6prefix, such as Framed-IPv6-Prefix:
+// a reserved byte, which must be 0, the prefix length of up to 128 bits, and
+// the bytes of the prefix covering at least the prefix length (RFC3162 2.3,
+// RFC8044 3.10). Bits beyond the prefix length are cleared.
+func (v RADIUSAttributeValue) IPv6Prefix() (*net.IPNet, error) {
+	if len(v) < 2 || len(v) > 2+net.IPv6len {
+		return nil, fmt.Errorf("RADIUS ipv6prefix length %d invalid", len(v))
+	}
+	if v[0] != 0 {
+		return nil, fmt.Errorf("RADIUS ipv6prefix reserved byte %#x not 0", v[0])
+	}
+	bits := int(v[1])
+	if bits > 8*net.IPv6len {
+		return nil, fmt.Errorf("RADIUS ipv6prefix length %d exceeds 128", bits)
+	}
+	if prefix := v[2:]; 8*len(prefix) < bits {
+		return nil, fmt.Errorf("RADIUS ipv6prefix length %d exceeds the %d prefix bytes", bits, len(prefix))
+	}
+
+	ip := make(net.IP, net.IPv6len)
+	copy(ip, v[2:])
+	mask := net.CIDRMask(bits, 8*net.IPv6len)
+	return &net.IPNet{IP: ip.Mask(mask), Mask: mask}, nil
+}
+
+// DNSServerIPv6Addresses returns the addresses of all DNS-Server-IPv6-Address
+// attributes in wire order, skipping malformed ones.
+func (radius *RADIUS) DNSServerIPv6Addresses() []net.IP {
+	var ips []net.IP
+	for _, v := range radius.Attributes {
+		if v.Type != RADIUSAttributeTypeDNSServerIPv6Address {
+			continue
+		}
+		if ip, err := v.IP(); err == nil {
+			ips = append(ips, ip)
+		}
+	}
+	return ips
+}
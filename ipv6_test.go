@@ -0,0 +1,78 @@
+package radius
+
+import (
+	"net"
+	"testing"
+)
+
+func TestRADIUSDNSServerIPv6Addresses(t *testing.T) {
+	radius := &RADIUS{Attributes: []RADIUSAttribute{
+		newAttribute(RADIUSAttributeTypeDNSServerIPv6Address, net.ParseIP("2001:db8::53")),
+		newAttribute(RADIUSAttributeTypeUserName, []byte("Admin")),
+		newAttribute(RADIUSAttributeTypeDNSServerIPv6Address, []byte{0x20, 0x01}),
+		newAttribute(RADIUSAttributeTypeDNSServerIPv6Address, net.ParseIP("2001:db8::5353")),
+	}}
+
+	got := radius.DNSServerIPv6Addresses()
+	want := []net.IP{net.ParseIP("2001:db8::53"), net.ParseIP("2001:db8::5353")}
+	if len(got) != len(want) {
+		t.Fatalf("got %v want %v", got, want)
+	}
+	for i := range want {
+		if !got[i].Equal(want[i]) {
+			t.Errorf("address %d got %v want %v", i, got[i], want[i])
+		}
+	}
+
+	if got := RADIUSAttributeTypeRouteIPv6Information.ValueType(); got != AttrValueTypeIPv6Prefix {
+		t.Errorf("Route-IPv6-Information value type got %v want %v", got, AttrValueTypeIPv6Prefix)
+	}
+}
+
+func TestRADIUSAttributeValueIPv6(t *testing.T) {
+	v := RADIUSAttributeValue(net.ParseIP("2001:db8::1"))
+	if ip, err := v.IPv6(); err != nil || !ip.Equal(net.ParseIP("2001:db8::1")) {
+		t.Errorf("got %v, %v", ip, err)
+	}
+	if _, err := RADIUSAttributeValue(net.IPv4(192, 0, 2, 1).To4()).IPv6(); err == nil {
+		t.Error("4 bytes: expected error")
+	}
+}
+
+func TestRADIUSAttributeValueIPv6Prefix(t *testing.T) {
+	tests := []struct {
+		desc    string
+		value   []byte
+		want    string
+		wantErr bool
+	}{
+		{"/64", []byte{0x00, 0x40, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x01, 0x00, 0x02}, "2001:db8:1:2::/64", false},
+		{"/64 full prefix", append([]byte{0x00, 0x40}, net.ParseIP("2001:db8:1:2::")...), "2001:db8:1:2::/64", false},
+		{"/64 host bits cleared", append([]byte{0x00, 0x40}, net.ParseIP("2001:db8:1:2::1")...), "2001:db8:1:2::/64", false},
+		{"/0", []byte{0x00, 0x00}, "::/0", false},
+		{"/128", append([]byte{0x00, 0x80}, net.ParseIP("2001:db8::1")...), "2001:db8::1/128", false},
+		{"too short", []byte{0x00}, "", true},
+		{"reserved", []byte{0x01, 0x40, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x01, 0x00, 0x02}, "", true},
+		{"length out of range", append([]byte{0x00, 0x81}, net.ParseIP("2001:db8::1")...), "", true},
+		{"prefix truncated", []byte{0x00, 0x40, 0x20, 0x01, 0x0d, 0xb8}, "", true},
+		{"prefix too long", append(append([]byte{0x00, 0x40}, net.ParseIP("2001:db8::1")...), 0x00), "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := RADIUSAttributeValue(tt.value).IPv6Prefix()
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error, got %v", tt.desc, got)
+			}
+			continue
+		}
+		if err != nil || got.String() != tt.want {
+			t.Errorf("%s: got %v, %v want %s", tt.desc, got, err, tt.want)
+		}
+	}
+
+	attr := newAttribute(RADIUSAttributeTypeFramedIPv6Prefix, tests[0].value)
+	if s := attr.String(); s != "Framed-IPv6-Prefix = 2001:db8:1:2::/64" {
+		t.Errorf("String() got %q", s)
+	}
+}
@@ -0,0 +1,237 @@
+package radius
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"time"
+	"unicode/utf8"
+)
+
+// radiusJSON is the JSON representation of a RADIUS packet.
+type radiusJSON struct {
+	Code          string                `json:"code"`
+	Identifier    RADIUSIdentifier      `json:"identifier"`
+	Length        RADIUSLength          `json:"length"`
+	Authenticator string                `json:"authenticator"`
+	Attributes    []radiusAttributeJSON `json:"attributes"`
+}
+
+// radiusAttributeJSON is the JSON representation of an attribute: Value
+// holds the value in its natural type, or Hex holds it in hex.
+type radiusAttributeJSON struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value,omitempty"`
+	Hex   *string         `json:"hex,omitempty"`
+}
+
+// MarshalJSON returns the JSON representation of the packet, with the code
+// and the attribute types by name, the authenticator in hex and the
+// attribute values in their natural type:
+//
+//	{"code":"Access-Request","identifier":1,"length":38,
+//	 "authenticator":"...","attributes":[{"type":"User-Name","value":"Admin"},
+//	 {"type":"NAS-Port","value":0},{"type":"NAS-IP-Address","value":"127.0.1.1"},
+//	 {"type":"Class","hex":"0102"}]}
+//
+// A value is in hex, as "hex" instead of "value", for octets, tagged and
+// unknown attribute types, and whenever its natural type would not
+// reproduce the bytes of the value, e.g. a truncated integer. This keeps the
+// representation lossless for UnmarshalJSON.
+func (radius *RADIUS) MarshalJSON() ([]byte, error) {
+	r := radiusJSON{
+		Code:          radius.Code.String(),
+		Identifier:    radius.Identifier,
+		Length:        radius.Length,
+		Authenticator: radius.Authenticator.String(),
+		Attributes:    make([]radiusAttributeJSON, len(radius.Attributes)),
+	}
+	for i, v := range radius.Attributes {
+		r.Attributes[i].Type = v.Type.String()
+		if value, ok := jsonValue(v); ok {
+			r.Attributes[i].Value = value
+		} else {
+			h := hex.EncodeToString(v.Value)
+			r.Attributes[i].Hex = &h
+		}
+	}
+	return json.Marshal(r)
+}
+
+// UnmarshalJSON reconstructs the packet from its MarshalJSON representation.
+// The attribute lengths are computed from the values, and so is the packet
+// length when it is absent.
+func (radius *RADIUS) UnmarshalJSON(data []byte) error {
+	var r radiusJSON
+	if err := json.Unmarshal(data, &r); err != nil {
+		return err
+	}
+
+	code, ok := radiusCodeForName(r.Code)
+	if !ok {
+		return fmt.Errorf("RADIUS JSON code %q unknown", r.Code)
+	}
+	auth, err := ParseAuthenticator(r.Authenticator)
+	if err != nil {
+		return err
+	}
+	attrs := make([]RADIUSAttribute, len(r.Attributes))
+	length := radiusMinimumRecordSizeInBytes
+	for i, a := range r.Attributes {
+		t, ok := attributeTypeForName(a.Type)
+		if !ok {
+			return fmt.Errorf("RADIUS JSON attribute type %q unknown", a.Type)
+		}
+		var value []byte
+		switch {
+		case a.Hex != nil:
+			if value, err = hex.DecodeString(*a.Hex); err != nil {
+				return fmt.Errorf("RADIUS JSON %s hex invalid: %s", t, err)
+			}
+		case a.Value != nil:
+			if value, err = parseJSONValue(t, a.Value); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("RADIUS JSON %s has no value", t)
+		}
+		if len(value) > radiusMaximumAttributeValueSizeInBytes {
+			return fmt.Errorf("RADIUS JSON %s length %d exceeds %d", t, len(value), radiusMaximumAttributeValueSizeInBytes)
+		}
+		attrs[i] = newAttribute(t, value)
+		length += len(value) + 2
+	}
+
+	*radius = RADIUS{
+		Code:          code,
+		Identifier:    r.Identifier,
+		Length:        r.Length,
+		Authenticator: auth,
+		Attributes:    attrs,
+	}
+	if radius.Length == 0 {
+		radius.Length = RADIUSLength(length)
+	}
+	return nil
+}
+
+// jsonValue returns the value of the attribute in its natural JSON type, if
+// it has one that reproduces the bytes of the value.
+func jsonValue(a RADIUSAttribute) (json.RawMessage, bool) {
+	if a.Type.IsTagged() {
+		return nil, false
+	}
+
+	var natural interface{}
+	v := a.Value
+	switch a.Type.ValueType() {
+	case AttrValueTypeString:
+		if !utf8.Valid(v) {
+			return nil, false
+		}
+		natural = string(v)
+	case AttrValueTypeInteger:
+		n, err := v.Uint32()
+		if err != nil {
+			return nil, false
+		}
+		natural = n
+	case AttrValueTypeIPAddr:
+		if len(v) != net.IPv4len {
+			return nil, false
+		}
+		natural = net.IP(v).String()
+	case AttrValueTypeIPv6Addr:
+		ip, err := v.IPv6()
+		if err != nil {
+			return nil, false
+		}
+		natural = ip.String()
+	case AttrValueTypeIPv6Prefix:
+		prefix, err := v.IPv6Prefix()
+		if err != nil {
+			return nil, false
+		}
+		natural = prefix.String()
+	case AttrValueTypeDate:
+		t, err := v.Time()
+		if err != nil {
+			return nil, false
+		}
+		natural = t.Format(time.RFC3339)
+	default:
+		return nil, false
+	}
+
+	data, err := json.Marshal(natural)
+	if err != nil {
+		return nil, false
+	}
+	if value, err := parseJSONValue(a.Type, data); err != nil || !bytes.Equal(value, v) {
+		return nil, false
+	}
+	return data, true
+}
+
+// parseJSONValue encodes a value in the natural JSON type of t, the reverse
+// of jsonValue.
+func parseJSONValue(t RADIUSAttributeType, data json.RawMessage) ([]byte, error) {
+	valueType := t.ValueType()
+	if valueType == AttrValueTypeInteger {
+		var n uint32
+		if err := json.Unmarshal(data, &n); err != nil {
+			return nil, fmt.Errorf("RADIUS JSON %s integer invalid: %s", t, err)
+		}
+		return uint32Value(n), nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("RADIUS JSON %s %s invalid: %s", t, valueType, err)
+	}
+	switch valueType {
+	case AttrValueTypeString:
+		return []byte(s), nil
+	case AttrValueTypeIPAddr:
+		if ip := net.ParseIP(s).To4(); ip != nil {
+			return ip, nil
+		}
+	case AttrValueTypeIPv6Addr:
+		if ip := net.ParseIP(s); ip != nil && ip.To4() == nil {
+			return ip, nil
+		}
+	case AttrValueTypeIPv6Prefix:
+		if ip, network, err := net.ParseCIDR(s); err == nil && ip.To4() == nil {
+			bits, _ := network.Mask.Size()
+			return append([]byte{0x00, byte(bits)}, network.IP[:(bits+7)/8]...), nil
+		}
+	case AttrValueTypeDate:
+		if ts, err := time.Parse(time.RFC3339, s); err == nil && ts.Unix() >= 0 && ts.Unix() <= 0xffffffff {
+			return uint32Value(uint32(ts.Unix())), nil
+		}
+	}
+	return nil, fmt.Errorf("RADIUS JSON %s %s %q invalid", t, valueType, s)
+}
+
+// radiusCodeForName returns the RADIUSCode whose String is name.
+func radiusCodeForName(name string) (RADIUSCode, bool) {
+	for i := 0; i < 256; i++ {
+		if code := RADIUSCode(i); code.String() == name {
+			return code, true
+		}
+	}
+	return 0, false
+}
+
+// attributeTypeForName returns the RADIUSAttributeType whose String is name,
+// including the attribute types added with RegisterAttribute.
+func attributeTypeForName(name string) (RADIUSAttributeType, bool) {
+	for i := 0; i < 256; i++ {
+		if t := RADIUSAttributeType(i); t.String() == name {
+			return t, true
+		}
+	}
+	return 0, false
+}
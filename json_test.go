@@ -0,0 +1,111 @@
+package radius
+
+import (
+	"encoding/json"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestRADIUSMarshalJSON(t *testing.T) {
+	radius := decodeTestRADIUS(t, testRADIUSAccessRequest)
+	data, err := json.Marshal(radius)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["code"] != "Access-Request" || got["authenticator"] != radius.Authenticator.String() {
+		t.Errorf("header got %s", data)
+	}
+	attrs := got["attributes"].([]interface{})
+	want := []map[string]interface{}{
+		{"type": "User-Name", "value": "Admin"},
+		{"type": "User-Password", "hex": attrs[1].(map[string]interface{})["hex"]},
+		{"type": "NAS-IP-Address", "value": "127.0.1.1"},
+		{"type": "NAS-Port", "value": float64(0)},
+		{"type": "Message-Authenticator", "hex": "4173ed26d3b3a964ff4dc30d9433e82a"},
+	}
+	if len(attrs) != len(want) {
+		t.Fatalf("got %d attributes want %d: %s", len(attrs), len(want), data)
+	}
+	for i := range want {
+		if !reflect.DeepEqual(attrs[i], want[i]) {
+			t.Errorf("attribute %d got %v want %v", i, attrs[i], want[i])
+		}
+	}
+
+	var decoded RADIUS
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	roundTrip, err := decoded.serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(roundTrip, testRADIUSAccessRequest) {
+		t.Errorf("round trip got %x want %x", roundTrip, testRADIUSAccessRequest)
+	}
+}
+
+func TestRADIUSJSONValues(t *testing.T) {
+	radius := NewRADIUS(RADIUSCodeAccountingRequest, 7).
+		AddUint32(RADIUSAttributeTypeEventTimestamp, 1500000000).
+		AddRaw(RADIUSAttributeTypeNASIPv6Address, net.ParseIP("2001:db8::1")).
+		AddRaw(RADIUSAttributeTypeFramedIPv6Prefix, []byte{0x00, 0x40, 0x20, 0x01, 0x0d, 0xb8, 0x00, 0x01, 0x00, 0x02}).
+		AddRaw(RADIUSAttributeTypeFramedIPv6Prefix, append([]byte{0x00, 0x40}, net.ParseIP("2001:db8:1:2::")...)).
+		AddRaw(RADIUSAttributeTypeNASPort, []byte{0x00, 0x01}).
+		AddRaw(RADIUSAttributeTypeUserName, []byte{0xff, 0xfe}).
+		AddRaw(RADIUSAttributeTypeTunnelPrivateGroupID, []byte("\x01100")).
+		AddRaw(RADIUSAttributeType(250), []byte{0x01}).
+		Build()
+
+	data, err := json.Marshal(radius)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got struct {
+		Attributes []map[string]interface{}
+	}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []map[string]interface{}{
+		{"type": "Event-Timestamp", "value": "2017-07-14T02:40:00Z"},
+		{"type": "NAS-IPv6-Address", "value": "2001:db8::1"},
+		{"type": "Framed-IPv6-Prefix", "value": "2001:db8:1:2::/64"},
+		// the full 16 bytes prefix is not what "2001:db8:1:2::/64" encodes to
+		{"type": "Framed-IPv6-Prefix", "hex": "004020010db8000100020000000000000000"},
+		{"type": "NAS-Port", "hex": "0001"},
+		{"type": "User-Name", "hex": "fffe"},
+		{"type": "Tunnel-Private-Group-ID", "hex": "01313030"},
+		{"type": "Unknown(250)", "hex": "01"},
+	}
+	if !reflect.DeepEqual(got.Attributes, want) {
+		t.Errorf("got %v want %v", got.Attributes, want)
+	}
+
+	var decoded RADIUS
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !decoded.Equal(radius) {
+		t.Errorf("round trip got %+v want %+v", &decoded, radius)
+	}
+
+	for _, s := range []string{
+		`{"code":"Access-Nothing","authenticator":"00000000000000000000000000000000"}`,
+		`{"code":"Access-Request","authenticator":"00"}`,
+		`{"code":"Access-Request","authenticator":"00000000000000000000000000000000","attributes":[{"type":"No-Such-Attribute","hex":""}]}`,
+		`{"code":"Access-Request","authenticator":"00000000000000000000000000000000","attributes":[{"type":"NAS-Port","value":"1"}]}`,
+		`{"code":"Access-Request","authenticator":"00000000000000000000000000000000","attributes":[{"type":"NAS-IP-Address","value":"2001:db8::1"}]}`,
+		`{"code":"Access-Request","authenticator":"00000000000000000000000000000000","attributes":[{"type":"User-Name"}]}`,
+	} {
+		if err := json.Unmarshal([]byte(s), &decoded); err == nil {
+			t.Errorf("%s: expected error", s)
+		}
+	}
+}
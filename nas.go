@@ -0,0 +1,69 @@
+package radius
+
+import (
+	"strings"
+)
+
+// NASIdentity returns the identity of the NAS that sent the packet, taken
+// from NAS-IP-Address, NAS-IPv6-Address or NAS-Identifier in that order of
+// preference. RFC2865 and RFC3162 require at least one of them in an
+// Access-Request.
+func (radius *RADIUS) NASIdentity() (string, bool) {
+	for _, t := range []RADIUSAttributeType{
+		RADIUSAttributeTypeNASIPAddress,
+		RADIUSAttributeTypeNASIPv6Address,
+	} {
+		if attr, ok := radius.GetAttribute(t); ok {
+			if ip, err := attr.IP(); err == nil {
+				return ip.String(), true
+			}
+		}
+	}
+	if attr, ok := radius.GetAttribute(RADIUSAttributeTypeNASIdentifier); ok && len(attr.Value) > 0 {
+		return string(attr.Value), true
+	}
+	return "", false
+}
+
+// PortLimit returns the Port-Limit attribute, the maximum number of ports
+// the NAS provides to the user.
+func (radius *RADIUS) PortLimit() (uint32, bool) {
+	return radius.uint32Attribute(RADIUSAttributeTypePortLimit)
+}
+
+// NASPortID returns the NAS-Port-Id attribute.
+func (radius *RADIUS) NASPortID() (string, bool) {
+	attr, ok := radius.GetAttribute(RADIUSAttributeTypeNASPortId)
+	if !ok {
+		return "", false
+	}
+	return string(attr.Value), true
+}
+
+// NASPortIDFields parses a NAS-Port-Id in the "key=value;key=value" format
+// used by broadband network gateways. If the value does not follow that
+// format, the returned map holds the raw value under the empty key and ok is
+// false. It returns nil and false without a NAS-Port-Id.
+func (radius *RADIUS) NASPortIDFields() (map[string]string, bool) {
+	raw, ok := radius.NASPortID()
+	if !ok {
+		return nil, false
+	}
+
+	fields := make(map[string]string)
+	for _, field := range strings.Split(raw, ";") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		i := strings.Index(field, "=")
+		if i <= 0 {
+			return map[string]string{"": raw}, false
+		}
+		fields[strings.TrimSpace(field[:i])] = strings.TrimSpace(field[i+1:])
+	}
+	if len(fields) == 0 {
+		return map[string]string{"": raw}, false
+	}
+	return fields, true
+}
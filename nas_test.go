@@ -0,0 +1,101 @@
+package radius
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestRADIUSNASIdentity(t *testing.T) {
+	tests := []struct {
+		desc       string
+		attributes []RADIUSAttribute
+		want       string
+		ok         bool
+	}{
+		{
+			desc: "NAS-IPv6-Address",
+			attributes: []RADIUSAttribute{
+				newAttribute(RADIUSAttributeTypeNASIdentifier, []byte("nas01")),
+				newAttribute(RADIUSAttributeTypeNASIPv6Address, net.ParseIP("2001:db8::1")),
+			},
+			want: "2001:db8::1",
+			ok:   true,
+		},
+		{
+			desc: "NAS-IPv6-Address truncated",
+			attributes: []RADIUSAttribute{
+				newAttribute(RADIUSAttributeTypeNASIPv6Address, []byte{0x20, 0x01, 0x0d, 0xb8}),
+				newAttribute(RADIUSAttributeTypeNASIdentifier, []byte("nas01")),
+			},
+			want: "nas01",
+			ok:   true,
+		},
+		{
+			desc: "None",
+		},
+	}
+
+	for _, tt := range tests {
+		radius := &RADIUS{Attributes: tt.attributes}
+		got, ok := radius.NASIdentity()
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%s: got %q, %v want %q, %v", tt.desc, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	if got, _ := decodeTestRADIUS(t, testRADIUSAccessRequest).NASIdentity(); got != "127.0.1.1" {
+		t.Errorf("AccessRequest: got %q want %q", got, "127.0.1.1")
+	}
+}
+
+func TestRADIUSPortLimit(t *testing.T) {
+	radius := &RADIUS{Attributes: []RADIUSAttribute{
+		newAttribute(RADIUSAttributeTypePortLimit, []byte{0x00, 0x00, 0x00, 0x02}),
+	}}
+	if n, ok := radius.PortLimit(); n != 2 || !ok {
+		t.Errorf("got %d, %v want 2, true", n, ok)
+	}
+	if v, err := radius.Attributes[0].DecodedValue(); err != nil || v != uint32(2) {
+		t.Errorf("DecodedValue() got %v, %v want 2", v, err)
+	}
+	if _, ok := (&RADIUS{}).PortLimit(); ok {
+		t.Error("expected no Port-Limit")
+	}
+}
+
+func TestRADIUSNASPortIDFields(t *testing.T) {
+	tests := []struct {
+		nasPortID string
+		want      map[string]string
+		ok        bool
+	}{
+		{
+			nasPortID: "slot=1;subslot=0;port=3;vlan=100;",
+			want:      map[string]string{"slot": "1", "subslot": "0", "port": "3", "vlan": "100"},
+			ok:        true,
+		},
+		{
+			nasPortID: "GigabitEthernet0/0/1.100:100",
+			want:      map[string]string{"": "GigabitEthernet0/0/1.100:100"},
+		},
+		{
+			nasPortID: "slot=1;eth 0/1",
+			want:      map[string]string{"": "slot=1;eth 0/1"},
+		},
+	}
+
+	for _, tt := range tests {
+		radius := &RADIUS{Attributes: []RADIUSAttribute{
+			newAttribute(RADIUSAttributeTypeNASPortId, []byte(tt.nasPortID)),
+		}}
+		got, ok := radius.NASPortIDFields()
+		if !reflect.DeepEqual(got, tt.want) || ok != tt.ok {
+			t.Errorf("%q: got %v, %v want %v, %v", tt.nasPortID, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	if got, ok := (&RADIUS{}).NASPortIDFields(); got != nil || ok {
+		t.Errorf("got %v, %v want nil, false", got, ok)
+	}
+}
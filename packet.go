@@ -0,0 +1,59 @@
+package radius
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+const radiusDefaultUDPPort layers.UDPPort = 1812
+
+// AsPacket serializes the RADIUS layer as it is, wraps it in minimal IPv4/UDP
+// headers (and an Ethernet header for layers.LinkTypeEthernet) addressed to
+// UDP port 1812, and decodes the result as a gopacket.Packet of the given
+// link type.
+func (radius *RADIUS) AsPacket(linkType layers.LinkType) (gopacket.Packet, error) {
+	payload, err := radius.serialize()
+	if err != nil {
+		return nil, err
+	}
+
+	ip := &layers.IPv4{
+		Version:  4,
+		TTL:      64,
+		Protocol: layers.IPProtocolUDP,
+		SrcIP:    net.IPv4(127, 0, 0, 1),
+		DstIP:    net.IPv4(127, 0, 0, 1),
+	}
+	udp := &layers.UDP{
+		SrcPort: radiusDefaultUDPPort,
+		DstPort: radiusDefaultUDPPort,
+	}
+	if err := udp.SetNetworkLayerForChecksum(ip); err != nil {
+		return nil, err
+	}
+
+	var ls []gopacket.SerializableLayer
+	switch linkType {
+	case layers.LinkTypeEthernet:
+		ls = append(ls, &layers.Ethernet{
+			SrcMAC:       net.HardwareAddr{0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			DstMAC:       net.HardwareAddr{0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+			EthernetType: layers.EthernetTypeIPv4,
+		})
+	case layers.LinkTypeRaw, layers.LinkTypeIPv4:
+	default:
+		return nil, fmt.Errorf("RADIUS unsupported link type %s", linkType)
+	}
+	ls = append(ls, ip, udp, gopacket.Payload(payload))
+
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	if err := gopacket.SerializeLayers(buf, opts, ls...); err != nil {
+		return nil, err
+	}
+
+	return gopacket.NewPacket(buf.Bytes(), linkType, gopacket.Default), nil
+}
@@ -0,0 +1,49 @@
+package radius
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/gopacket"
+	"github.com/google/gopacket/layers"
+)
+
+func TestRADIUSAsPacket(t *testing.T) {
+	radius := decodeTestRADIUS(t, testRADIUSAccessRequest)
+
+	p, err := radius.AsPacket(layers.LinkTypeEthernet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.ErrorLayer() != nil {
+		t.Fatalf("Failed to decode packet: %v", p.ErrorLayer().Error())
+	}
+	checkLayers(p, []gopacket.LayerType{
+		layers.LayerTypeEthernet,
+		layers.LayerTypeIPv4,
+		layers.LayerTypeUDP,
+		LayerTypeRADIUS,
+	}, t)
+
+	got, ok := p.ApplicationLayer().(*RADIUS)
+	if !ok {
+		t.Fatal("No RADIUS layer type found in packet")
+	}
+	if !bytes.Equal(got.Contents, testRADIUSAccessRequest) {
+		t.Errorf("got %x want %x", got.Contents, testRADIUSAccessRequest)
+	}
+
+	p, err = radius.AsPacket(layers.LinkTypeRaw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkLayers(p, []gopacket.LayerType{
+		layers.LayerTypeIPv4,
+		layers.LayerTypeUDP,
+		LayerTypeRADIUS,
+	}, t)
+
+	if _, err := radius.AsPacket(layers.LinkTypeFDDI); err == nil {
+		t.Error("expected error for unsupported link type")
+	}
+}
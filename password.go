@@ -0,0 +1,163 @@
+package radius
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/rand"
+	"fmt"
+)
+
+const radiusUserPasswordMaximumLength = 128
+
+// radiusSaltedMaximumLength is the longest plaintext a salt-encrypted value
+// can carry: with the length byte and the padding, the 240 bytes encrypted
+// value, the 2 bytes salt and the tag of Tunnel-Password fit in 253 bytes.
+const radiusSaltedMaximumLength = 239
+
+// DecryptUserPassword recovers the cleartext of the User-Password attribute
+// of an Access-Request, hidden with the Request Authenticator and the shared
+// secret (RFC2865 5.2). The trailing NUL padding is stripped.
+func (radius *RADIUS) DecryptUserPassword(secret []byte) (string, error) {
+	attr, ok := radius.GetAttribute(RADIUSAttributeTypeUserPassword)
+	if !ok {
+		return "", fmt.Errorf("RADIUS %s missing", RADIUSAttributeTypeUserPassword)
+	}
+	if len(attr.Value) == 0 || len(attr.Value)%md5.Size != 0 || len(attr.Value) > radiusUserPasswordMaximumLength {
+		return "", fmt.Errorf("RADIUS %s length %d is not a multiple of %d up to %d", attr.Type, len(attr.Value), md5.Size, radiusUserPasswordMaximumLength)
+	}
+
+	password := make([]byte, len(attr.Value))
+	last := radius.Authenticator[:]
+	for i := 0; i < len(attr.Value); i += md5.Size {
+		b := userPasswordBlock(secret, last)
+		for j := 0; j < md5.Size; j++ {
+			password[i+j] = attr.Value[i+j] ^ b[j]
+		}
+		last = attr.Value[i : i+md5.Size]
+	}
+	return string(bytes.TrimRight(password, "\x00")), nil
+}
+
+// EncryptUserPassword returns the User-Password attribute value hiding
+// password with the Request Authenticator of the packet and the shared
+// secret (RFC2865 5.2). The password is padded with NUL to a multiple of 16
+// bytes, so the Authenticator must be set before.
+func (radius *RADIUS) EncryptUserPassword(password string, secret []byte) (RADIUSAttributeValue, error) {
+	if len(password) > radiusUserPasswordMaximumLength {
+		return nil, fmt.Errorf("RADIUS %s length %d exceeds %d", RADIUSAttributeTypeUserPassword, len(password), radiusUserPasswordMaximumLength)
+	}
+	n := (len(password) + md5.Size - 1) / md5.Size * md5.Size
+	if n == 0 {
+		n = md5.Size
+	}
+
+	value := make(RADIUSAttributeValue, n)
+	copy(value, password)
+	last := radius.Authenticator[:]
+	for i := 0; i < n; i += md5.Size {
+		b := userPasswordBlock(secret, last)
+		for j := 0; j < md5.Size; j++ {
+			value[i+j] ^= b[j]
+		}
+		last = value[i : i+md5.Size]
+	}
+	return value, nil
+}
+
+// userPasswordBlock returns MD5(secret + last), the key stream of the
+// User-Password block following last.
+func userPasswordBlock(secret, last []byte) []byte {
+	h := md5.New()
+	h.Write(secret)
+	h.Write(last)
+	return h.Sum(nil)
+}
+
+// DecryptSalted recovers the plaintext of a salt-encrypted attribute: a
+// Tunnel-Password (RFC2868 3.5), or a Vendor-Specific attribute carrying an
+// MS-MPPE-Send-Key or MS-MPPE-Recv-Key (RFC2548 2.4.2, 2.4.3). The key stream
+// is seeded with the salt, the shared secret and the Authenticator of
+// radius, which must be the request answered by the packet carrying attr.
+// The most significant bit of the salt must be set, and the leading length
+// byte of the decrypted value is stripped along with the padding.
+func (radius *RADIUS) DecryptSalted(attr RADIUSAttribute, secret []byte) ([]byte, error) {
+	var salt, value []byte
+	switch attr.Type {
+	case RADIUSAttributeTypeTunnelPassword:
+		var ok bool
+		if _, salt, value, ok = attr.SaltedValue(); !ok {
+			return nil, fmt.Errorf("RADIUS %s salt invalid", attr.Type)
+		}
+	case RADIUSAttributeTypeVendorSpecific:
+		vsa, err := attr.VendorSpecific()
+		if err != nil {
+			return nil, err
+		}
+		for _, sub := range vsa.Attributes {
+			if vsa.VendorID == RADIUSVendorIDMicrosoft && (sub.Type == RADIUSMicrosoftAttributeTypeMPPESendKey || sub.Type == RADIUSMicrosoftAttributeTypeMPPERecvKey) {
+				value = sub.Value
+				break
+			}
+		}
+		if len(value) < 2 || value[0]&0x80 == 0 {
+			return nil, fmt.Errorf("RADIUS %s has no salt-encrypted MS-MPPE key", attr.Type)
+		}
+		salt, value = value[:2], value[2:]
+	default:
+		return nil, fmt.Errorf("RADIUS %s is not salt-encrypted", attr.Type)
+	}
+	if len(value) == 0 || len(value)%md5.Size != 0 {
+		return nil, fmt.Errorf("RADIUS %s encrypted length %d is not a multiple of %d", attr.Type, len(value), md5.Size)
+	}
+
+	plaintext := make([]byte, len(value))
+	last := append(radius.Authenticator[:], salt...)
+	for i := 0; i < len(value); i += md5.Size {
+		b := userPasswordBlock(secret, last)
+		for j := 0; j < md5.Size; j++ {
+			plaintext[i+j] = value[i+j] ^ b[j]
+		}
+		last = value[i : i+md5.Size]
+	}
+	if n := int(plaintext[0]); n > len(plaintext)-1 {
+		return nil, fmt.Errorf("RADIUS %s decrypted length %d exceeds %d", attr.Type, n, len(plaintext)-1)
+	}
+	return plaintext[1 : 1+plaintext[0]], nil
+}
+
+// EncryptSalted returns plaintext salt-encrypted with the Authenticator of
+// radius, the request being answered, and the shared secret, the reverse of
+// DecryptSalted: a random salt with its most significant bit set, followed
+// by the encrypted length byte, plaintext and padding. It is the value of a
+// Tunnel-Password after its tag, see NewTaggedAttribute, or of an MS-MPPE key
+// sub-attribute.
+func (radius *RADIUS) EncryptSalted(plaintext, secret []byte) (RADIUSAttributeValue, error) {
+	salt := make([]byte, 2)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("RADIUS salt: %s", err)
+	}
+	salt[0] |= 0x80
+	return radius.encryptSalted(salt, plaintext, secret)
+}
+
+// encryptSalted is EncryptSalted with the given salt.
+func (radius *RADIUS) encryptSalted(salt, plaintext, secret []byte) (RADIUSAttributeValue, error) {
+	if len(plaintext) > radiusSaltedMaximumLength {
+		return nil, fmt.Errorf("RADIUS salt-encrypted value length %d exceeds %d", len(plaintext), radiusSaltedMaximumLength)
+	}
+	n := (len(plaintext) + 1 + md5.Size - 1) / md5.Size * md5.Size
+
+	value := make(RADIUSAttributeValue, 2+n)
+	copy(value, salt)
+	value[2] = byte(len(plaintext))
+	copy(value[3:], plaintext)
+	last := append(radius.Authenticator[:], salt...)
+	for i := 2; i < len(value); i += md5.Size {
+		b := userPasswordBlock(secret, last)
+		for j := 0; j < md5.Size; j++ {
+			value[i+j] ^= b[j]
+		}
+		last = value[i : i+md5.Size]
+	}
+	return value, nil
+}
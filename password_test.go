@@ -0,0 +1,136 @@
+package radius
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestRADIUSUserPassword(t *testing.T) {
+	secret := []byte("secret")
+	req := decodeTestRADIUS(t, testRADIUSAccessRequest)
+
+	password, err := req.DecryptUserPassword(secret)
+	if err != nil || password != "P@ssW0rd" {
+		t.Errorf("DecryptUserPassword got %q, %v want %q", password, err, "P@ssW0rd")
+	}
+
+	attr, _ := req.GetAttribute(RADIUSAttributeTypeUserPassword)
+	value, err := req.EncryptUserPassword("P@ssW0rd", secret)
+	if err != nil || !bytes.Equal(value, attr.Value) {
+		t.Errorf("EncryptUserPassword got %x, %v want %x", value, err, attr.Value)
+	}
+
+	for _, password := range []string{"", "0123456789abcdef", "a much longer password spanning three blocks", strings.Repeat("x", 128)} {
+		value, err := req.EncryptUserPassword(password, secret)
+		if err != nil {
+			t.Errorf("%q: EncryptUserPassword unexpected error: %v", password, err)
+			continue
+		}
+		if len(value) == 0 || len(value)%16 != 0 {
+			t.Errorf("%q: EncryptUserPassword length %d", password, len(value))
+		}
+		radius := &RADIUS{
+			Authenticator: req.Authenticator,
+			Attributes:    []RADIUSAttribute{newAttribute(RADIUSAttributeTypeUserPassword, value)},
+		}
+		if got, err := radius.DecryptUserPassword(secret); err != nil || got != password {
+			t.Errorf("%q: round trip got %q, %v", password, got, err)
+		}
+	}
+
+	if _, err := req.EncryptUserPassword(strings.Repeat("x", 129), secret); err == nil {
+		t.Error("EncryptUserPassword of 129 bytes: expected error")
+	}
+	for _, value := range [][]byte{nil, make([]byte, 15), make([]byte, 17)} {
+		radius := &RADIUS{}
+		if value != nil {
+			radius.Attributes = []RADIUSAttribute{newAttribute(RADIUSAttributeTypeUserPassword, value)}
+		}
+		if _, err := radius.DecryptUserPassword(secret); err == nil {
+			t.Errorf("DecryptUserPassword of %d bytes: expected error", len(value))
+		}
+	}
+}
+
+func TestRADIUSSalted(t *testing.T) {
+	req := decodeTestRADIUS(t, testRADIUSAccessRequest)
+	secret := []byte("secret")
+	mppeKey := make([]byte, 32)
+	for i := range mppeKey {
+		mppeKey[i] = byte(i)
+	}
+
+	// computed independently for the Request Authenticator of
+	// testRADIUSAccessRequest and the salt 0x8123
+	tests := []struct {
+		desc      string
+		plaintext []byte
+		want      string
+	}{
+		{"TunnelPassword", []byte("tunnel-pass"), "8123f8818c85a5339b0ccd08247bf16b5910"},
+		{"MPPEKey", mppeKey, "8123d3f5f8e9c852f227ba615e02fa67541eb24ee5087af71d54bfa97b9d10e61f42a382b8a5641539ee685ebf8789525ed6"},
+	}
+
+	for _, tt := range tests {
+		value, err := req.encryptSalted([]byte{0x81, 0x23}, tt.plaintext, secret)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.desc, err)
+		}
+		if got := hex.EncodeToString(value); got != tt.want {
+			t.Errorf("%s: got %s want %s", tt.desc, got, tt.want)
+		}
+	}
+
+	password, err := req.EncryptSalted([]byte("tunnel-pass"), secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if password[0]&0x80 == 0 {
+		t.Errorf("salt %x most significant bit clear", password[:2])
+	}
+	attr, err := NewTaggedAttribute(RADIUSAttributeTypeTunnelPassword, 1, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, err := req.DecryptSalted(attr, secret); err != nil || string(got) != "tunnel-pass" {
+		t.Errorf("Tunnel-Password got %q, %v", got, err)
+	}
+
+	key, err := req.EncryptSalted(mppeKey, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	vsa, err := SerializeVendorSpecific(&RADIUSVendorSpecific{
+		VendorID:   RADIUSVendorIDMicrosoft,
+		Attributes: []RADIUSVendorAttribute{{Type: RADIUSMicrosoftAttributeTypeMPPERecvKey, Value: key}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := req.DecryptSalted(newAttribute(RADIUSAttributeTypeVendorSpecific, vsa), secret)
+	if err != nil || !bytes.Equal(got, mppeKey) {
+		t.Errorf("MS-MPPE-Recv-Key got %x, %v", got, err)
+	}
+
+	if got, err := req.DecryptSalted(attr, []byte("wrong")); err == nil && string(got) == "tunnel-pass" {
+		t.Error("wrong secret: decrypted")
+	}
+	for _, tt := range []struct {
+		desc string
+		attr RADIUSAttribute
+	}{
+		{"SaltBitClear", newAttribute(RADIUSAttributeTypeTunnelPassword, append([]byte{0x01, 0x01}, password[1:]...))},
+		{"Truncated", newAttribute(RADIUSAttributeTypeTunnelPassword, append([]byte{0x01}, password[:10]...))},
+		{"NotSalted", newAttribute(RADIUSAttributeTypeUserPassword, password)},
+		{"NoMPPEKey", testCiscoAVPairVSA},
+	} {
+		if _, err := req.DecryptSalted(tt.attr, secret); err == nil {
+			t.Errorf("%s: expected error", tt.desc)
+		}
+	}
+	if _, err := req.EncryptSalted(make([]byte, 240), secret); err == nil {
+		t.Error("240 bytes: expected error")
+	}
+}
@@ -0,0 +1,63 @@
+package radius
+
+// Prompt values, RFC2869 5.10.
+const (
+	radiusPromptNoEcho uint32 = 0
+	radiusPromptEcho   uint32 = 1
+)
+
+// ShouldEcho reports whether the Prompt attribute of an Access-Challenge asks
+// for the user's response to be echoed as it is entered. It returns ok=false
+// when the Prompt is absent or invalid, in which case the client chooses.
+func (radius *RADIUS) ShouldEcho() (echo bool, ok bool) {
+	n, ok := radius.uint32Attribute(RADIUSAttributeTypePrompt)
+	if !ok {
+		return false, false
+	}
+	switch n {
+	case radiusPromptNoEcho:
+		return false, true
+	case radiusPromptEcho:
+		return true, true
+	default:
+		return false, false
+	}
+}
+
+// SetPrompt appends a Prompt attribute asking the client to echo the user's
+// response or not.
+func (b *RADIUSBuilder) SetPrompt(echo bool) *RADIUSBuilder {
+	prompt := radiusPromptNoEcho
+	if echo {
+		prompt = radiusPromptEcho
+	}
+	return b.AddRaw(RADIUSAttributeTypePrompt, uint32Value(prompt))
+}
+
+// NewInteractivePrompt returns the Access-Challenge answering req with an
+// interactive prompt, e.g. for a one-time password: the prompt text as
+// Reply-Message, the State to be echoed by the next Access-Request, and the
+// Prompt telling whether to echo the user's input. The Message-Authenticator
+// and the Response Authenticator are computed with the shared secret. It
+// returns nil if state is longer than 253 bytes.
+func NewInteractivePrompt(req *RADIUS, secret []byte, promptText string, state []byte, echo bool) *RADIUS {
+	if len(state) > radiusMaximumAttributeValueSizeInBytes {
+		return nil
+	}
+	challenge := NewRADIUS(RADIUSCodeAccessChallenge, req.Identifier).
+		AddReplyMessage(promptText).
+		AddRaw(RADIUSAttributeTypeState, state).
+		SetPrompt(echo).
+		AddRaw(RADIUSAttributeTypeMessageAuthenticator, make([]byte, 16)).
+		Build()
+
+	mac, err := challenge.messageAuthenticator(req.Authenticator, secret)
+	if err != nil {
+		return nil
+	}
+	challenge.Attributes[len(challenge.Attributes)-1] = newAttribute(RADIUSAttributeTypeMessageAuthenticator, mac)
+	if challenge.Authenticator, err = challenge.ComputeResponseAuthenticator(req.Authenticator, secret); err != nil {
+		return nil
+	}
+	return challenge
+}
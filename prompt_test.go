@@ -0,0 +1,61 @@
+package radius
+
+import (
+	"testing"
+)
+
+func TestRADIUSShouldEcho(t *testing.T) {
+	tests := []struct {
+		desc     string
+		radius   *RADIUS
+		wantEcho bool
+		wantOK   bool
+	}{
+		{"Echo", NewRADIUS(RADIUSCodeAccessChallenge, 1).SetPrompt(true).Build(), true, true},
+		{"NoEcho", NewRADIUS(RADIUSCodeAccessChallenge, 1).SetPrompt(false).Build(), false, true},
+		{"Absent", NewRADIUS(RADIUSCodeAccessChallenge, 1).Build(), false, false},
+		{"Invalid", NewRADIUS(RADIUSCodeAccessChallenge, 1).AddRaw(RADIUSAttributeTypePrompt, uint32Value(2)).Build(), false, false},
+	}
+
+	for _, tt := range tests {
+		echo, ok := tt.radius.ShouldEcho()
+		if echo != tt.wantEcho || ok != tt.wantOK {
+			t.Errorf("%s: got %v, %v want %v, %v", tt.desc, echo, ok, tt.wantEcho, tt.wantOK)
+		}
+	}
+}
+
+func TestNewInteractivePrompt(t *testing.T) {
+	secret := []byte("secret")
+	req := decodeTestRADIUS(t, testRADIUSAccessRequest)
+	state := []byte{0x00, 0x01, 0xfe, 0xff}
+
+	challenge := NewInteractivePrompt(req, secret, "Enter the code sent to your phone:", state, false)
+	data, err := challenge.serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	challenge = decodeTestRADIUS(t, data)
+
+	if challenge.Code != RADIUSCodeAccessChallenge || challenge.Identifier != req.Identifier {
+		t.Errorf("got %s %d want %s %d", challenge.Code, challenge.Identifier, RADIUSCodeAccessChallenge, req.Identifier)
+	}
+	if !challenge.VerifyResponseAuthenticatorStrict(req.Authenticator, secret) {
+		t.Error("Access-Challenge does not validate")
+	}
+	if text := challenge.ReplyMessageText(); text != "Enter the code sent to your phone:" {
+		t.Errorf("ReplyMessageText got %q", text)
+	}
+	if echo, ok := challenge.ShouldEcho(); echo || !ok {
+		t.Errorf("ShouldEcho got %v, %v want false, true", echo, ok)
+	}
+
+	next := challenge.ContinueRequest(req)
+	if attr, ok := next.GetAttribute(RADIUSAttributeTypeState); !ok || string(attr.Value) != string(state) {
+		t.Errorf("State got %x, %v want %x", attr.Value, ok, state)
+	}
+
+	if NewInteractivePrompt(req, secret, "PIN:", make([]byte, 254), true) != nil {
+		t.Error("state of 254 bytes: expected nil")
+	}
+}
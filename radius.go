@@ -3,14 +3,18 @@ package radius
 import (
 	"bytes"
 	"encoding/binary"
+	"encoding/hex"
 	"fmt"
-	"io"
 
 	"github.com/google/gopacket"
 	"github.com/google/gopacket/layers"
 )
 
-const radiusMinimumRecordSizeInBytes int = 20
+const (
+	radiusMinimumRecordSizeInBytes         int = 20
+	radiusMaximumRecordSizeInBytes         int = 4096
+	radiusMaximumAttributeValueSizeInBytes int = 253
+)
 
 var LayerTypeRADIUS = gopacket.RegisterLayerType(1812, gopacket.LayerTypeMetadata{Name: "RADIUS", Decoder: gopacket.DecodeFunc(decodeRADIUS)})
 
@@ -23,6 +27,18 @@ type RADIUS struct {
 	Length        RADIUSLength
 	Authenticator RADIUSAuthenticator
 	Attributes    []RADIUSAttribute
+
+	// Warnings lists the suspicious but decodable structures found by
+	// DecodeFromBytes, such as a single-instance attribute like User-Name
+	// appearing more than once, which usually indicates corruption or an
+	// attack. They do not prevent decoding.
+	Warnings []string
+
+	// Options are the DecodeOptions DecodeFromBytes decodes with, so that a
+	// RADIUS layer decoding packet after packet, as with
+	// gopacket.DecodingLayerParser, can copy values or reject unknown codes.
+	// AttributeLayers does not apply to DecodeFromBytes.
+	Options DecodeOptions
 }
 
 // RADIUSCode represents packet type.
@@ -36,8 +52,14 @@ const (
 	RADIUSCodeAccountingRequest  RADIUSCode = 4   // RFC2865 3.  Packet Format
 	RADIUSCodeAccountingResponse RADIUSCode = 5   // RFC2865 3.  Packet Format
 	RADIUSCodeAccessChallenge    RADIUSCode = 11  // RFC2865 3.  Packet Format
-	RADIUSCodeStatusServer       RADIUSCode = 12  // RFC2865 3.  Packet Format (experimental)
+	RADIUSCodeStatusServer       RADIUSCode = 12  // RFC5997 2.  Status-Server
 	RADIUSCodeStatusClient       RADIUSCode = 13  // RFC2865 3.  Packet Format (experimental)
+	RADIUSCodeDisconnectRequest  RADIUSCode = 40  // RFC5176 3.  Packet Format
+	RADIUSCodeDisconnectACK      RADIUSCode = 41  // RFC5176 3.  Packet Format
+	RADIUSCodeDisconnectNAK      RADIUSCode = 42  // RFC5176 3.  Packet Format
+	RADIUSCodeCoARequest         RADIUSCode = 43  // RFC5176 3.  Packet Format
+	RADIUSCodeCoAACK             RADIUSCode = 44  // RFC5176 3.  Packet Format
+	RADIUSCodeCoANAK             RADIUSCode = 45  // RFC5176 3.  Packet Format
 	RADIUSCodeReserved           RADIUSCode = 255 // RFC2865 3.  Packet Format
 )
 
@@ -60,6 +82,18 @@ func (t RADIUSCode) String() (s string) {
 		s = "Status-Server"
 	case RADIUSCodeStatusClient:
 		s = "Status-Client"
+	case RADIUSCodeDisconnectRequest:
+		s = "Disconnect-Request"
+	case RADIUSCodeDisconnectACK:
+		s = "Disconnect-ACK"
+	case RADIUSCodeDisconnectNAK:
+		s = "Disconnect-NAK"
+	case RADIUSCodeCoARequest:
+		s = "CoA-Request"
+	case RADIUSCodeCoAACK:
+		s = "CoA-ACK"
+	case RADIUSCodeCoANAK:
+		s = "CoA-NAK"
 	case RADIUSCodeReserved:
 		s = "Reserved"
 	default:
@@ -68,6 +102,31 @@ func (t RADIUSCode) String() (s string) {
 	return
 }
 
+// IsKnown reports whether a RADIUSCode is one of the packet types defined by
+// the RFCs, excluding the Reserved code 255. Other codes may be newer or
+// experimental packet types, or a payload that is not RADIUS at all.
+func (t RADIUSCode) IsKnown() bool {
+	switch t {
+	case RADIUSCodeAccessRequest,
+		RADIUSCodeAccessAccept,
+		RADIUSCodeAccessReject,
+		RADIUSCodeAccountingRequest,
+		RADIUSCodeAccountingResponse,
+		RADIUSCodeAccessChallenge,
+		RADIUSCodeStatusServer,
+		RADIUSCodeStatusClient,
+		RADIUSCodeDisconnectRequest,
+		RADIUSCodeDisconnectACK,
+		RADIUSCodeDisconnectNAK,
+		RADIUSCodeCoARequest,
+		RADIUSCodeCoAACK,
+		RADIUSCodeCoANAK:
+		return true
+	default:
+		return false
+	}
+}
+
 // RADIUSIdentifier represents packet identifier.
 type RADIUSIdentifier uint8
 
@@ -77,6 +136,23 @@ type RADIUSLength uint16
 // RADIUSAuthenticator represents authenticator.
 type RADIUSAuthenticator [16]byte
 
+// String returns a hex string version of a RADIUSAuthenticator.
+func (a RADIUSAuthenticator) String() string {
+	return hex.EncodeToString(a[:])
+}
+
+// ParseAuthenticator parses a 32 characters hex string into a RADIUSAuthenticator.
+func ParseAuthenticator(s string) (RADIUSAuthenticator, error) {
+	var a RADIUSAuthenticator
+	if len(s) != hex.EncodedLen(len(a)) {
+		return a, fmt.Errorf("RADIUS authenticator hex length %d invalid", len(s))
+	}
+	if _, err := hex.Decode(a[:], []byte(s)); err != nil {
+		return a, fmt.Errorf("RADIUS authenticator hex invalid: %s", err)
+	}
+	return a, nil
+}
+
 // RADIUSAttribute represents attributes.
 type RADIUSAttribute struct {
 	Type   RADIUSAttributeType
@@ -141,6 +217,10 @@ const (
 	RADIUSAttributeTypeAcctInputGigawords     RADIUSAttributeType = 52 // RFC2869  5.1.  Acct-Input-Gigawords
 	RADIUSAttributeTypeAcctOutputGigawords    RADIUSAttributeType = 53 // RFC2869  5.2.  Acct-Output-Gigawords
 	RADIUSAttributeTypeEventTimestamp         RADIUSAttributeType = 55 // RFC2869  5.3.  Event-Timestamp
+	RADIUSAttributeTypeEgressVLANID           RADIUSAttributeType = 56 // RFC4675  2.1.  Egress-VLANID
+	RADIUSAttributeTypeIngressFilters         RADIUSAttributeType = 57 // RFC4675  2.2.  Ingress-Filters
+	RADIUSAttributeTypeEgressVLANName         RADIUSAttributeType = 58 // RFC4675  2.3.  Egress-VLAN-Name
+	RADIUSAttributeTypeUserPriorityTable      RADIUSAttributeType = 59 // RFC4675  2.4.  User-Priority-Table
 	RADIUSAttributeTypeCHAPChallenge          RADIUSAttributeType = 60 // RFC2865 5.40.  CHAP-Challenge
 	RADIUSAttributeTypeNASPortType            RADIUSAttributeType = 61 // RFC2865 5.41.  NAS-Port-Type
 	RADIUSAttributeTypePortLimit              RADIUSAttributeType = 62 // RFC2865 5.42.  Port-Limit
@@ -170,8 +250,49 @@ const (
 	RADIUSAttributeTypeAcctTunnelPacketsLost  RADIUSAttributeType = 86 // RFC2867  4.2.  Acct-Tunnel-Packets-Lost
 	RADIUSAttributeTypeNASPortId              RADIUSAttributeType = 87 // RFC2869 5.17.  NAS-Port-Id
 	RADIUSAttributeTypeFramedPool             RADIUSAttributeType = 88 // RFC2869 5.18.  Framed-Pool
+	RADIUSAttributeTypeChargeableUserIdentity RADIUSAttributeType = 89 // RFC4372  2.1.  Chargeable-User-Identity
 	RADIUSAttributeTypeTunnelClientAuthID     RADIUSAttributeType = 90 // RFC2868  3.9.  Tunnel-Client-Auth-ID
 	RADIUSAttributeTypeTunnelServerAuthID     RADIUSAttributeType = 91 // RFC2868 3.10.  Tunnel-Server-Auth-ID
+	RADIUSAttributeTypeNASFilterRule          RADIUSAttributeType = 92 // RFC4849  2.    NAS-Filter-Rule
+	RADIUSAttributeTypeNASIPv6Address         RADIUSAttributeType = 95 // RFC3162  2.1.  NAS-IPv6-Address
+	RADIUSAttributeTypeFramedInterfaceId      RADIUSAttributeType = 96 // RFC3162  2.2.  Framed-Interface-Id
+	RADIUSAttributeTypeFramedIPv6Prefix       RADIUSAttributeType = 97 // RFC3162  2.3.  Framed-IPv6-Prefix
+	RADIUSAttributeTypeLoginIPv6Host          RADIUSAttributeType = 98 // RFC3162  2.4.  Login-IPv6-Host
+	RADIUSAttributeTypeFramedIPv6Route        RADIUSAttributeType = 99 // RFC3162  2.5.  Framed-IPv6-Route
+
+	RADIUSAttributeTypeFramedIPv6Pool          RADIUSAttributeType = 100 // RFC3162  2.6.  Framed-IPv6-Pool
+	RADIUSAttributeTypeErrorCause              RADIUSAttributeType = 101 // RFC5176  3.6.  Error-Cause
+	RADIUSAttributeTypeDigestResponse          RADIUSAttributeType = 103 // RFC5090  3.1.  Digest-Response
+	RADIUSAttributeTypeDigestRealm             RADIUSAttributeType = 104 // RFC5090  3.2.  Digest-Realm
+	RADIUSAttributeTypeDigestNonce             RADIUSAttributeType = 105 // RFC5090  3.3.  Digest-Nonce
+	RADIUSAttributeTypeDigestResponseAuth      RADIUSAttributeType = 106 // RFC5090  3.4.  Digest-Response-Auth
+	RADIUSAttributeTypeDigestNextnonce         RADIUSAttributeType = 107 // RFC5090  3.5.  Digest-Nextnonce
+	RADIUSAttributeTypeDigestMethod            RADIUSAttributeType = 108 // RFC5090  3.6.  Digest-Method
+	RADIUSAttributeTypeDigestURI               RADIUSAttributeType = 109 // RFC5090  3.7.  Digest-URI
+	RADIUSAttributeTypeDigestQop               RADIUSAttributeType = 110 // RFC5090  3.8.  Digest-Qop
+	RADIUSAttributeTypeDigestAlgorithm         RADIUSAttributeType = 111 // RFC5090  3.9.  Digest-Algorithm
+	RADIUSAttributeTypeDigestEntityBodyHash    RADIUSAttributeType = 112 // RFC5090  3.10. Digest-Entity-Body-Hash
+	RADIUSAttributeTypeDigestCNonce            RADIUSAttributeType = 113 // RFC5090  3.11. Digest-CNonce
+	RADIUSAttributeTypeDigestNonceCount        RADIUSAttributeType = 114 // RFC5090  3.12. Digest-Nonce-Count
+	RADIUSAttributeTypeDigestUsername          RADIUSAttributeType = 115 // RFC5090  3.13. Digest-Username
+	RADIUSAttributeTypeDigestOpaque            RADIUSAttributeType = 116 // RFC5090  3.14. Digest-Opaque
+	RADIUSAttributeTypeDigestAuthParam         RADIUSAttributeType = 117 // RFC5090  3.15. Digest-Auth-Param
+	RADIUSAttributeTypeDigestAKAAuts           RADIUSAttributeType = 118 // RFC5090  3.16. Digest-AKA-Auts
+	RADIUSAttributeTypeDigestDomain            RADIUSAttributeType = 119 // RFC5090  3.17. Digest-Domain
+	RADIUSAttributeTypeDigestStale             RADIUSAttributeType = 120 // RFC5090  3.18. Digest-Stale
+	RADIUSAttributeTypeDigestHA1               RADIUSAttributeType = 121 // RFC5090  3.19. Digest-HA1
+	RADIUSAttributeTypeFramedIPv6Address       RADIUSAttributeType = 168 // RFC6911  3.1.  Framed-IPv6-Address
+	RADIUSAttributeTypeDNSServerIPv6Address    RADIUSAttributeType = 169 // RFC6911  3.2.  DNS-Server-IPv6-Address
+	RADIUSAttributeTypeRouteIPv6Information    RADIUSAttributeType = 170 // RFC6911  3.3.  Route-IPv6-Information
+	RADIUSAttributeTypeDelegatedIPv6PrefixPool RADIUSAttributeType = 171 // RFC6911  3.4.  Delegated-IPv6-Prefix-Pool
+	RADIUSAttributeTypeStatefulIPv6AddressPool RADIUSAttributeType = 172 // RFC6911  3.5.  Stateful-IPv6-Address-Pool
+	RADIUSAttributeTypeDigestAttributes        RADIUSAttributeType = 207 // draft-sterman-aaa-sip-00 Digest-Attributes
+	RADIUSAttributeTypeExtendedType1           RADIUSAttributeType = 241 // RFC6929  2.1.  Extended-Type-1
+	RADIUSAttributeTypeExtendedType2           RADIUSAttributeType = 242 // RFC6929  2.1.  Extended-Type-2
+	RADIUSAttributeTypeExtendedType3           RADIUSAttributeType = 243 // RFC6929  2.1.  Extended-Type-3
+	RADIUSAttributeTypeExtendedType4           RADIUSAttributeType = 244 // RFC6929  2.1.  Extended-Type-4
+	RADIUSAttributeTypeLongExtendedType1       RADIUSAttributeType = 245 // RFC6929  2.2.  Long-Extended-Type-1
+	RADIUSAttributeTypeLongExtendedType2       RADIUSAttributeType = 246 // RFC6929  2.2.  Long-Extended-Type-2
 )
 
 // RADIUSAttributeType represents attribute length.
@@ -181,7 +302,25 @@ type RADIUSAttributeLength uint8
 type RADIUSAttributeValue []byte
 
 // String returns a string version of a RADIUSAttributeType.
-func (t RADIUSAttributeType) String() (s string) {
+func (t RADIUSAttributeType) String() string {
+	if s := t.standardName(); s != "" {
+		return s
+	}
+	if r, ok := registeredAttribute(t); ok {
+		return r.name
+	}
+	return fmt.Sprintf("Unknown(%d)", t)
+}
+
+// isStandard reports whether t is an attribute type this package defines,
+// which RegisterAttribute cannot replace.
+func (t RADIUSAttributeType) isStandard() bool {
+	return t.standardName() != ""
+}
+
+// standardName returns the name of an attribute type this package defines,
+// or "" for any other type.
+func (t RADIUSAttributeType) standardName() (s string) {
 	switch t {
 	case RADIUSAttributeTypeUserName:
 		s = "User-Name"
@@ -287,6 +426,14 @@ func (t RADIUSAttributeType) String() (s string) {
 		s = "Acct-Output-Gigawords"
 	case RADIUSAttributeTypeEventTimestamp:
 		s = "Event-Timestamp"
+	case RADIUSAttributeTypeEgressVLANID:
+		s = "Egress-VLANID"
+	case RADIUSAttributeTypeIngressFilters:
+		s = "Ingress-Filters"
+	case RADIUSAttributeTypeEgressVLANName:
+		s = "Egress-VLAN-Name"
+	case RADIUSAttributeTypeUserPriorityTable:
+		s = "User-Priority-Table"
 	case RADIUSAttributeTypeCHAPChallenge:
 		s = "CHAP-Challenge"
 	case RADIUSAttributeTypeNASPortType:
@@ -345,12 +492,90 @@ func (t RADIUSAttributeType) String() (s string) {
 		s = "NAS-Port-Id"
 	case RADIUSAttributeTypeFramedPool:
 		s = "Framed-Pool"
+	case RADIUSAttributeTypeChargeableUserIdentity:
+		s = "Chargeable-User-Identity"
 	case RADIUSAttributeTypeTunnelClientAuthID:
 		s = "Tunnel-Client-Auth-ID"
 	case RADIUSAttributeTypeTunnelServerAuthID:
 		s = "Tunnel-Server-Auth-ID"
-	default:
-		s = fmt.Sprintf("Unknown(%d)", t)
+	case RADIUSAttributeTypeNASFilterRule:
+		s = "NAS-Filter-Rule"
+	case RADIUSAttributeTypeNASIPv6Address:
+		s = "NAS-IPv6-Address"
+	case RADIUSAttributeTypeFramedInterfaceId:
+		s = "Framed-Interface-Id"
+	case RADIUSAttributeTypeFramedIPv6Prefix:
+		s = "Framed-IPv6-Prefix"
+	case RADIUSAttributeTypeLoginIPv6Host:
+		s = "Login-IPv6-Host"
+	case RADIUSAttributeTypeFramedIPv6Route:
+		s = "Framed-IPv6-Route"
+	case RADIUSAttributeTypeFramedIPv6Pool:
+		s = "Framed-IPv6-Pool"
+	case RADIUSAttributeTypeErrorCause:
+		s = "Error-Cause"
+	case RADIUSAttributeTypeDigestResponse:
+		s = "Digest-Response"
+	case RADIUSAttributeTypeDigestRealm:
+		s = "Digest-Realm"
+	case RADIUSAttributeTypeDigestNonce:
+		s = "Digest-Nonce"
+	case RADIUSAttributeTypeDigestResponseAuth:
+		s = "Digest-Response-Auth"
+	case RADIUSAttributeTypeDigestNextnonce:
+		s = "Digest-Nextnonce"
+	case RADIUSAttributeTypeDigestMethod:
+		s = "Digest-Method"
+	case RADIUSAttributeTypeDigestURI:
+		s = "Digest-URI"
+	case RADIUSAttributeTypeDigestQop:
+		s = "Digest-Qop"
+	case RADIUSAttributeTypeDigestAlgorithm:
+		s = "Digest-Algorithm"
+	case RADIUSAttributeTypeDigestEntityBodyHash:
+		s = "Digest-Entity-Body-Hash"
+	case RADIUSAttributeTypeDigestCNonce:
+		s = "Digest-CNonce"
+	case RADIUSAttributeTypeDigestNonceCount:
+		s = "Digest-Nonce-Count"
+	case RADIUSAttributeTypeDigestUsername:
+		s = "Digest-Username"
+	case RADIUSAttributeTypeDigestOpaque:
+		s = "Digest-Opaque"
+	case RADIUSAttributeTypeDigestAuthParam:
+		s = "Digest-Auth-Param"
+	case RADIUSAttributeTypeDigestAKAAuts:
+		s = "Digest-AKA-Auts"
+	case RADIUSAttributeTypeDigestDomain:
+		s = "Digest-Domain"
+	case RADIUSAttributeTypeDigestStale:
+		s = "Digest-Stale"
+	case RADIUSAttributeTypeDigestHA1:
+		s = "Digest-HA1"
+	case RADIUSAttributeTypeFramedIPv6Address:
+		s = "Framed-IPv6-Address"
+	case RADIUSAttributeTypeDNSServerIPv6Address:
+		s = "DNS-Server-IPv6-Address"
+	case RADIUSAttributeTypeRouteIPv6Information:
+		s = "Route-IPv6-Information"
+	case RADIUSAttributeTypeDelegatedIPv6PrefixPool:
+		s = "Delegated-IPv6-Prefix-Pool"
+	case RADIUSAttributeTypeStatefulIPv6AddressPool:
+		s = "Stateful-IPv6-Address-Pool"
+	case RADIUSAttributeTypeDigestAttributes:
+		s = "Digest-Attributes"
+	case RADIUSAttributeTypeExtendedType1:
+		s = "Extended-Type-1"
+	case RADIUSAttributeTypeExtendedType2:
+		s = "Extended-Type-2"
+	case RADIUSAttributeTypeExtendedType3:
+		s = "Extended-Type-3"
+	case RADIUSAttributeTypeExtendedType4:
+		s = "Extended-Type-4"
+	case RADIUSAttributeTypeLongExtendedType1:
+		s = "Long-Extended-Type-1"
+	case RADIUSAttributeTypeLongExtendedType2:
+		s = "Long-Extended-Type-2"
 	}
 	return
 }
@@ -359,7 +584,7 @@ func (t RADIUSAttributeType) String() (s string) {
 func (radius *RADIUS) Len() (int, error) {
 	n := radiusMinimumRecordSizeInBytes
 	for _, v := range radius.Attributes {
-		alen, err := attributeValueLength(v.Value)
+		alen, err := attributeValueLength(v.Type, v.Value)
 		if err != nil {
 			return 0, err
 		}
@@ -373,67 +598,181 @@ func (radius *RADIUS) LayerType() gopacket.LayerType {
 	return LayerTypeRADIUS
 }
 
-// DecodeFromBytes decodes the given bytes into this layer.
+// Equal reports whether two packets have the same code, identifier, length,
+// authenticator and attributes, in the same order. A nil and an empty
+// Attributes are equal, and the decoding state of the layer, its Contents,
+// Payload and Warnings, is not compared.
+func (radius *RADIUS) Equal(other *RADIUS) bool {
+	if radius == nil || other == nil {
+		return radius == other
+	}
+	if radius.Code != other.Code ||
+		radius.Identifier != other.Identifier ||
+		radius.Length != other.Length ||
+		radius.Authenticator != other.Authenticator ||
+		len(radius.Attributes) != len(other.Attributes) {
+		return false
+	}
+	for i, v := range radius.Attributes {
+		w := other.Attributes[i]
+		if v.Type != w.Type || v.Length != w.Length || !bytes.Equal(v.Value, w.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// Clone returns a deep copy of the packet, whose attribute values, Contents
+// and Payload do not refer to the buffer it was decoded from, so that it can
+// be modified and serialized again without corrupting the original packet.
+func (radius *RADIUS) Clone() *RADIUS {
+	if radius == nil {
+		return nil
+	}
+	c := *radius
+	c.BaseLayer = layers.BaseLayer{
+		Contents: append([]byte(nil), radius.Contents...),
+		Payload:  append([]byte(nil), radius.BaseLayer.Payload...),
+	}
+	if radius.Attributes != nil {
+		c.Attributes = make([]RADIUSAttribute, len(radius.Attributes))
+		for i, v := range radius.Attributes {
+			c.Attributes[i] = RADIUSAttribute{
+				Type:   v.Type,
+				Length: v.Length,
+				Value:  append(RADIUSAttributeValue(nil), v.Value...),
+			}
+		}
+	}
+	c.Warnings = append([]string(nil), radius.Warnings...)
+	return &c
+}
+
+// String returns a compact summary of the packet, its code, identifier,
+// length and attribute count, e.g.
+// "Access-Request Identifier=42 Length=88 Attributes=6". It implements
+// fmt.Stringer, which gopacket uses for the layer on printing a packet.
+func (radius *RADIUS) String() string {
+	return fmt.Sprintf("%s Identifier=%d Length=%d Attributes=%d", radius.Code, radius.Identifier, radius.Length, len(radius.Attributes))
+}
+
+// DecodeFromBytes decodes the given bytes into this layer. The attribute
+// values refer to data, and the Attributes slice of a previous decode is
+// reused, so that a RADIUS layer can decode packet after packet without
+// allocating, as gopacket.DecodingLayerParser does. Bytes beyond the Length
+// field, padding added by some implementations (RFC2865 3.), are not part of
+// the packet: they are the Payload unless the packet carries EAP-Message
+// attributes, and are ignored otherwise. It decodes with the Options of the
+// layer: DecodeOptions.CopyValues decodes a packet that outlives data.
 func (radius *RADIUS) DecodeFromBytes(data []byte, df gopacket.DecodeFeedback) error {
+	return radius.DecodeFromBytesWithOptions(data, df, radius.Options)
+}
+
+// DecodeFromBytesWithOptions is DecodeFromBytes with RADIUS specific options.
+func (radius *RADIUS) DecodeFromBytesWithOptions(data []byte, df gopacket.DecodeFeedback, opts DecodeOptions) error {
 	if len(data) < radiusMinimumRecordSizeInBytes {
 		df.SetTruncated()
 		return fmt.Errorf("RADIUS length %d too short", len(data))
 	}
 
-	radius.BaseLayer = layers.BaseLayer{Contents: data}
+	radius.Warnings = nil
 
 	radius.Code = RADIUSCode(data[0])
+	if opts.RejectUnknownCodes && !radius.Code.IsKnown() {
+		return fmt.Errorf("RADIUS code %s unknown", radius.Code)
+	}
 	radius.Identifier = RADIUSIdentifier(data[1])
 	radius.Length = RADIUSLength(binary.BigEndian.Uint16(data[2:4]))
 	copy(radius.Authenticator[:], data[4:20])
 
-	if len(data) == radiusMinimumRecordSizeInBytes {
-		return nil
+	if int(radius.Length) < radiusMinimumRecordSizeInBytes {
+		return fmt.Errorf("RADIUS length field %d too short", radius.Length)
 	}
+	if int(radius.Length) > len(data) {
+		df.SetTruncated()
+		return fmt.Errorf("RADIUS length field %d exceeds %d bytes", radius.Length, len(data))
+	}
+	radius.BaseLayer = layers.BaseLayer{Contents: data[:radius.Length], Payload: data[radius.Length:]}
+	if len(radius.BaseLayer.Payload) == 0 {
+		radius.BaseLayer.Payload = nil
+	}
+	data = data[:radius.Length]
 
-	reader := bytes.NewReader(data[20:])
-	header := make([]byte, 2)
-	value := make([]byte, 255)
-	for {
-		header = header[:2]
-
-		if _, err := reader.Read(header); err == io.EOF {
-			break
-		} else if err != nil {
-			return fmt.Errorf("RADIUS unknown error: %s", err)
+	radius.Attributes = radius.Attributes[:0]
+	attrs := data[radiusMinimumRecordSizeInBytes:]
+	for len(attrs) > 0 {
+		attr, err := DecodeAttribute(attrs)
+		if err != nil {
+			if len(attrs) < 2 || int(attrs[1]) > len(attrs) {
+				df.SetTruncated()
+			}
+			return err
 		}
-
-		value = value[:header[1]-2]
-
-		if _, err := reader.Read(value); err == io.EOF {
-			break
-		} else if err != nil {
-			return fmt.Errorf("RADIUS unknown error: %s", err)
+		if attr.Type.IsExtended() && len(attr.Value) < attr.Type.extendedHeaderLength() {
+			return fmt.Errorf("RADIUS %s length %d shorter than the extended header", attr.Type, attr.Length)
 		}
-
-		attr := RADIUSAttribute{}
-		attr.Type = RADIUSAttributeType(header[0])
-		attr.Length = RADIUSAttributeLength(header[1])
-		attr.Value = make([]byte, header[1]-2)
-		copy(attr.Value[:], value[:])
+		attrs = attrs[attr.Length:]
 
 		radius.Attributes = append(radius.Attributes, attr)
 	}
 
-	for _, v := range radius.Attributes {
-		if v.Type == RADIUSAttributeTypeEAPMessage {
-			radius.BaseLayer.Payload = append(radius.BaseLayer.Payload, v.Value...)
+	if radius.HasAttribute(RADIUSAttributeTypeEAPMessage) {
+		radius.BaseLayer.Payload = nil
+		for _, v := range radius.Attributes {
+			if v.Type == RADIUSAttributeTypeEAPMessage {
+				radius.BaseLayer.Payload = append(radius.BaseLayer.Payload, v.Value...)
+			}
 		}
 	}
+	radius.Warnings = radius.duplicateWarnings()
 
+	if opts.CopyValues {
+		radius.copyValues()
+	}
 	return nil
 }
 
+// copyValues replaces the attribute values, Contents and Payload of a decoded
+// packet with copies of their own, so that they no longer refer to the
+// decoded bytes.
+func (radius *RADIUS) copyValues() {
+	radius.BaseLayer.Contents = append([]byte(nil), radius.BaseLayer.Contents...)
+	if radius.BaseLayer.Payload != nil {
+		radius.BaseLayer.Payload = append([]byte(nil), radius.BaseLayer.Payload...)
+	}
+	for i, v := range radius.Attributes {
+		radius.Attributes[i].Value = append(RADIUSAttributeValue(nil), v.Value...)
+	}
+}
+
 // SerializeTo writes the serialized form of this layer into the
 // SerializationBuffer, implementing gopacket.SerializableLayer.
 // See the docs for gopacket.SerializableLayer for more info.
 func (radius *RADIUS) SerializeTo(b gopacket.SerializeBuffer, opts gopacket.SerializeOptions) error {
-	plen, err := radius.Len()
+	return radius.SerializeToWithOptions(b, SerializeOptions{SerializeOptions: opts})
+}
+
+// SerializeOptions extends gopacket.SerializeOptions with RADIUS specific options.
+type SerializeOptions struct {
+	gopacket.SerializeOptions
+
+	// ValidateAttributeTypes checks each attribute value against the value
+	// type of its attribute type before serializing, e.g. an integer must be
+	// 4 bytes and an ipaddr 4 bytes.
+	ValidateAttributeTypes bool
+}
+
+// SerializeToWithOptions is SerializeTo with RADIUS specific options.
+func (radius *RADIUS) SerializeToWithOptions(b gopacket.SerializeBuffer, opts SerializeOptions) error {
+	if opts.ValidateAttributeTypes {
+		for _, v := range radius.Attributes {
+			if err := v.validateValueType(); err != nil {
+				return err
+			}
+		}
+	}
+
+	plen, err := radius.serializedLength()
 	if err != nil {
 		return err
 	}
@@ -446,19 +785,55 @@ func (radius *RADIUS) SerializeTo(b gopacket.SerializeBuffer, opts gopacket.Seri
 	if err != nil {
 		return err
 	}
+	return radius.encode(data, opts)
+}
+
+// SerializeInto writes the serialized form of this layer into buf, as
+// SerializeTo with default options does, and returns the number of bytes
+// written. It does not allocate, so senders can reuse a single buffer.
+func (radius *RADIUS) SerializeInto(buf []byte) (int, error) {
+	plen, err := radius.serializedLength()
+	if err != nil {
+		return 0, err
+	}
+	if len(buf) < plen {
+		return 0, fmt.Errorf("RADIUS buffer length %d too short for %d bytes", len(buf), plen)
+	}
+	if err := radius.encode(buf[:plen], SerializeOptions{}); err != nil {
+		return 0, err
+	}
+	return plen, nil
+}
+
+// serializedLength returns Len, checking it against the 4096 bytes maximum
+// RADIUS packet length (RFC2865 3.).
+func (radius *RADIUS) serializedLength() (int, error) {
+	plen, err := radius.Len()
+	if err != nil {
+		return 0, err
+	}
+	if plen > radiusMaximumRecordSizeInBytes {
+		return 0, fmt.Errorf("RADIUS packet length too long (%d > %d)", plen, radiusMaximumRecordSizeInBytes)
+	}
+	return plen, nil
+}
 
+// encode writes the packet into data, which is exactly Len bytes long.
+func (radius *RADIUS) encode(data []byte, opts SerializeOptions) error {
 	data[0] = byte(radius.Code)
 	data[1] = byte(radius.Identifier)
 	binary.BigEndian.PutUint16(data[2:], uint16(radius.Length))
 	copy(data[4:20], radius.Authenticator[:])
 
 	pos := radiusMinimumRecordSizeInBytes
-	for _, v := range radius.Attributes {
+	for i, v := range radius.Attributes {
 		if opts.FixLengths {
-			v.Length, err = attributeValueLength(v.Value)
+			n, err := attributeValueLength(v.Type, v.Value)
 			if err != nil {
 				return err
 			}
+			v.Length = n + 2 // Added Type and Length
+			radius.Attributes[i].Length = v.Length
 		}
 
 		data[pos] = byte(v.Type)
@@ -476,40 +851,36 @@ func (radius *RADIUS) CanDecode() gopacket.LayerClass {
 	return LayerTypeRADIUS
 }
 
-// NextLayerType returns the layer type contained by this DecodingLayer.
+// NextLayerType returns the layer type contained by this DecodingLayer: EAP
+// for EAP-Message attributes, and gopacket.LayerTypePayload for padding.
 func (radius *RADIUS) NextLayerType() gopacket.LayerType {
-	if len(radius.BaseLayer.Payload) > 0 {
+	switch {
+	case len(radius.BaseLayer.Payload) > 0 && radius.HasAttribute(RADIUSAttributeTypeEAPMessage):
 		return layers.LayerTypeEAP
-	} else {
+	case len(radius.BaseLayer.Payload) > 0:
+		return gopacket.LayerTypePayload
+	default:
 		return gopacket.LayerTypeZero
 	}
 }
 
-// Payload returns the EAP Type-Data for EAP-Message attributes.
+// Payload returns the EAP Type-Data for EAP-Message attributes, or the
+// padding beyond the Length field.
 func (radius *RADIUS) Payload() []byte {
 	return radius.BaseLayer.Payload
 }
 
 func decodeRADIUS(data []byte, p gopacket.PacketBuilder) error {
-	radius := &RADIUS{}
-	err := radius.DecodeFromBytes(data, p)
-	if err != nil {
-		return err
-	}
-	p.AddLayer(radius)
-	p.SetApplicationLayer(radius)
-	next := radius.NextLayerType()
-	if next == gopacket.LayerTypeZero {
-		return nil
-	}
-	return p.NextDecoder(next)
+	return defaultDecodeOptions.Decode(data, p)
 }
 
-func attributeValueLength(v []byte) (RADIUSAttributeLength, error) {
+// attributeValueLength returns the length of an attribute value, which must
+// fit the Length field along with the Type and Length. Longer values must be
+// split across attributes, as AddLongString and SerializeLongExtended do.
+func attributeValueLength(t RADIUSAttributeType, v []byte) (RADIUSAttributeLength, error) {
 	n := len(v)
-	if n > 255 {
-		return 0, fmt.Errorf("RADIUS attribute value length %d too long", n)
-	} else {
-		return RADIUSAttributeLength(n), nil
+	if n > radiusMaximumAttributeValueSizeInBytes {
+		return 0, fmt.Errorf("RADIUS attribute type %s value too long (%d > %d)", t, n, radiusMaximumAttributeValueSizeInBytes)
 	}
+	return RADIUSAttributeLength(n), nil
 }
@@ -1,7 +1,10 @@
 package radius
 
 import (
+	"bytes"
+	"fmt"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/google/gopacket"
@@ -60,7 +63,7 @@ func checkRADIUS(desc string, t *testing.T, packetBytes []byte, pExpectedRADIUS
 	}
 
 	// Compare the generated RADIUS object with the expected RADIUS object.
-	if !reflect.DeepEqual(pResultRADIUS, pExpectedRADIUS) {
+	if !pResultRADIUS.Equal(pExpectedRADIUS) {
 		t.Errorf("RADIUS packet processing failed for packet "+desc+
 			":\ngot  :\n%#v\n\nwant :\n%#v\n\n", pResultRADIUS, pExpectedRADIUS)
 	}
@@ -173,3 +176,553 @@ func TestRADIUSAccessAccept(t *testing.T) {
 
 	checkRADIUS("AccessAccept", t, testPacketRADIUS, pExpectedRADIUS)
 }
+
+// testRADIUSAccessRequest is the RADIUS payload of the Access-Request in
+// TestRADIUSAccessRequest. The shared secret is "secret".
+var testRADIUSAccessRequest = []byte{
+	0x01, 0x8d, 0x00, 0x4b, 0x3b, 0xbd, 0x22, 0x52, 0xb4, 0xc8, 0xd8, 0x44, 0x1b, 0x46, 0x79, 0xbf,
+	0x4a, 0x2b, 0x86, 0x01, 0x01, 0x07, 0x41, 0x64, 0x6d, 0x69, 0x6e, 0x02, 0x12, 0x4d, 0x2f, 0x62,
+	0x0b, 0x33, 0x9d, 0x6d, 0x1f, 0xe0, 0xe4, 0x6d, 0x1f, 0x9b, 0xda, 0xff, 0xf0, 0x04, 0x06, 0x7f,
+	0x00, 0x01, 0x01, 0x05, 0x06, 0x00, 0x00, 0x00, 0x00, 0x50, 0x12, 0x41, 0x73, 0xed, 0x26, 0xd3,
+	0xb3, 0xa9, 0x64, 0xff, 0x4d, 0xc3, 0x0d, 0x94, 0x33, 0xe8, 0x2a,
+}
+
+// testRADIUSAccessAccept is the RADIUS payload of the Access-Accept in
+// TestRADIUSAccessAccept, answering testRADIUSAccessRequest.
+var testRADIUSAccessAccept = []byte{
+	0x02, 0x8d, 0x00, 0x14, 0x86, 0xa8, 0xd5, 0xcd, 0x69, 0x3c, 0x07, 0x5e, 0x9e, 0x18, 0xa2, 0x2d,
+	0xdd, 0x5f, 0x2b, 0xff,
+}
+
+func TestRADIUSUDPPorts(t *testing.T) {
+	for _, port := range []layers.UDPPort{1812, 1813, 1645, 1646, 3799} {
+		eth := &layers.Ethernet{
+			SrcMAC:       []byte{0x00, 0x00, 0x5e, 0x00, 0x53, 0x01},
+			DstMAC:       []byte{0x00, 0x00, 0x5e, 0x00, 0x53, 0x02},
+			EthernetType: layers.EthernetTypeIPv4,
+		}
+		ip := &layers.IPv4{
+			Version:  4,
+			TTL:      64,
+			Protocol: layers.IPProtocolUDP,
+			SrcIP:    []byte{192, 0, 2, 1},
+			DstIP:    []byte{192, 0, 2, 2},
+		}
+		udp := &layers.UDP{SrcPort: 50000, DstPort: port}
+		udp.SetNetworkLayerForChecksum(ip)
+
+		buf := gopacket.NewSerializeBuffer()
+		opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+		if err := gopacket.SerializeLayers(buf, opts, eth, ip, udp, gopacket.Payload(testRADIUSAccessRequest)); err != nil {
+			t.Fatal(err)
+		}
+
+		p := gopacket.NewPacket(buf.Bytes(), layers.LayerTypeEthernet, gopacket.Default)
+		if p.ErrorLayer() != nil {
+			t.Errorf("port %d: %v", port, p.ErrorLayer().Error())
+			continue
+		}
+		radius, ok := p.Layer(LayerTypeRADIUS).(*RADIUS)
+		if !ok {
+			t.Errorf("port %d: no RADIUS layer", port)
+			continue
+		}
+		if radius.Code != RADIUSCodeAccessRequest || p.ApplicationLayer() != radius {
+			t.Errorf("port %d: got %s", port, radius)
+		}
+	}
+}
+
+// decodeTestRADIUS decodes a bare RADIUS payload.
+func decodeTestRADIUS(t *testing.T, data []byte) *RADIUS {
+	t.Helper()
+	radius := &RADIUS{}
+	if err := radius.DecodeFromBytes(data, gopacket.NilDecodeFeedback); err != nil {
+		t.Fatalf("Failed to decode RADIUS: %v", err)
+	}
+	return radius
+}
+
+func TestRADIUSDecodeMalformed(t *testing.T) {
+	header := func(length int) []byte {
+		return append([]byte{0x01, 0x01, byte(length >> 8), byte(length)}, make([]byte, 16)...)
+	}
+	tests := []struct {
+		desc string
+		data []byte
+	}{
+		{"empty", nil},
+		{"short header", header(20)[:19]},
+		{"length field too short", header(19)},
+		{"length field exceeds data", header(21)},
+		{"attribute header truncated", append(header(21), 0x01)},
+		{"attribute length 0", append(header(22), 0x01, 0x00)},
+		{"attribute length 1", append(header(22), 0x01, 0x01)},
+		{"attribute overruns", append(header(25), 0x01, 0x07, 'A', 'd', 'm')},
+		{"second attribute overruns", append(header(29), 0x01, 0x03, 'A', 0x01, 0xff, 'A', 'd', 'm', 'i')},
+	}
+
+	for _, tt := range tests {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("%s: panic: %v", tt.desc, r)
+				}
+			}()
+			radius := &RADIUS{}
+			if err := radius.DecodeFromBytes(tt.data, gopacket.NilDecodeFeedback); err == nil {
+				t.Errorf("%s: expected error", tt.desc)
+			}
+			p := gopacket.NewPacket(tt.data, LayerTypeRADIUS, gopacket.Default)
+			if p.ErrorLayer() == nil {
+				t.Errorf("%s: expected error layer", tt.desc)
+			}
+		}()
+	}
+
+	// every truncation and single byte corruption of a valid packet
+	for i := 0; i <= len(testRADIUSAccessRequest); i++ {
+		for _, b := range []byte{0x00, 0x01, 0x02, 0x7f, 0xff} {
+			data := append([]byte(nil), testRADIUSAccessRequest[:i]...)
+			if i < len(testRADIUSAccessRequest) {
+				data = append(data, b)
+				data = append(data, testRADIUSAccessRequest[i+1:]...)
+			}
+			func() {
+				defer func() {
+					if r := recover(); r != nil {
+						t.Errorf("%x: panic: %v", data, r)
+					}
+				}()
+				(&RADIUS{}).DecodeFromBytes(data, gopacket.NilDecodeFeedback)
+				(&RADIUS{}).DecodeFromBytes(data[:i], gopacket.NilDecodeFeedback)
+			}()
+		}
+	}
+}
+
+func TestRADIUSDecodePadding(t *testing.T) {
+	data := append(append([]byte(nil), testRADIUSAccessRequest...), 0x00, 0x00, 0x00, 0x00)
+
+	p := gopacket.NewPacket(data, LayerTypeRADIUS, gopacket.Default)
+	if p.ErrorLayer() != nil {
+		t.Fatal(p.ErrorLayer().Error())
+	}
+	radius := p.Layer(LayerTypeRADIUS).(*RADIUS)
+	if !bytes.Equal(radius.LayerContents(), testRADIUSAccessRequest) {
+		t.Errorf("Contents got %x want %x", radius.LayerContents(), testRADIUSAccessRequest)
+	}
+	if !bytes.Equal(radius.LayerPayload(), []byte{0x00, 0x00, 0x00, 0x00}) {
+		t.Errorf("Payload got %x want 00000000", radius.LayerPayload())
+	}
+	if len(radius.Attributes) != 5 {
+		t.Errorf("got %d attributes want 5", len(radius.Attributes))
+	}
+	if payload := p.Layer(gopacket.LayerTypePayload); payload == nil || len(payload.LayerContents()) != 4 {
+		t.Errorf("padding layer got %v", payload)
+	}
+
+	got, err := radius.serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, testRADIUSAccessRequest) {
+		t.Errorf("serialized got %x want %x", got, testRADIUSAccessRequest)
+	}
+
+	// EAP-Message attributes remain the payload
+	eap := []byte{0x02, 0x01, 0x00, 0x05, 0x01}
+	data, err = NewRADIUS(RADIUSCodeAccessRequest, 0x01).
+		AddEAPMessage(eap).
+		AddRaw(RADIUSAttributeTypeMessageAuthenticator, make([]byte, 16)).
+		Build().serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	radius = decodeTestRADIUS(t, append(data, 0x00, 0x00))
+	if !bytes.Equal(radius.Payload(), eap) || radius.NextLayerType() != layers.LayerTypeEAP {
+		t.Errorf("EAP with padding: got payload %x, next %s", radius.Payload(), radius.NextLayerType())
+	}
+}
+
+func TestRADIUSEqual(t *testing.T) {
+	a := decodeTestRADIUS(t, testRADIUSAccessRequest)
+	b := &RADIUS{
+		Code:          a.Code,
+		Identifier:    a.Identifier,
+		Length:        a.Length,
+		Authenticator: a.Authenticator,
+	}
+	for _, v := range a.Attributes {
+		b.Attributes = append(b.Attributes, newAttribute(v.Type, append([]byte(nil), v.Value...)))
+	}
+	if !a.Equal(b) || !b.Equal(a) {
+		t.Error("decoded and constructed packets differ")
+	}
+
+	empty := &RADIUS{Code: RADIUSCodeAccessAccept, Length: 20, Attributes: []RADIUSAttribute{}}
+	if !empty.Equal(&RADIUS{Code: RADIUSCodeAccessAccept, Length: 20}) {
+		t.Error("nil and empty Attributes differ")
+	}
+	if !(*RADIUS)(nil).Equal(nil) || empty.Equal(nil) || (*RADIUS)(nil).Equal(empty) {
+		t.Error("nil packets")
+	}
+
+	tests := []struct {
+		desc   string
+		modify func(*RADIUS)
+	}{
+		{"Code", func(r *RADIUS) { r.Code = RADIUSCodeAccessReject }},
+		{"Identifier", func(r *RADIUS) { r.Identifier++ }},
+		{"Length", func(r *RADIUS) { r.Length++ }},
+		{"Authenticator", func(r *RADIUS) { r.Authenticator[0] ^= 0xff }},
+		{"AttributeCount", func(r *RADIUS) { r.Attributes = r.Attributes[1:] }},
+		{"AttributeOrder", func(r *RADIUS) { r.Attributes[0], r.Attributes[1] = r.Attributes[1], r.Attributes[0] }},
+		{"AttributeValue", func(r *RADIUS) { r.Attributes[0] = newAttribute(r.Attributes[0].Type, []byte("admin")) }},
+		{"AttributeLength", func(r *RADIUS) { r.Attributes[0].Length++ }},
+	}
+	for _, tt := range tests {
+		c := decodeTestRADIUS(t, testRADIUSAccessRequest)
+		tt.modify(c)
+		if a.Equal(c) {
+			t.Errorf("%s: got equal", tt.desc)
+		}
+	}
+}
+
+func TestRADIUSClone(t *testing.T) {
+	data := append([]byte(nil), testRADIUSAccessRequest...)
+	radius := decodeTestRADIUS(t, data)
+	c := radius.Clone()
+	if !c.Equal(radius) || !bytes.Equal(c.Contents, radius.Contents) {
+		t.Fatal("clone differs from the original")
+	}
+
+	c.Attributes[0].Value[0] = 'X'
+	c.Authenticator[0] ^= 0xff
+	c.Contents[0] = byte(RADIUSCodeAccessReject)
+	if string(radius.Attributes[0].Value) != "Admin" || radius.Authenticator[0] != testRADIUSAccessRequest[4] {
+		t.Errorf("original modified: got %s", radius.Attributes[0])
+	}
+	if !bytes.Equal(data, testRADIUSAccessRequest) {
+		t.Errorf("source buffer modified: got %x", data)
+	}
+
+	if (*RADIUS)(nil).Clone() != nil {
+		t.Error("nil clone")
+	}
+}
+
+func TestRADIUSString(t *testing.T) {
+	radius := decodeTestRADIUS(t, testRADIUSAccessRequest)
+	want := fmt.Sprintf("Access-Request Identifier=%d Length=%d Attributes=%d", radius.Identifier, len(testRADIUSAccessRequest), len(radius.Attributes))
+	if got := radius.String(); got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+
+	p := gopacket.NewPacket(testRADIUSAccessRequest, LayerTypeRADIUS, gopacket.Default)
+	if got := gopacket.LayerString(p.Layer(LayerTypeRADIUS)); got != "RADIUS\t"+want {
+		t.Errorf("LayerString got %q want %q", got, "RADIUS\t"+want)
+	}
+}
+
+func TestRADIUSDecodingLayerParser(t *testing.T) {
+	var radius RADIUS
+	parser := gopacket.NewDecodingLayerParser(LayerTypeRADIUS, &radius)
+	decoded := make([]gopacket.LayerType, 0, 1)
+	if err := parser.DecodeLayers(testRADIUSAccessRequest, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(decoded, []gopacket.LayerType{LayerTypeRADIUS}) {
+		t.Errorf("decoded %v", decoded)
+	}
+	if want := decodeTestRADIUS(t, testRADIUSAccessRequest); !radius.Equal(want) {
+		t.Errorf("got %v want %v", radius.Attributes, want.Attributes)
+	}
+
+	// no attributes are left from the previous packet
+	if err := parser.DecodeLayers(testRADIUSAccessAccept, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if radius.Code != RADIUSCodeAccessAccept || len(radius.Attributes) != 0 {
+		t.Errorf("got %s with %d attributes want %s with 0", radius.Code, len(radius.Attributes), RADIUSCodeAccessAccept)
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() { parser.DecodeLayers(testRADIUSAccessRequest, &decoded) }); allocs != 0 {
+		t.Errorf("got %v allocations want 0", allocs)
+	}
+}
+
+func BenchmarkDecodingLayerParser(b *testing.B) {
+	var radius RADIUS
+	parser := gopacket.NewDecodingLayerParser(LayerTypeRADIUS, &radius)
+	decoded := make([]gopacket.LayerType, 0, 1)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		parser.DecodeLayers(testRADIUSAccessRequest, &decoded)
+	}
+}
+
+func BenchmarkNewPacket(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		gopacket.NewPacket(testRADIUSAccessRequest, LayerTypeRADIUS, gopacket.Default)
+	}
+}
+
+func TestRADIUSAuthenticatorString(t *testing.T) {
+	radius := decodeTestRADIUS(t, testRADIUSAccessRequest)
+	s := radius.Authenticator.String()
+	if s != "3bbd2252b4c8d8441b4679bf4a2b8601" {
+		t.Errorf("got %q", s)
+	}
+
+	a, err := ParseAuthenticator(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a != radius.Authenticator {
+		t.Errorf("got %v want %v", a, radius.Authenticator)
+	}
+
+	for _, s := range []string{"", "3bbd2252b4c8d8441b4679bf4a2b86", "3bbd2252b4c8d8441b4679bf4a2b860101", "zzbd2252b4c8d8441b4679bf4a2b8601"} {
+		if _, err := ParseAuthenticator(s); err == nil {
+			t.Errorf("ParseAuthenticator(%q) expected error", s)
+		}
+	}
+}
+
+func TestRADIUSSerializeValidateAttributeTypes(t *testing.T) {
+	radius := &RADIUS{
+		Code:       RADIUSCodeAccessRequest,
+		Identifier: 1,
+		Attributes: []RADIUSAttribute{
+			newAttribute(RADIUSAttributeTypeUserName, []byte("Admin")),
+			// an integer attribute mistakenly holding a string
+			newAttribute(RADIUSAttributeTypeNASPort, []byte("port1")),
+		},
+	}
+
+	opts := SerializeOptions{ValidateAttributeTypes: true}
+	if err := radius.SerializeToWithOptions(gopacket.NewSerializeBuffer(), opts); err == nil {
+		t.Error("expected error for mistyped NAS-Port")
+	}
+	if err := radius.SerializeTo(gopacket.NewSerializeBuffer(), gopacket.SerializeOptions{}); err != nil {
+		t.Errorf("unexpected error without validation: %v", err)
+	}
+
+	radius.Attributes[1] = newAttribute(RADIUSAttributeTypeNASPort, []byte{0x00, 0x00, 0x00, 0x01})
+	if err := radius.SerializeToWithOptions(gopacket.NewSerializeBuffer(), opts); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRADIUSSerializeFixLengths(t *testing.T) {
+	radius := decodeTestRADIUS(t, testRADIUSAccessRequest)
+	radius.Length = 0
+	for i := range radius.Attributes {
+		radius.Attributes[i].Length = 0
+	}
+
+	buf := gopacket.NewSerializeBuffer()
+	if err := radius.SerializeTo(buf, gopacket.SerializeOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(buf.Bytes(), testRADIUSAccessRequest) {
+		t.Error("lengths fixed without FixLengths")
+	}
+
+	buf = gopacket.NewSerializeBuffer()
+	if err := radius.SerializeTo(buf, gopacket.SerializeOptions{FixLengths: true}); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), testRADIUSAccessRequest) {
+		t.Errorf("got %x want %x", buf.Bytes(), testRADIUSAccessRequest)
+	}
+	if int(radius.Length) != len(testRADIUSAccessRequest) {
+		t.Errorf("Length got %d want %d", radius.Length, len(testRADIUSAccessRequest))
+	}
+	for _, v := range radius.Attributes {
+		if int(v.Length) != len(v.Value)+2 {
+			t.Errorf("%s Length got %d want %d", v.Type, v.Length, len(v.Value)+2)
+		}
+	}
+}
+
+func TestRADIUSSerializeInto(t *testing.T) {
+	radius := decodeTestRADIUS(t, testRADIUSAccessRequest)
+	want, err := radius.serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, radiusMaximumRecordSizeInBytes)
+	n, err := radius.SerializeInto(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf[:n], want) {
+		t.Errorf("got %x want %x", buf[:n], want)
+	}
+
+	if _, err := radius.SerializeInto(buf[:len(want)-1]); err == nil {
+		t.Error("short buffer: expected error")
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() { radius.SerializeInto(buf) }); allocs != 0 {
+		t.Errorf("got %v allocations want 0", allocs)
+	}
+}
+
+func TestRADIUSSerializeTooLong(t *testing.T) {
+	radius := NewRADIUS(RADIUSCodeAccessAccept, 0x01).
+		AddRaw(RADIUSAttributeTypeClass, make([]byte, 254)).
+		Build()
+	buf := gopacket.NewSerializeBuffer()
+	err := radius.SerializeTo(buf, gopacket.SerializeOptions{FixLengths: true})
+	if err == nil || err.Error() != "RADIUS attribute type Class value too long (254 > 253)" {
+		t.Errorf("254 bytes value: got %v", err)
+	}
+	if len(buf.Bytes()) != 0 {
+		t.Errorf("254 bytes value: got %x", buf.Bytes())
+	}
+
+	// the split helper keeps each attribute within 253 bytes
+	radius = NewRADIUS(RADIUSCodeAccessAccept, 0x01).
+		AddLongString(RADIUSAttributeTypeReplyMessage, strings.Repeat("a", 254)).
+		Build()
+	if _, err := radius.serialize(); err != nil {
+		t.Errorf("AddLongString: unexpected error: %v", err)
+	}
+
+	radius = NewRADIUS(RADIUSCodeAccessAccept, 0x01).Build()
+	for i := 0; i < 17; i++ {
+		radius.Attributes = append(radius.Attributes, newAttribute(RADIUSAttributeTypeClass, make([]byte, 253)))
+	}
+	err = radius.SerializeTo(gopacket.NewSerializeBuffer(), gopacket.SerializeOptions{FixLengths: true})
+	if err == nil || err.Error() != "RADIUS packet length too long (4355 > 4096)" {
+		t.Errorf("4355 bytes packet: got %v", err)
+	}
+	if _, err := radius.SerializeInto(make([]byte, 8192)); err == nil {
+		t.Error("4355 bytes packet: SerializeInto expected error")
+	}
+}
+
+func TestRADIUSCodeString(t *testing.T) {
+	tests := []struct {
+		code RADIUSCode
+		want string
+	}{
+		{RADIUSCodeAccessRequest, "Access-Request"},
+		{RADIUSCodeAccessAccept, "Access-Accept"},
+		{RADIUSCodeAccountingRequest, "Accounting-Request"},
+		{RADIUSCodeAccountingResponse, "Accounting-Response"},
+		{RADIUSCodeAccessChallenge, "Access-Challenge"},
+		{RADIUSCodeStatusServer, "Status-Server"},
+		{RADIUSCodeDisconnectRequest, "Disconnect-Request"},
+		{RADIUSCodeCoANAK, "CoA-NAK"},
+		{RADIUSCode(6), "Unknown(6)"},
+		{RADIUSCode(254), "Unknown(254)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.code.String(); got != tt.want {
+			t.Errorf("RADIUSCode(%d).String() got %q want %q", uint8(tt.code), got, tt.want)
+		}
+	}
+}
+
+func TestRADIUSCodeIsKnown(t *testing.T) {
+	for _, code := range []RADIUSCode{RADIUSCodeAccessRequest, RADIUSCodeStatusServer, RADIUSCodeCoANAK} {
+		if !code.IsKnown() {
+			t.Errorf("%s: got unknown", code)
+		}
+	}
+	for _, code := range []RADIUSCode{0, 6, 250, RADIUSCodeReserved} {
+		if code.IsKnown() {
+			t.Errorf("%s: got known", code)
+		}
+	}
+
+	data := append([]byte(nil), testRADIUSAccessAccept...)
+	data[0] = 250
+
+	p := gopacket.NewPacket(data, DecodeOptions{RejectUnknownCodes: true}, gopacket.Default)
+	if p.ErrorLayer() == nil || p.Layer(LayerTypeRADIUS) != nil {
+		t.Errorf("strict: got layers %v", p.Layers())
+	}
+
+	radius := decodeTestRADIUS(t, data)
+	if radius.Code != 250 || radius.Code.IsKnown() {
+		t.Errorf("lenient: got code %s", radius.Code)
+	}
+
+	strict := RADIUS{Options: DecodeOptions{RejectUnknownCodes: true}}
+	parser := gopacket.NewDecodingLayerParser(LayerTypeRADIUS, &strict)
+	decoded := make([]gopacket.LayerType, 0, 1)
+	if err := parser.DecodeLayers(data, &decoded); err == nil {
+		t.Errorf("strict DecodingLayerParser: expected error")
+	}
+}
+
+func TestSetDefaultDecodeOptions(t *testing.T) {
+	payload := append([]byte(nil), testRADIUSAccessAccept...)
+	payload[0] = 250
+
+	eth := &layers.Ethernet{
+		SrcMAC:       []byte{0x00, 0x00, 0x5e, 0x00, 0x53, 0x01},
+		DstMAC:       []byte{0x00, 0x00, 0x5e, 0x00, 0x53, 0x02},
+		EthernetType: layers.EthernetTypeIPv4,
+	}
+	ip := &layers.IPv4{
+		Version:  4,
+		TTL:      64,
+		Protocol: layers.IPProtocolUDP,
+		SrcIP:    []byte{192, 0, 2, 1},
+		DstIP:    []byte{192, 0, 2, 2},
+	}
+	udp := &layers.UDP{SrcPort: 50000, DstPort: 1812}
+	udp.SetNetworkLayerForChecksum(ip)
+
+	buf := gopacket.NewSerializeBuffer()
+	opts := gopacket.SerializeOptions{FixLengths: true, ComputeChecksums: true}
+	if err := gopacket.SerializeLayers(buf, opts, eth, ip, udp, gopacket.Payload(payload)); err != nil {
+		t.Fatal(err)
+	}
+
+	p := gopacket.NewPacket(buf.Bytes(), layers.LayerTypeEthernet, gopacket.Default)
+	if p.Layer(LayerTypeRADIUS) == nil {
+		t.Errorf("lenient: got layers %v", p.Layers())
+	}
+
+	SetDefaultDecodeOptions(DecodeOptions{RejectUnknownCodes: true})
+	t.Cleanup(func() { SetDefaultDecodeOptions(DecodeOptions{}) })
+	p = gopacket.NewPacket(buf.Bytes(), layers.LayerTypeEthernet, gopacket.Default)
+	if p.ErrorLayer() == nil || p.Layer(LayerTypeRADIUS) != nil {
+		t.Errorf("strict: got layers %v", p.Layers())
+	}
+}
+
+func TestRADIUSAttributeTypeString(t *testing.T) {
+	tests := []struct {
+		attr RADIUSAttributeType
+		want string
+	}{
+		{RADIUSAttributeTypeUserName, "User-Name"},
+		{RADIUSAttributeTypeNASIPAddress, "NAS-IP-Address"},
+		{RADIUSAttributeTypeVendorSpecific, "Vendor-Specific"},
+		{RADIUSAttributeTypeAcctStatusType, "Acct-Status-Type"},
+		{RADIUSAttributeTypeAcctSessionId, "Acct-Session-Id"},
+		{RADIUSAttributeTypeAcctLinkCount, "Acct-Link-Count"},
+		{RADIUSAttributeTypeMessageAuthenticator, "Message-Authenticator"},
+		{RADIUSAttributeTypeErrorCause, "Error-Cause"},
+		{RADIUSAttributeType(0), "Unknown(0)"},
+		{RADIUSAttributeType(250), "Unknown(250)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.attr.String(); got != tt.want {
+			t.Errorf("RADIUSAttributeType(%d).String() got %q want %q", uint8(tt.attr), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package radius
+
+import (
+	"strings"
+)
+
+// splitRealm splits a User-Name into its user and realm parts. It handles
+// both the NAI form (user@realm) and the prefix form (realm\user).
+func splitRealm(name string) (user, realm string, prefix, ok bool) {
+	if i := strings.LastIndex(name, "@"); i >= 0 {
+		return name[:i], name[i+1:], false, true
+	}
+	if i := strings.Index(name, `\`); i >= 0 {
+		return name[i+1:], name[:i], true, true
+	}
+	return name, "", false, false
+}
+
+// Realm returns the realm of the User-Name attribute, in either the NAI form
+// (user@realm) or the prefix form (realm\user).
+func (radius *RADIUS) Realm() (string, bool) {
+	attr, ok := radius.GetAttribute(RADIUSAttributeTypeUserName)
+	if !ok {
+		return "", false
+	}
+	_, realm, _, ok := splitRealm(string(attr.Value))
+	return realm, ok
+}
+
+// StripRealm removes the realm from the User-Name attribute, leaving only the
+// user part.
+func (radius *RADIUS) StripRealm() {
+	i := radius.attributeIndex(RADIUSAttributeTypeUserName)
+	if i < 0 {
+		return
+	}
+	user, _, _, ok := splitRealm(string(radius.Attributes[i].Value))
+	if !ok {
+		return
+	}
+	radius.Attributes[i] = newAttribute(RADIUSAttributeTypeUserName, []byte(user))
+}
+
+// SetRealm replaces the realm of the User-Name attribute. The prefix form is
+// kept if it is already in use, otherwise the NAI form is used. An empty
+// realm is equivalent to StripRealm. It does nothing without a User-Name.
+func (radius *RADIUS) SetRealm(realm string) {
+	i := radius.attributeIndex(RADIUSAttributeTypeUserName)
+	if i < 0 {
+		return
+	}
+	user, _, prefix, _ := splitRealm(string(radius.Attributes[i].Value))
+
+	name := user
+	if realm != "" {
+		if prefix {
+			name = realm + `\` + user
+		} else {
+			name = user + "@" + realm
+		}
+	}
+	radius.Attributes[i] = newAttribute(RADIUSAttributeTypeUserName, []byte(name))
+}
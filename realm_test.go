@@ -0,0 +1,60 @@
+package radius
+
+import (
+	"testing"
+)
+
+func TestRADIUSRealm(t *testing.T) {
+	tests := []struct {
+		desc      string
+		userName  string
+		realm     string
+		hasRealm  bool
+		stripped  string
+		setRealm  string
+		withRealm string
+	}{
+		{"NAI", "alice@example.com", "example.com", true, "alice", "example.net", "alice@example.net"},
+		{"Prefix", `EXAMPLE\alice`, "EXAMPLE", true, "alice", "OTHER", `OTHER\alice`},
+		{"None", "alice", "", false, "alice", "example.net", "alice@example.net"},
+		{"DecoratedNAI", "example.net!alice@example.com", "example.com", true, "example.net!alice", "example.org", "example.net!alice@example.org"},
+		{"SetEmpty", "alice@example.com", "example.com", true, "alice", "", "alice"},
+	}
+
+	for _, tt := range tests {
+		radius := &RADIUS{Attributes: []RADIUSAttribute{
+			newAttribute(RADIUSAttributeTypeUserName, []byte(tt.userName)),
+		}}
+		realm, ok := radius.Realm()
+		if realm != tt.realm || ok != tt.hasRealm {
+			t.Errorf("%s: Realm() got %q, %v want %q, %v", tt.desc, realm, ok, tt.realm, tt.hasRealm)
+		}
+
+		stripped := &RADIUS{Attributes: []RADIUSAttribute{
+			newAttribute(RADIUSAttributeTypeUserName, []byte(tt.userName)),
+		}}
+		stripped.StripRealm()
+		checkUserName(t, tt.desc+" StripRealm()", stripped, tt.stripped)
+
+		radius.SetRealm(tt.setRealm)
+		checkUserName(t, tt.desc+" SetRealm()", radius, tt.withRealm)
+	}
+
+	radius := &RADIUS{}
+	radius.SetRealm("example.com")
+	radius.StripRealm()
+	if _, ok := radius.Realm(); ok || len(radius.Attributes) != 0 {
+		t.Error("realm helpers must not add a User-Name")
+	}
+}
+
+func checkUserName(t *testing.T, desc string, radius *RADIUS, want string) {
+	t.Helper()
+	attr := radius.Attributes[0]
+	if string(attr.Value) != want {
+		t.Errorf("%s: got %q want %q", desc, attr.Value, want)
+	}
+	if int(attr.Length) != len(want)+2 {
+		t.Errorf("%s: Length got %d want %d", desc, attr.Length, len(want)+2)
+	}
+}
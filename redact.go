@@ -0,0 +1,111 @@
+package radius
+
+import (
+	"encoding/binary"
+)
+
+// redactedAttributeTypes are the attribute types whose values Redact
+// replaces: the attributes carrying passwords.
+var redactedAttributeTypes = []RADIUSAttributeType{
+	RADIUSAttributeTypeUserPassword,
+	RADIUSAttributeTypeCHAPPassword,
+	RADIUSAttributeTypeTunnelPassword,
+}
+
+// Redact returns a Clone of the packet with the User-Password, CHAP-Password
+// and Tunnel-Password values and the MS-MPPE-Send-Key and MS-MPPE-Recv-Key of
+// Microsoft Vendor-Specific attributes replaced by zeroes, e.g. before
+// sharing a capture. See RedactWith.
+func (radius *RADIUS) Redact() *RADIUS {
+	c := radius.redact(redactedAttributeTypes)
+	for _, v := range c.Attributes {
+		if v.Type == RADIUSAttributeTypeVendorSpecific {
+			redactMPPEKeys(v.Value)
+		}
+	}
+	c.redactLayer()
+	return c
+}
+
+// RedactWith returns a Clone of the packet with the values of the attributes
+// of the given types replaced by zeroes of the same length, so that the
+// packet keeps its structure and lengths and still serializes. The
+// Message-Authenticator is zeroed too, as it no longer matches the packet;
+// call ComputeMessageAuthenticator on the result to sign it again. The
+// Contents of the layer are the serialized form of the redacted packet, and
+// its Payload the redacted EAP-Message values, or zeroed padding.
+func (radius *RADIUS) RedactWith(types ...RADIUSAttributeType) *RADIUS {
+	c := radius.redact(types)
+	c.redactLayer()
+	return c
+}
+
+// redact returns a Clone of the packet with the values of the attributes of
+// the given types and of the Message-Authenticator zeroed.
+func (radius *RADIUS) redact(types []RADIUSAttributeType) *RADIUS {
+	c := radius.Clone()
+	for _, v := range c.Attributes {
+		if v.Type == RADIUSAttributeTypeMessageAuthenticator {
+			zeroBytes(v.Value)
+			continue
+		}
+		for _, t := range types {
+			if v.Type == t {
+				zeroBytes(v.Value)
+				break
+			}
+		}
+	}
+	return c
+}
+
+// redactLayer replaces the Contents and Payload of a redacted packet, copied
+// from the original packet by Clone, with the serialized form of the redacted
+// attributes and their EAP-Message values. Contents are left empty for a
+// packet that does not serialize.
+func (radius *RADIUS) redactLayer() {
+	radius.Contents, _ = radius.serialize()
+	if !radius.HasAttribute(RADIUSAttributeTypeEAPMessage) {
+		if radius.BaseLayer.Payload != nil {
+			radius.BaseLayer.Payload = make([]byte, len(radius.BaseLayer.Payload))
+		}
+		return
+	}
+	radius.BaseLayer.Payload = nil
+	for _, v := range radius.Attributes {
+		if v.Type == RADIUSAttributeTypeEAPMessage {
+			radius.BaseLayer.Payload = append(radius.BaseLayer.Payload, v.Value...)
+		}
+	}
+}
+
+// redactMPPEKeys zeroes the values of the MS-MPPE-Send-Key and
+// MS-MPPE-Recv-Key sub-attributes of a Microsoft Vendor-Specific value, in
+// place. A malformed value is zeroed from the first invalid sub-attribute.
+func redactMPPEKeys(value RADIUSAttributeValue) {
+	if len(value) < 4 || binary.BigEndian.Uint32(value) != RADIUSVendorIDMicrosoft {
+		return
+	}
+	data := value[4:]
+	for len(data) > 0 {
+		n := 0
+		if len(data) >= 2 {
+			n = int(data[1])
+		}
+		if n < 2 || n > len(data) {
+			zeroBytes(data)
+			return
+		}
+		if data[0] == RADIUSMicrosoftAttributeTypeMPPESendKey || data[0] == RADIUSMicrosoftAttributeTypeMPPERecvKey {
+			zeroBytes(data[2:n])
+		}
+		data = data[n:]
+	}
+}
+
+// zeroBytes sets every byte of b to zero.
+func zeroBytes(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
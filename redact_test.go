@@ -0,0 +1,93 @@
+package radius
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestRADIUSRedact(t *testing.T) {
+	radius := decodeTestRADIUS(t, testRADIUSAccessRequest)
+	redacted := radius.Redact()
+
+	got, err := redacted.serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := append([]byte(nil), testRADIUSAccessRequest...)
+	copy(want[29:45], make([]byte, 16)) // User-Password
+	copy(want[59:75], make([]byte, 16)) // Message-Authenticator
+	if !bytes.Equal(got, want) {
+		t.Errorf("got\n%x\nwant\n%x", got, want)
+	}
+	if !bytes.Equal(redacted.LayerContents(), want) || bytes.Contains(redacted.LayerContents(), testRADIUSAccessRequest[29:45]) {
+		t.Errorf("Contents got\n%x\nwant\n%x", redacted.LayerContents(), want)
+	}
+	if attr, _ := redacted.GetAttribute(RADIUSAttributeTypeUserPassword); attr.Length != 18 || len(attr.Value) != 16 {
+		t.Errorf("User-Password got %s", attr)
+	}
+	if !bytes.Equal(radius.Contents, testRADIUSAccessRequest) || bytes.Equal(radius.Attributes[1].Value, make([]byte, 16)) {
+		t.Error("original packet modified")
+	}
+
+	if err := redacted.ComputeMessageAuthenticator([]byte("secret")); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := redacted.VerifyMessageAuthenticator([]byte("secret")); !ok || err != nil {
+		t.Errorf("re-signed: got %v, %v", ok, err)
+	}
+}
+
+func TestRADIUSRedactPayload(t *testing.T) {
+	eap := []byte{0x02, 0x01, 0x00, 0x09, 0x01, 'u', 's', 'e', 'r'}
+	data, err := NewRADIUS(RADIUSCodeAccessRequest, 0x01).
+		AddEAPMessage(eap).
+		AddRaw(RADIUSAttributeTypeMessageAuthenticator, bytes.Repeat([]byte{0xaa}, 16)).
+		Build().serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	radius := decodeTestRADIUS(t, data)
+	redacted := radius.RedactWith(RADIUSAttributeTypeEAPMessage)
+	if !bytes.Equal(redacted.Payload(), make([]byte, len(eap))) {
+		t.Errorf("EAP payload got %x", redacted.Payload())
+	}
+	if bytes.Contains(redacted.LayerContents(), eap[4:]) || bytes.Contains(redacted.LayerContents(), bytes.Repeat([]byte{0xaa}, 16)) {
+		t.Errorf("Contents got %x", redacted.LayerContents())
+	}
+
+	// padding beyond the Length field is zeroed
+	radius = decodeTestRADIUS(t, append(append([]byte(nil), testRADIUSAccessRequest...), 's', 'e', 'c'))
+	redacted = radius.Redact()
+	if !bytes.Equal(redacted.Payload(), make([]byte, 3)) || !bytes.Equal(radius.Payload(), []byte("sec")) {
+		t.Errorf("padding got %x, original %x", redacted.Payload(), radius.Payload())
+	}
+}
+
+func TestRADIUSRedactMPPEKeys(t *testing.T) {
+	mppe := []byte("\x00\x00\x01\x37" +
+		"\x10\x06\x80\x01\xaa\xbb" +
+		"\x07\x06\x00\x00\x00\x01" +
+		"\x11\x05\x80\x02\xcc")
+	radius := &RADIUS{Attributes: []RADIUSAttribute{
+		newAttribute(RADIUSAttributeTypeVendorSpecific, mppe),
+		testCiscoAVPairVSA,
+		newAttribute(RADIUSAttributeTypeClass, []byte("class")),
+	}}
+
+	redacted := radius.Redact()
+	want := "\x00\x00\x01\x37" +
+		"\x10\x06\x00\x00\x00\x00" +
+		"\x07\x06\x00\x00\x00\x01" +
+		"\x11\x05\x00\x00\x00"
+	if got := string(redacted.Attributes[0].Value); got != want {
+		t.Errorf("Microsoft got %x want %x", got, want)
+	}
+	if !bytes.Equal(redacted.Attributes[1].Value, testCiscoAVPairVSA.Value) || string(redacted.Attributes[2].Value) != "class" {
+		t.Errorf("other attributes got %v", redacted.Attributes[1:])
+	}
+
+	redacted = radius.RedactWith(RADIUSAttributeTypeClass)
+	if string(redacted.Attributes[0].Value) != string(mppe) || !bytes.Equal(redacted.Attributes[2].Value, make([]byte, 5)) {
+		t.Errorf("RedactWith(Class) got %v", redacted.Attributes)
+	}
+}
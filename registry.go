@@ -0,0 +1,50 @@
+package radius
+
+import (
+	"fmt"
+	"sync"
+)
+
+// registeredAttributeType is a site-specific attribute type added with
+// RegisterAttribute.
+type registeredAttributeType struct {
+	name      string
+	valueType AttrValueType
+}
+
+var attributeRegistry = struct {
+	sync.RWMutex
+	types map[RADIUSAttributeType]registeredAttributeType
+}{
+	types: make(map[RADIUSAttributeType]registeredAttributeType),
+}
+
+// RegisterAttribute adds a site-specific attribute type, such as one of the
+// 192-223 Experimental Use range (RFC3575), so that its name and value type
+// are used by String, ValueType, DecodedValue and HexDump. Registering a type
+// again replaces it. It is safe to call concurrently with decoding, and
+// panics if t is a standard attribute type.
+func RegisterAttribute(t RADIUSAttributeType, name string, valueType AttrValueType) {
+	if t.isStandard() {
+		panic(fmt.Sprintf("RADIUS RegisterAttribute of standard attribute type %s (%d)", t, uint8(t)))
+	}
+
+	attributeRegistry.Lock()
+	defer attributeRegistry.Unlock()
+	attributeRegistry.types[t] = registeredAttributeType{name: name, valueType: valueType}
+}
+
+// unregisterAttribute removes the registration of t, if any.
+func unregisterAttribute(t RADIUSAttributeType) {
+	attributeRegistry.Lock()
+	defer attributeRegistry.Unlock()
+	delete(attributeRegistry.types, t)
+}
+
+// registeredAttribute returns the registration of t, if any.
+func registeredAttribute(t RADIUSAttributeType) (registeredAttributeType, bool) {
+	attributeRegistry.RLock()
+	defer attributeRegistry.RUnlock()
+	r, ok := attributeRegistry.types[t]
+	return r, ok
+}
@@ -0,0 +1,82 @@
+package radius
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRegisterAttribute(t *testing.T) {
+	siteVLAN := RADIUSAttributeType(200)
+	siteGroup := RADIUSAttributeType(201)
+	t.Cleanup(func() {
+		unregisterAttribute(siteVLAN)
+		unregisterAttribute(siteGroup)
+	})
+	RegisterAttribute(siteVLAN, "Site-VLAN", AttrValueTypeInteger)
+	RegisterAttribute(siteGroup, "Site-Group", AttrValueTypeOctets)
+	RegisterAttribute(siteGroup, "Site-Group", AttrValueTypeString)
+
+	if s := siteVLAN.String(); s != "Site-VLAN" {
+		t.Errorf("String got %q want %q", s, "Site-VLAN")
+	}
+	if v := siteGroup.ValueType(); v != AttrValueTypeString {
+		t.Errorf("ValueType got %s want %s", v, AttrValueTypeString)
+	}
+	if s := RADIUSAttributeType(202).String(); s != "Unknown(202)" {
+		t.Errorf("unregistered String got %q", s)
+	}
+
+	attr := newAttribute(siteVLAN, uint32Value(42))
+	if v, err := attr.DecodedValue(); err != nil || v != uint32(42) {
+		t.Errorf("DecodedValue got %v, %v want 42", v, err)
+	}
+	radius := &RADIUS{Attributes: []RADIUSAttribute{attr}}
+	if dump := radius.HexDump(); !strings.Contains(dump, "Site-VLAN (200), Length: 6") {
+		t.Errorf("HexDump got:\n%s", dump)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				RegisterAttribute(siteGroup, "Site-Group", AttrValueTypeString)
+				_ = siteGroup.String()
+			}
+		}()
+	}
+	wg.Wait()
+
+	defer func() {
+		if recover() == nil {
+			t.Error("RegisterAttribute of a standard type must panic")
+		}
+		if s := RADIUSAttributeTypeUserName.String(); s != "User-Name" {
+			t.Errorf("User-Name String got %q", s)
+		}
+	}()
+	RegisterAttribute(RADIUSAttributeTypeUserName, "Site-User", AttrValueTypeOctets)
+}
+
+func TestRADIUSAttributeTypeIsStandard(t *testing.T) {
+	for _, typ := range []RADIUSAttributeType{RADIUSAttributeTypeUserName, RADIUSAttributeTypeVendorSpecific, RADIUSAttributeTypeLongExtendedType2} {
+		if !typ.isStandard() {
+			t.Errorf("%s: got not standard", typ)
+		}
+	}
+	for _, typ := range []RADIUSAttributeType{0, 200, 255} {
+		if typ.isStandard() {
+			t.Errorf("%s: got standard", typ)
+		}
+	}
+
+	// a registered type is not standard, and can be registered again
+	RegisterAttribute(210, "Site-Zone", AttrValueTypeString)
+	t.Cleanup(func() { unregisterAttribute(210) })
+	if RADIUSAttributeType(210).isStandard() {
+		t.Error("registered type: got standard")
+	}
+	RegisterAttribute(210, "Site-Zone", AttrValueTypeInteger)
+}
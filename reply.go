@@ -0,0 +1,56 @@
+package radius
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// ReplyMessages returns the text of all Reply-Message attributes in the order
+// they must be displayed to the user.
+func (radius *RADIUS) ReplyMessages() []string {
+	var messages []string
+	for _, v := range radius.Attributes {
+		if v.Type == RADIUSAttributeTypeReplyMessage {
+			messages = append(messages, string(v.Value))
+		}
+	}
+	return messages
+}
+
+// ReplyMessageText returns all Reply-Message attributes joined with newlines,
+// ready for display. It returns an empty string if there are none.
+func (radius *RADIUS) ReplyMessageText() string {
+	return strings.Join(radius.ReplyMessages(), "\n")
+}
+
+// AddReplyMessage appends text as Reply-Message attributes of at most 253
+// bytes each, which clients display concatenated in order (RFC2865 5.18).
+// Text too long for one attribute is split after the last line break that
+// fits, or after the last space that fits for lines too long for one
+// attribute, or at a character boundary for lack of spaces. The line breaks
+// and spaces are kept, so that the values concatenated reproduce text.
+func (b *RADIUSBuilder) AddReplyMessage(text string) *RADIUSBuilder {
+	for len(text) > radiusMaximumAttributeValueSizeInBytes {
+		window := text[:radiusMaximumAttributeValueSizeInBytes]
+		i := strings.LastIndexByte(window, '\n')
+		if i < 0 {
+			i = strings.LastIndexByte(window, ' ')
+		}
+		if i >= 0 {
+			b.AddRaw(RADIUSAttributeTypeReplyMessage, []byte(text[:i+1]))
+			text = text[i+1:]
+			continue
+		}
+
+		i = radiusMaximumAttributeValueSizeInBytes
+		for i > 0 && !utf8.RuneStart(text[i]) {
+			i--
+		}
+		b.AddRaw(RADIUSAttributeTypeReplyMessage, []byte(text[:i]))
+		text = text[i:]
+	}
+	if len(text) > 0 {
+		b.AddRaw(RADIUSAttributeTypeReplyMessage, []byte(text))
+	}
+	return b
+}
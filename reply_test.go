@@ -0,0 +1,70 @@
+package radius
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestRADIUSReplyMessageText(t *testing.T) {
+	radius := &RADIUS{Attributes: []RADIUSAttribute{
+		newAttribute(RADIUSAttributeTypeReplyMessage, []byte("Welcome.")),
+		newAttribute(RADIUSAttributeTypeUserName, []byte("Admin")),
+		newAttribute(RADIUSAttributeTypeReplyMessage, []byte("Your password expires in 3 days.")),
+	}}
+
+	if got, want := radius.ReplyMessageText(), "Welcome.\nYour password expires in 3 days."; got != want {
+		t.Errorf("got %q want %q", got, want)
+	}
+	if got := (&RADIUS{}).ReplyMessageText(); got != "" {
+		t.Errorf("got %q want empty", got)
+	}
+}
+
+func TestRADIUSBuilderAddReplyMessage(t *testing.T) {
+	var lines []string
+	for i := 0; len(strings.Join(lines, "\n")) < 500; i++ {
+		lines = append(lines, fmt.Sprintf("Line %02d: access is denied outside business hours.", i))
+	}
+	multiLine := strings.Join(lines, "\n")
+
+	tests := []struct {
+		desc string
+		text string
+		want []string
+	}{
+		{"Short", "Welcome.", []string{"Welcome."}},
+		{"MultiLine", multiLine, nil},
+		{"Words", strings.Repeat("word ", 100), []string{strings.Repeat("word ", 50), strings.Repeat("word ", 50)}},
+		{"NoSpaces", strings.Repeat("é", 200), []string{strings.Repeat("é", 126), strings.Repeat("é", 74)}},
+		{"Empty", "", nil},
+	}
+
+	for _, tt := range tests {
+		radius := NewRADIUS(RADIUSCodeAccessReject, 1).AddReplyMessage(tt.text).Build()
+		for _, v := range radius.Attributes {
+			if v.Type != RADIUSAttributeTypeReplyMessage || len(v.Value) == 0 || len(v.Value) > 253 || !utf8.Valid(v.Value) {
+				t.Errorf("%s: invalid attribute %s length %d", tt.desc, v.Type, len(v.Value))
+			}
+		}
+		if got := string(radius.ConcatValues(RADIUSAttributeTypeReplyMessage)); got != tt.text {
+			t.Errorf("%s: concatenated got %q want %q", tt.desc, got, tt.text)
+		}
+		if tt.want != nil && !reflect.DeepEqual(radius.ReplyMessages(), tt.want) {
+			t.Errorf("%s: got %q want %q", tt.desc, radius.ReplyMessages(), tt.want)
+		}
+	}
+
+	// multiple lines are split after a line break
+	messages := NewRADIUS(RADIUSCodeAccessReject, 1).AddReplyMessage(multiLine).Build().ReplyMessages()
+	if len(messages) < 2 {
+		t.Fatalf("MultiLine: got %d attributes want at least 2", len(messages))
+	}
+	for _, m := range messages[:len(messages)-1] {
+		if !strings.HasSuffix(m, "\n") {
+			t.Errorf("MultiLine: got %q not ending with a line break", m)
+		}
+	}
+}
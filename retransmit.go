@@ -0,0 +1,19 @@
+package radius
+
+// PacketKey identifies a request among the retransmissions of a client: its
+// code, Identifier and Request Authenticator, which a client keeps unchanged
+// when retransmitting (RFC5080 2.2.2). The attributes are not part of it.
+// It is comparable, to be used as a map key.
+type PacketKey [18]byte
+
+// Key returns the key of the packet for duplicate detection, e.g. for a
+// proxy dropping retransmitted Access-Requests within its retransmit window.
+// A key is only unique among the packets of a single client, so the caller
+// combines it with the client source address and port.
+func (radius *RADIUS) Key() PacketKey {
+	var k PacketKey
+	k[0] = byte(radius.Code)
+	k[1] = byte(radius.Identifier)
+	copy(k[2:], radius.Authenticator[:])
+	return k
+}
@@ -0,0 +1,37 @@
+package radius
+
+import (
+	"testing"
+)
+
+func TestRADIUSKey(t *testing.T) {
+	a := decodeTestRADIUS(t, testRADIUSAccessRequest)
+	b := decodeTestRADIUS(t, testRADIUSAccessRequest)
+	if a.Key() != b.Key() {
+		t.Errorf("identical packets: got %x and %x", a.Key(), b.Key())
+	}
+
+	// the attributes are not part of the key
+	b.Attributes = b.Attributes[1:]
+	if a.Key() != b.Key() {
+		t.Errorf("different attributes: got %x and %x", a.Key(), b.Key())
+	}
+
+	tests := []struct {
+		desc   string
+		modify func(*RADIUS)
+	}{
+		{"Identifier", func(r *RADIUS) { r.Identifier++ }},
+		{"Code", func(r *RADIUS) { r.Code = RADIUSCodeAccountingRequest }},
+		{"Authenticator", func(r *RADIUS) { r.Authenticator[15] ^= 0x01 }},
+	}
+	seen := map[PacketKey]string{a.Key(): "original"}
+	for _, tt := range tests {
+		c := decodeTestRADIUS(t, testRADIUSAccessRequest)
+		tt.modify(c)
+		if desc, ok := seen[c.Key()]; ok {
+			t.Errorf("%s: same key as %s", tt.desc, desc)
+		}
+		seen[c.Key()] = tt.desc
+	}
+}
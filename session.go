@@ -0,0 +1,178 @@
+package radius
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// SessionKey identifies an accounting session of a NAS.
+type SessionKey struct {
+	NAS       string
+	SessionID string
+}
+
+// SessionKey returns the key of the accounting session of the packet, made of
+// the NASIdentity and the Acct-Session-Id attribute.
+func (radius *RADIUS) SessionKey() (SessionKey, bool) {
+	attr, ok := radius.GetAttribute(RADIUSAttributeTypeAcctSessionId)
+	if !ok {
+		return SessionKey{}, false
+	}
+	nas, _ := radius.NASIdentity()
+	return SessionKey{NAS: nas, SessionID: string(attr.Value)}, true
+}
+
+// sessionStateStoppedLimit is the number of stopped sessions a SessionState
+// remembers to flag records after their Stop.
+const sessionStateStoppedLimit = 4096
+
+// SessionState tracks the lifecycle of accounting sessions (Start, then any
+// number of Interim-Update, then Stop) from a stream of Accounting-Request
+// records. A session is forgotten once stopped, except for the most recently
+// stopped ones, so that its memory stays bounded by the sessions in progress.
+// The zero value is ready to use.
+type SessionState struct {
+	sessions map[SessionKey]AcctStatusType
+
+	// stopped maps the recently stopped sessions to the sequence number of
+	// their entry in stoppedOrder, oldest first, so that an entry of a
+	// session stopped again later does not evict it.
+	stopped      map[SessionKey]uint64
+	stoppedOrder []stoppedSession
+	stoppedSeq   uint64
+}
+
+type stoppedSession struct {
+	key SessionKey
+	seq uint64
+}
+
+// Apply consumes an accounting record and returns an error if it violates the
+// lifecycle of its session, e.g. a Stop without a preceding Start or an
+// Interim-Update after Stop. The record still advances the state so the rest
+// of the stream is checked against it. Accounting-On and Accounting-Off end
+// all sessions of the NAS, as it lost or is about to lose them. Records after
+// Stop are only recognized for the last 4096 stopped sessions; older ones are
+// reported as without Start.
+func (s *SessionState) Apply(r *RADIUS) error {
+	if r.Code != RADIUSCodeAccountingRequest {
+		return fmt.Errorf("RADIUS record is %s, not %s", r.Code, RADIUSCodeAccountingRequest)
+	}
+	status, ok := r.AcctStatusType()
+	if !ok {
+		return fmt.Errorf("RADIUS record has no %s", RADIUSAttributeTypeAcctStatusType)
+	}
+	if s.sessions == nil {
+		s.sessions = make(map[SessionKey]AcctStatusType)
+		s.stopped = make(map[SessionKey]uint64)
+	}
+
+	if r.IsAccountingSystemEvent() {
+		nas, _ := r.NASIdentity()
+		for key := range s.sessions {
+			if key.NAS == nas {
+				delete(s.sessions, key)
+			}
+		}
+		for key := range s.stopped {
+			if key.NAS == nas {
+				delete(s.stopped, key)
+			}
+		}
+		return nil
+	}
+
+	key, ok := r.SessionKey()
+	if !ok {
+		return fmt.Errorf("RADIUS %s record has no %s", status, RADIUSAttributeTypeAcctSessionId)
+	}
+	last, ok := s.sessions[key]
+	if _, stopped := s.stopped[key]; stopped {
+		last, ok = AcctStatusTypeStop, true
+	}
+
+	var err error
+	switch status {
+	case AcctStatusTypeStart:
+		if ok {
+			err = fmt.Errorf("RADIUS session %q %s after %s", key.SessionID, status, last)
+		}
+		delete(s.stopped, key)
+		s.sessions[key] = AcctStatusTypeStart
+	case AcctStatusTypeInterimUpdate:
+		if !ok {
+			err = fmt.Errorf("RADIUS session %q %s without %s", key.SessionID, status, AcctStatusTypeStart)
+			s.sessions[key] = AcctStatusTypeStart
+		} else if last == AcctStatusTypeStop {
+			err = fmt.Errorf("RADIUS session %q %s after %s", key.SessionID, status, last)
+		}
+	case AcctStatusTypeStop:
+		if !ok {
+			err = fmt.Errorf("RADIUS session %q %s without %s", key.SessionID, status, AcctStatusTypeStart)
+		} else if last == AcctStatusTypeStop {
+			err = fmt.Errorf("RADIUS session %q %s after %s", key.SessionID, status, last)
+		}
+		s.stop(key)
+	}
+	return err
+}
+
+// stop forgets a session, remembering it among the recently stopped ones and
+// evicting the oldest of them beyond sessionStateStoppedLimit.
+func (s *SessionState) stop(key SessionKey) {
+	delete(s.sessions, key)
+	s.stoppedSeq++
+	s.stopped[key] = s.stoppedSeq
+	s.stoppedOrder = append(s.stoppedOrder, stoppedSession{key, s.stoppedSeq})
+	for len(s.stoppedOrder) > sessionStateStoppedLimit {
+		oldest := s.stoppedOrder[0]
+		if seq, ok := s.stopped[oldest.key]; ok && seq == oldest.seq {
+			delete(s.stopped, oldest.key)
+		}
+		s.stoppedOrder = s.stoppedOrder[1:]
+	}
+}
+
+// Remove forgets a session, in progress or recently stopped, e.g. once it
+// has been settled.
+func (s *SessionState) Remove(key SessionKey) {
+	delete(s.sessions, key)
+	delete(s.stopped, key)
+}
+
+// Classes returns the values of all Class attributes, which a server sets in
+// an Access-Accept for the NAS to echo unmodified in accounting
+// (RFC2865 5.25).
+func (radius *RADIUS) Classes() [][]byte {
+	var classes [][]byte
+	for _, v := range radius.Attributes {
+		if v.Type == RADIUSAttributeTypeClass {
+			classes = append(classes, []byte(v.Value))
+		}
+	}
+	return classes
+}
+
+// LinkAuthToAccounting reports whether acctStart belongs to the session
+// authorized by authAccept, by checking every Class of authAccept is echoed
+// in acctStart. Without a Class in authAccept the packets cannot be linked.
+func LinkAuthToAccounting(authAccept, acctStart *RADIUS) bool {
+	classes := authAccept.Classes()
+	if len(classes) == 0 {
+		return false
+	}
+	echoed := acctStart.Classes()
+	for _, class := range classes {
+		found := false
+		for _, v := range echoed {
+			if bytes.Equal(v, class) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,120 @@
+package radius
+
+import (
+	"fmt"
+	"testing"
+)
+
+func newTestSessionRecord(status AcctStatusType, nas, sessionID string) *RADIUS {
+	radius := &RADIUS{
+		Code: RADIUSCodeAccountingRequest,
+		Attributes: []RADIUSAttribute{
+			newAttribute(RADIUSAttributeTypeAcctStatusType, uint32Value(uint32(status))),
+			newAttribute(RADIUSAttributeTypeNASIdentifier, []byte(nas)),
+		},
+	}
+	if sessionID != "" {
+		radius.Attributes = append(radius.Attributes, newAttribute(RADIUSAttributeTypeAcctSessionId, []byte(sessionID)))
+	}
+	return radius
+}
+
+func TestSessionStateApply(t *testing.T) {
+	tests := []struct {
+		record  *RADIUS
+		wantErr bool
+	}{
+		{newTestSessionRecord(AcctStatusTypeStart, "nas01", "0001"), false},
+		{newTestSessionRecord(AcctStatusTypeInterimUpdate, "nas01", "0001"), false},
+		{newTestSessionRecord(AcctStatusTypeInterimUpdate, "nas01", "0001"), false},
+		// same session ID from another NAS is another session
+		{newTestSessionRecord(AcctStatusTypeStop, "nas02", "0001"), true},
+		{newTestSessionRecord(AcctStatusTypeStop, "nas01", "0001"), false},
+		{newTestSessionRecord(AcctStatusTypeInterimUpdate, "nas01", "0001"), true},
+		{newTestSessionRecord(AcctStatusTypeStop, "nas01", "0001"), true},
+		{newTestSessionRecord(AcctStatusTypeStart, "nas01", "0002"), false},
+		{newTestSessionRecord(AcctStatusTypeStart, "nas01", "0002"), true},
+		// the NAS rebooted, all its sessions are gone
+		{newTestSessionRecord(AcctStatusTypeAccountingOn, "nas01", ""), false},
+		{newTestSessionRecord(AcctStatusTypeInterimUpdate, "nas01", "0002"), true},
+		{newTestSessionRecord(AcctStatusTypeStop, "nas01", ""), true},
+		{&RADIUS{Code: RADIUSCodeAccessRequest}, true},
+	}
+
+	var s SessionState
+	for i, tt := range tests {
+		err := s.Apply(tt.record)
+		if tt.wantErr && err == nil {
+			t.Errorf("record %d: expected error", i)
+		} else if !tt.wantErr && err != nil {
+			t.Errorf("record %d: unexpected error: %v", i, err)
+		}
+	}
+}
+
+func TestSessionStateBounded(t *testing.T) {
+	var s SessionState
+	n := 3 * sessionStateStoppedLimit
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("%08x", i)
+		if err := s.Apply(newTestSessionRecord(AcctStatusTypeStart, "nas01", id)); err != nil {
+			t.Fatalf("%s Start: unexpected error: %v", id, err)
+		}
+		if err := s.Apply(newTestSessionRecord(AcctStatusTypeStop, "nas01", id)); err != nil {
+			t.Fatalf("%s Stop: unexpected error: %v", id, err)
+		}
+	}
+	if len(s.sessions) != 0 {
+		t.Errorf("got %d sessions in progress want 0", len(s.sessions))
+	}
+	if len(s.stopped) != sessionStateStoppedLimit || len(s.stoppedOrder) != sessionStateStoppedLimit {
+		t.Errorf("got %d stopped sessions in %d entries want %d", len(s.stopped), len(s.stoppedOrder), sessionStateStoppedLimit)
+	}
+
+	// the most recently stopped session is still flagged
+	last := fmt.Sprintf("%08x", n-1)
+	if err := s.Apply(newTestSessionRecord(AcctStatusTypeInterimUpdate, "nas01", last)); err == nil {
+		t.Errorf("%s Interim-Update after Stop: expected error", last)
+	}
+	if len(s.sessions) != 0 {
+		t.Errorf("Interim-Update after Stop got %d sessions in progress want 0", len(s.sessions))
+	}
+}
+
+func TestLinkAuthToAccounting(t *testing.T) {
+	accept := func(classes ...string) *RADIUS {
+		b := NewRADIUS(RADIUSCodeAccessAccept, 1)
+		for _, class := range classes {
+			b.AddRaw(RADIUSAttributeTypeClass, []byte(class))
+		}
+		return b.Build()
+	}
+	start := func(classes ...string) *RADIUS {
+		b := NewRADIUS(RADIUSCodeAccountingRequest, 2).
+			AddRaw(RADIUSAttributeTypeAcctStatusType, uint32Value(uint32(AcctStatusTypeStart)))
+		for _, class := range classes {
+			b.AddRaw(RADIUSAttributeTypeClass, []byte(class))
+		}
+		return b.Build()
+	}
+
+	tests := []struct {
+		desc       string
+		authAccept *RADIUS
+		acctStart  *RADIUS
+		want       bool
+	}{
+		{"Echoed", accept("session-0001"), start("session-0001"), true},
+		{"EchoedAll", accept("session-0001", "policy=gold"), start("policy=gold", "session-0001"), true},
+		{"Different", accept("session-0001"), start("session-0002"), false},
+		{"Partial", accept("session-0001", "policy=gold"), start("session-0001"), false},
+		{"NotEchoed", accept("session-0001"), start(), false},
+		{"NoClass", accept(), start(), false},
+	}
+
+	for _, tt := range tests {
+		if got := LinkAuthToAccounting(tt.authAccept, tt.acctStart); got != tt.want {
+			t.Errorf("%s: got %v want %v", tt.desc, got, tt.want)
+		}
+	}
+}
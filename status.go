@@ -0,0 +1,32 @@
+package radius
+
+import (
+	"fmt"
+)
+
+// IsStatusServer reports whether the packet is a Status-Server, the
+// watchdog probe sent by clients and load balancers to check that a server
+// is alive (RFC5997).
+func (radius *RADIUS) IsStatusServer() bool {
+	return radius.Code == RADIUSCodeStatusServer
+}
+
+// NewStatusServerResponse returns the response answering the Status-Server
+// req: an Access-Accept on the authentication port, or an
+// Accounting-Response on the accounting port (RFC5997 3.), as given by code.
+// The Response Authenticator is computed with the shared secret.
+func NewStatusServerResponse(req *RADIUS, code RADIUSCode, secret []byte) (*RADIUS, error) {
+	if !req.IsStatusServer() {
+		return nil, fmt.Errorf("RADIUS %s is not %s", req.Code, RADIUSCodeStatusServer)
+	}
+	if code != RADIUSCodeAccessAccept && code != RADIUSCodeAccountingResponse {
+		return nil, fmt.Errorf("RADIUS %s does not answer %s", code, RADIUSCodeStatusServer)
+	}
+	resp := NewRADIUS(code, req.Identifier).Build()
+	authenticator, err := resp.ComputeResponseAuthenticator(req.Authenticator, secret)
+	if err != nil {
+		return nil, err
+	}
+	resp.Authenticator = authenticator
+	return resp, nil
+}
@@ -0,0 +1,75 @@
+package radius
+
+import (
+	"bytes"
+	"testing"
+)
+
+// testRADIUSStatusServer is the Status-Server of RFC5997 6.1, with a
+// Message-Authenticator. The shared secret is "xyzzy5461".
+var testRADIUSStatusServer = []byte{
+	0x0c, 0xda, 0x00, 0x26, 0x8a, 0x54, 0xf4, 0x68, 0x6f, 0xb3, 0x94, 0xc5, 0x28, 0x66, 0xe3, 0x02,
+	0x18, 0x5d, 0x06, 0x23, 0x50, 0x12, 0x5a, 0x66, 0x5e, 0x2e, 0x1e, 0x84, 0x11, 0xf3, 0xe2, 0x43,
+	0x82, 0x20, 0x97, 0xc8, 0x4f, 0xa3,
+}
+
+func TestRADIUSStatusServer(t *testing.T) {
+	secret := []byte("xyzzy5461")
+	radius := decodeTestRADIUS(t, testRADIUSStatusServer)
+
+	if !radius.IsStatusServer() || radius.Code.String() != "Status-Server" {
+		t.Errorf("got %s want Status-Server", radius.Code)
+	}
+	if decodeTestRADIUS(t, testRADIUSAccessRequest).IsStatusServer() {
+		t.Error("Access-Request reported as Status-Server")
+	}
+	if ok, err := radius.VerifyMessageAuthenticator(secret); err != nil || !ok {
+		t.Errorf("VerifyMessageAuthenticator got %v, %v want true", ok, err)
+	}
+	if ok, _ := radius.VerifyMessageAuthenticator([]byte("secret")); ok {
+		t.Error("VerifyMessageAuthenticator with the wrong secret: got true")
+	}
+	if err := radius.Validate(); err != nil {
+		t.Errorf("Validate: unexpected error: %v", err)
+	}
+	if err := radius.ValidateRequiredAttributes(); err != nil {
+		t.Errorf("ValidateRequiredAttributes: unexpected error: %v", err)
+	}
+}
+
+func TestNewStatusServerResponse(t *testing.T) {
+	secret := []byte("xyzzy5461")
+	req := decodeTestRADIUS(t, testRADIUSStatusServer)
+
+	// the Access-Accept of RFC5997 6.1
+	resp, err := NewStatusServerResponse(req, RADIUSCodeAccessAccept, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := resp.serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte{
+		0x02, 0xda, 0x00, 0x14, 0xef, 0x0d, 0x55, 0x2a, 0x4b, 0xf2, 0xd6, 0x93, 0xec, 0x2b, 0x6f, 0xe8,
+		0xb5, 0x41, 0x1d, 0x66,
+	}
+	if !bytes.Equal(data, want) {
+		t.Errorf("got %x want %x", data, want)
+	}
+
+	resp, err = NewStatusServerResponse(req, RADIUSCodeAccountingResponse, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.VerifyResponseAuthenticator(req.Authenticator, secret) {
+		t.Error("Accounting-Response authenticator invalid")
+	}
+
+	if _, err := NewStatusServerResponse(req, RADIUSCodeAccessReject, secret); err == nil {
+		t.Error("Access-Reject: expected error")
+	}
+	if _, err := NewStatusServerResponse(decodeTestRADIUS(t, testRADIUSAccessRequest), RADIUSCodeAccessAccept, secret); err == nil {
+		t.Error("Access-Request: expected error")
+	}
+}
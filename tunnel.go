@@ -0,0 +1,295 @@
+package radius
+
+import (
+	"fmt"
+)
+
+const radiusTagMaximum byte = 0x1f
+
+// Tag splits the RFC2868 tag from the value of a tagged attribute. Integer
+// attributes always carry the tag, followed by a 3 bytes value, and so does
+// Tunnel-Password, followed by the salt and the encrypted password (see
+// SaltedValue). String attributes carry it only if the first byte is within
+// the tag range.
+func (a RADIUSAttribute) Tag() (tag byte, value RADIUSAttributeValue, ok bool) {
+	if !a.Type.IsTagged() || len(a.Value) == 0 {
+		return 0, a.Value, false
+	}
+	if a.Type.ValueType() == AttrValueTypeInteger {
+		if len(a.Value) != 4 {
+			return 0, a.Value, false
+		}
+		return a.Value[0], a.Value[1:], true
+	}
+	if a.Type == RADIUSAttributeTypeTunnelPassword {
+		if len(a.Value) < 3 || a.Value[0] > radiusTagMaximum {
+			return 0, a.Value, false
+		}
+		return a.Value[0], a.Value[1:], true
+	}
+	if a.Value[0] > radiusTagMaximum {
+		return 0, a.Value, true
+	}
+	return a.Value[0], a.Value[1:], true
+}
+
+// SaltedValue splits the value of a Tunnel-Password into its tag, its 2
+// bytes salt and the encrypted password (RFC2868 3.5). It returns false if
+// the most significant bit of the salt, which must be set, is clear.
+func (a RADIUSAttribute) SaltedValue() (tag byte, salt []byte, value RADIUSAttributeValue, ok bool) {
+	if a.Type != RADIUSAttributeTypeTunnelPassword {
+		return 0, nil, a.Value, false
+	}
+	tag, value, ok = a.Tag()
+	if !ok || value[0]&0x80 == 0 {
+		return 0, nil, a.Value, false
+	}
+	return tag, value[:2], value[2:], true
+}
+
+// NewTaggedAttribute returns a tagged attribute of type t, the reverse of
+// Tag: the value of integer attributes is 3 bytes, and the one of
+// Tunnel-Password starts with the salt. The tag is always encoded, a tag of
+// 0 meaning the attribute is not grouped with other tunnel attributes.
+func NewTaggedAttribute(t RADIUSAttributeType, tag byte, value []byte) (RADIUSAttribute, error) {
+	if !t.IsTagged() {
+		return RADIUSAttribute{}, fmt.Errorf("RADIUS %s is not tagged", t)
+	}
+	if tag > radiusTagMaximum {
+		return RADIUSAttribute{}, fmt.Errorf("RADIUS %s tag %d exceeds %d", t, tag, radiusTagMaximum)
+	}
+	if t.ValueType() == AttrValueTypeInteger && len(value) != 3 {
+		return RADIUSAttribute{}, fmt.Errorf("RADIUS %s tagged integer length %d invalid", t, len(value))
+	}
+	if len(value)+1 > radiusMaximumAttributeValueSizeInBytes {
+		return RADIUSAttribute{}, fmt.Errorf("RADIUS %s length %d exceeds %d", t, len(value)+1, radiusMaximumAttributeValueSizeInBytes)
+	}
+	return newAttribute(t, append([]byte{tag}, value...)), nil
+}
+
+// AddTagged appends a tagged attribute, see NewTaggedAttribute.
+func (b *RADIUSBuilder) AddTagged(t RADIUSAttributeType, tag byte, value []byte) error {
+	attr, err := NewTaggedAttribute(t, tag, value)
+	if err != nil {
+		return err
+	}
+	b.attributes = append(b.attributes, attr)
+	return nil
+}
+
+// AddTaggedUint32 appends a tagged integer attribute such as Tunnel-Type. The
+// value must fit in 3 bytes.
+func (b *RADIUSBuilder) AddTaggedUint32(t RADIUSAttributeType, tag byte, n uint32) error {
+	if n > 0xffffff {
+		return fmt.Errorf("RADIUS %s tagged integer %d exceeds %d", t, n, 0xffffff)
+	}
+	return b.AddTagged(t, tag, uint32Value(n)[1:])
+}
+
+// taggedUint32 returns the tag and the 3 bytes integer value of a tagged attribute.
+func (a RADIUSAttribute) taggedUint32() (uint8, uint32, bool) {
+	tag, value, ok := a.Tag()
+	if !ok || len(value) != 3 {
+		return 0, 0, false
+	}
+	return tag, uint32(value[0])<<16 | uint32(value[1])<<8 | uint32(value[2]), true
+}
+
+// TunnelType represents the tunneling protocol of Tunnel-Type.
+type TunnelType uint32
+
+// constants that define TunnelType.
+const (
+	TunnelTypePPTP    TunnelType = 1  // RFC2868 3.1.  Point-to-Point Tunneling Protocol
+	TunnelTypeL2F     TunnelType = 2  // RFC2868 3.1.  Layer Two Forwarding
+	TunnelTypeL2TP    TunnelType = 3  // RFC2868 3.1.  Layer Two Tunneling Protocol
+	TunnelTypeATMP    TunnelType = 4  // RFC2868 3.1.  Ascend Tunnel Management Protocol
+	TunnelTypeVTP     TunnelType = 5  // RFC2868 3.1.  Virtual Tunneling Protocol
+	TunnelTypeAH      TunnelType = 6  // RFC2868 3.1.  IP Authentication Header in the Tunnel-mode
+	TunnelTypeIPIP    TunnelType = 7  // RFC2868 3.1.  IP-in-IP Encapsulation
+	TunnelTypeMinIPIP TunnelType = 8  // RFC2868 3.1.  Minimal IP-in-IP Encapsulation
+	TunnelTypeESP     TunnelType = 9  // RFC2868 3.1.  IP Encapsulating Security Payload in the Tunnel-mode
+	TunnelTypeGRE     TunnelType = 10 // RFC2868 3.1.  Generic Route Encapsulation
+	TunnelTypeDVS     TunnelType = 11 // RFC2868 3.1.  Bay Dial Virtual Services
+	TunnelTypeIPInIP  TunnelType = 12 // RFC2868 3.1.  IP-in-IP Tunneling
+	TunnelTypeVLAN    TunnelType = 13 // RFC3580 3.31. Virtual LANs
+)
+
+// String returns a string version of a TunnelType.
+func (t TunnelType) String() (s string) {
+	switch t {
+	case TunnelTypePPTP:
+		s = "PPTP"
+	case TunnelTypeL2F:
+		s = "L2F"
+	case TunnelTypeL2TP:
+		s = "L2TP"
+	case TunnelTypeATMP:
+		s = "ATMP"
+	case TunnelTypeVTP:
+		s = "VTP"
+	case TunnelTypeAH:
+		s = "AH"
+	case TunnelTypeIPIP:
+		s = "IP-IP"
+	case TunnelTypeMinIPIP:
+		s = "MIN-IP-IP"
+	case TunnelTypeESP:
+		s = "ESP"
+	case TunnelTypeGRE:
+		s = "GRE"
+	case TunnelTypeDVS:
+		s = "DVS"
+	case TunnelTypeIPInIP:
+		s = "IP-in-IP"
+	case TunnelTypeVLAN:
+		s = "VLAN"
+	default:
+		s = fmt.Sprintf("Unknown(%d)", t)
+	}
+	return
+}
+
+// TunnelType returns the tag and value of the first Tunnel-Type attribute.
+func (radius *RADIUS) TunnelType() (tag uint8, tt TunnelType, ok bool) {
+	for _, v := range radius.Attributes {
+		if v.Type != RADIUSAttributeTypeTunnelType {
+			continue
+		}
+		if tag, n, ok := v.taggedUint32(); ok {
+			return tag, TunnelType(n), true
+		}
+	}
+	return 0, 0, false
+}
+
+// TunnelMediumType represents the transport medium of Tunnel-Medium-Type,
+// taken from the IANA Address Family Numbers.
+type TunnelMediumType uint32
+
+// constants that define TunnelMediumType.
+const (
+	TunnelMediumTypeIPv4        TunnelMediumType = 1  // RFC2868 3.2.  IPv4 (IP version 4)
+	TunnelMediumTypeIPv6        TunnelMediumType = 2  // RFC2868 3.2.  IPv6 (IP version 6)
+	TunnelMediumTypeNSAP        TunnelMediumType = 3  // RFC2868 3.2.  NSAP
+	TunnelMediumTypeHDLC        TunnelMediumType = 4  // RFC2868 3.2.  HDLC (8-bit multidrop)
+	TunnelMediumTypeBBN1822     TunnelMediumType = 5  // RFC2868 3.2.  BBN 1822
+	TunnelMediumTypeIEEE802     TunnelMediumType = 6  // RFC2868 3.2.  802 (includes all 802 media plus Ethernet)
+	TunnelMediumTypeE163        TunnelMediumType = 7  // RFC2868 3.2.  E.163 (POTS)
+	TunnelMediumTypeE164        TunnelMediumType = 8  // RFC2868 3.2.  E.164 (SMDS, Frame Relay, ATM)
+	TunnelMediumTypeF69         TunnelMediumType = 9  // RFC2868 3.2.  F.69 (Telex)
+	TunnelMediumTypeX121        TunnelMediumType = 10 // RFC2868 3.2.  X.121 (X.25, Frame Relay)
+	TunnelMediumTypeIPX         TunnelMediumType = 11 // RFC2868 3.2.  IPX
+	TunnelMediumTypeAppletalk   TunnelMediumType = 12 // RFC2868 3.2.  Appletalk
+	TunnelMediumTypeDecnetIV    TunnelMediumType = 13 // RFC2868 3.2.  Decnet IV
+	TunnelMediumTypeBanyanVines TunnelMediumType = 14 // RFC2868 3.2.  Banyan Vines
+	TunnelMediumTypeE164NSAP    TunnelMediumType = 15 // RFC2868 3.2.  E.164 with NSAP format subaddress
+)
+
+// String returns a string version of a TunnelMediumType.
+func (t TunnelMediumType) String() (s string) {
+	switch t {
+	case TunnelMediumTypeIPv4:
+		s = "IPv4"
+	case TunnelMediumTypeIPv6:
+		s = "IPv6"
+	case TunnelMediumTypeNSAP:
+		s = "NSAP"
+	case TunnelMediumTypeHDLC:
+		s = "HDLC"
+	case TunnelMediumTypeBBN1822:
+		s = "BBN-1822"
+	case TunnelMediumTypeIEEE802:
+		s = "IEEE-802"
+	case TunnelMediumTypeE163:
+		s = "E.163"
+	case TunnelMediumTypeE164:
+		s = "E.164"
+	case TunnelMediumTypeF69:
+		s = "F.69"
+	case TunnelMediumTypeX121:
+		s = "X.121"
+	case TunnelMediumTypeIPX:
+		s = "IPX"
+	case TunnelMediumTypeAppletalk:
+		s = "Appletalk"
+	case TunnelMediumTypeDecnetIV:
+		s = "DecNet-IV"
+	case TunnelMediumTypeBanyanVines:
+		s = "Banyan-Vines"
+	case TunnelMediumTypeE164NSAP:
+		s = "E.164-NSAP"
+	default:
+		s = fmt.Sprintf("Unknown(%d)", t)
+	}
+	return
+}
+
+// TunnelMediumType returns the tag and value of the first Tunnel-Medium-Type attribute.
+func (radius *RADIUS) TunnelMediumType() (tag uint8, mt TunnelMediumType, ok bool) {
+	for _, v := range radius.Attributes {
+		if v.Type != RADIUSAttributeTypeTunnelMediumType {
+			continue
+		}
+		if tag, n, ok := v.taggedUint32(); ok {
+			return tag, TunnelMediumType(n), true
+		}
+	}
+	return 0, 0, false
+}
+
+// DynamicVLAN returns the VLAN assigned by the IEEE 802.1X dynamic VLAN
+// pattern (RFC3580 3.31): Tunnel-Type VLAN, Tunnel-Medium-Type IEEE-802 and
+// the VLAN ID in Tunnel-Private-Group-ID, all three sharing the same tag.
+func (radius *RADIUS) DynamicVLAN() (vlanID string, ok bool) {
+	groupIDs := radius.TunnelPrivateGroupIDs()
+	for _, tunnelType := range radius.Attributes {
+		if tunnelType.Type != RADIUSAttributeTypeTunnelType {
+			continue
+		}
+		tag, n, ok := tunnelType.taggedUint32()
+		if !ok || TunnelType(n) != TunnelTypeVLAN {
+			continue
+		}
+		if !radius.hasTunnelMediumType(tag, TunnelMediumTypeIEEE802) {
+			continue
+		}
+		if vlanID, ok := groupIDs[tag]; ok {
+			return vlanID, true
+		}
+	}
+	return "", false
+}
+
+// TunnelPrivateGroupIDs returns the Tunnel-Private-Group-ID of each tag, to
+// be correlated with the Tunnel-Type and Tunnel-Medium-Type of the same tag.
+// Untagged attributes are returned with tag 0, and only the first non-empty
+// value of each tag is kept.
+func (radius *RADIUS) TunnelPrivateGroupIDs() map[uint8]string {
+	groupIDs := make(map[uint8]string)
+	for _, v := range radius.Attributes {
+		if v.Type != RADIUSAttributeTypeTunnelPrivateGroupID {
+			continue
+		}
+		tag, value, ok := v.Tag()
+		if !ok || len(value) == 0 {
+			continue
+		}
+		if _, ok := groupIDs[tag]; !ok {
+			groupIDs[tag] = string(value)
+		}
+	}
+	return groupIDs
+}
+
+func (radius *RADIUS) hasTunnelMediumType(tag uint8, mediumType TunnelMediumType) bool {
+	for _, v := range radius.Attributes {
+		if v.Type != RADIUSAttributeTypeTunnelMediumType {
+			continue
+		}
+		if mediumTag, n, ok := v.taggedUint32(); ok && mediumTag == tag && TunnelMediumType(n) == mediumType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,215 @@
+package radius
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRADIUSAttributeTag(t *testing.T) {
+	tests := []struct {
+		desc  string
+		attr  RADIUSAttribute
+		tag   byte
+		value string
+		ok    bool
+	}{
+		{"Tunnel-Type", newAttribute(RADIUSAttributeTypeTunnelType, []byte{0x01, 0x00, 0x00, 0x0d}), 1, "\x00\x00\x0d", true},
+		{"Tunnel-Type untagged", newAttribute(RADIUSAttributeTypeTunnelType, []byte{0x00, 0x00, 0x00, 0x0d}), 0, "\x00\x00\x0d", true},
+		{"Tunnel-Type truncated", newAttribute(RADIUSAttributeTypeTunnelType, []byte{0x01, 0x0d}), 0, "\x01\x0d", false},
+		{"Tunnel-Private-Group-ID", newAttribute(RADIUSAttributeTypeTunnelPrivateGroupID, []byte("\x02100")), 2, "100", true},
+		{"Tunnel-Private-Group-ID untagged", newAttribute(RADIUSAttributeTypeTunnelPrivateGroupID, []byte("100")), 0, "100", true},
+		{"Tunnel-Password", newAttribute(RADIUSAttributeTypeTunnelPassword, []byte("\x01\x85\x6a\x3f")), 1, "\x85\x6a\x3f", true},
+		{"Tunnel-Password salt first", newAttribute(RADIUSAttributeTypeTunnelPassword, []byte("\x85\x6a\x3f")), 0, "\x85\x6a\x3f", false},
+		{"Tunnel-Password truncated", newAttribute(RADIUSAttributeTypeTunnelPassword, []byte("\x01\x85")), 0, "\x01\x85", false},
+		{"User-Name", newAttribute(RADIUSAttributeTypeUserName, []byte("\x01Admin")), 0, "\x01Admin", false},
+	}
+
+	for _, tt := range tests {
+		tag, value, ok := tt.attr.Tag()
+		if tag != tt.tag || string(value) != tt.value || ok != tt.ok {
+			t.Errorf("%s: got %d, %q, %v want %d, %q, %v", tt.desc, tag, value, ok, tt.tag, tt.value, tt.ok)
+		}
+	}
+}
+
+func TestRADIUSAttributeSaltedValue(t *testing.T) {
+	attr := newAttribute(RADIUSAttributeTypeTunnelPassword, []byte("\x02\x85\x6a\x3f\x10"))
+	tag, salt, value, ok := attr.SaltedValue()
+	if tag != 2 || string(salt) != "\x85\x6a" || string(value) != "\x3f\x10" || !ok {
+		t.Errorf("got %d, %x, %x, %v", tag, salt, []byte(value), ok)
+	}
+
+	for _, attr := range []RADIUSAttribute{
+		newAttribute(RADIUSAttributeTypeTunnelPassword, []byte("\x02\x05\x6a\x3f")),
+		newAttribute(RADIUSAttributeTypeTunnelPassword, []byte("\x02\x85")),
+		newAttribute(RADIUSAttributeTypeTunnelPrivateGroupID, []byte("\x02\x85\x6a\x3f")),
+	} {
+		if _, _, _, ok := attr.SaltedValue(); ok {
+			t.Errorf("%s %x: expected not ok", attr.Type, []byte(attr.Value))
+		}
+	}
+}
+
+func TestNewTaggedAttribute(t *testing.T) {
+	b := NewRADIUS(RADIUSCodeAccessAccept, 1)
+	if err := b.AddTaggedUint32(RADIUSAttributeTypeTunnelType, 1, uint32(TunnelTypeVLAN)); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.AddTaggedUint32(RADIUSAttributeTypeTunnelMediumType, 1, uint32(TunnelMediumTypeIEEE802)); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.AddTagged(RADIUSAttributeTypeTunnelPrivateGroupID, 1, []byte("100")); err != nil {
+		t.Fatal(err)
+	}
+	data, err := b.Build().serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	radius := decodeTestRADIUS(t, data)
+
+	if tag, tt, ok := radius.TunnelType(); tag != 1 || tt != TunnelTypeVLAN || !ok {
+		t.Errorf("TunnelType got %d, %s, %v", tag, tt, ok)
+	}
+	if vlanID, ok := radius.DynamicVLAN(); vlanID != "100" || !ok {
+		t.Errorf("DynamicVLAN got %q, %v", vlanID, ok)
+	}
+
+	// a tag of 0 is encoded so that it is not mistaken for the value
+	attr, err := NewTaggedAttribute(RADIUSAttributeTypeTunnelPrivateGroupID, 0, []byte("\x01"))
+	if err != nil || string(attr.Value) != "\x00\x01" || attr.Length != 4 {
+		t.Errorf("tag 0 got %+v, %v", attr, err)
+	}
+
+	tests := []struct {
+		desc  string
+		t     RADIUSAttributeType
+		tag   byte
+		value []byte
+	}{
+		{"untagged type", RADIUSAttributeTypeUserName, 1, []byte("Admin")},
+		{"tag out of range", RADIUSAttributeTypeTunnelPrivateGroupID, 0x20, []byte("100")},
+		{"integer length", RADIUSAttributeTypeTunnelType, 1, []byte{0x00, 0x00, 0x00, 0x0d}},
+		{"too long", RADIUSAttributeTypeTunnelPrivateGroupID, 1, make([]byte, 253)},
+	}
+	for _, tt := range tests {
+		if _, err := NewTaggedAttribute(tt.t, tt.tag, tt.value); err == nil {
+			t.Errorf("%s: expected error", tt.desc)
+		}
+	}
+	if err := b.AddTaggedUint32(RADIUSAttributeTypeTunnelType, 1, 0x1000000); err == nil {
+		t.Error("integer exceeding 3 bytes: expected error")
+	}
+}
+
+func TestRADIUSTunnelType(t *testing.T) {
+	radius := &RADIUS{Attributes: []RADIUSAttribute{
+		newAttribute(RADIUSAttributeTypeTunnelType, []byte{0x01, 0x00, 0x00, 0x0d}),
+	}}
+	tag, tt, ok := radius.TunnelType()
+	if tag != 1 || tt != TunnelTypeVLAN || !ok {
+		t.Errorf("got %d, %v, %v want 1, VLAN, true", tag, tt, ok)
+	}
+	if s := tt.String(); s != "VLAN" {
+		t.Errorf("got %q want %q", s, "VLAN")
+	}
+	if s := TunnelType(99).String(); s != "Unknown(99)" {
+		t.Errorf("got %q want %q", s, "Unknown(99)")
+	}
+
+	if _, _, ok := (&RADIUS{}).TunnelType(); ok {
+		t.Error("expected no Tunnel-Type")
+	}
+}
+
+func TestRADIUSDynamicVLAN(t *testing.T) {
+	tests := []struct {
+		desc       string
+		attributes []RADIUSAttribute
+		vlanID     string
+		ok         bool
+	}{
+		{
+			desc: "Untagged",
+			attributes: []RADIUSAttribute{
+				newAttribute(RADIUSAttributeTypeTunnelType, []byte{0x00, 0x00, 0x00, 0x0d}),
+				newAttribute(RADIUSAttributeTypeTunnelMediumType, []byte{0x00, 0x00, 0x00, 0x06}),
+				newAttribute(RADIUSAttributeTypeTunnelPrivateGroupID, []byte("100")),
+			},
+			vlanID: "100",
+			ok:     true,
+		},
+		{
+			desc: "Tagged",
+			attributes: []RADIUSAttribute{
+				newAttribute(RADIUSAttributeTypeTunnelType, []byte{0x01, 0x00, 0x00, 0x03}),
+				newAttribute(RADIUSAttributeTypeTunnelType, []byte{0x02, 0x00, 0x00, 0x0d}),
+				newAttribute(RADIUSAttributeTypeTunnelMediumType, []byte{0x01, 0x00, 0x00, 0x01}),
+				newAttribute(RADIUSAttributeTypeTunnelMediumType, []byte{0x02, 0x00, 0x00, 0x06}),
+				newAttribute(RADIUSAttributeTypeTunnelPrivateGroupID, []byte("\x01l2tp")),
+				newAttribute(RADIUSAttributeTypeTunnelPrivateGroupID, []byte("\x02200")),
+			},
+			vlanID: "200",
+			ok:     true,
+		},
+		{
+			desc: "TagMismatch",
+			attributes: []RADIUSAttribute{
+				newAttribute(RADIUSAttributeTypeTunnelType, []byte{0x01, 0x00, 0x00, 0x0d}),
+				newAttribute(RADIUSAttributeTypeTunnelMediumType, []byte{0x02, 0x00, 0x00, 0x06}),
+				newAttribute(RADIUSAttributeTypeTunnelPrivateGroupID, []byte("\x01100")),
+			},
+		},
+		{
+			desc: "MissingGroupID",
+			attributes: []RADIUSAttribute{
+				newAttribute(RADIUSAttributeTypeTunnelType, []byte{0x00, 0x00, 0x00, 0x0d}),
+				newAttribute(RADIUSAttributeTypeTunnelMediumType, []byte{0x00, 0x00, 0x00, 0x06}),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		radius := &RADIUS{Attributes: tt.attributes}
+		vlanID, ok := radius.DynamicVLAN()
+		if vlanID != tt.vlanID || ok != tt.ok {
+			t.Errorf("%s: got %q, %v want %q, %v", tt.desc, vlanID, ok, tt.vlanID, tt.ok)
+		}
+	}
+}
+
+func TestRADIUSTunnelPrivateGroupIDs(t *testing.T) {
+	radius := &RADIUS{Attributes: []RADIUSAttribute{
+		newAttribute(RADIUSAttributeTypeTunnelPrivateGroupID, []byte("\x01l2tp")),
+		newAttribute(RADIUSAttributeTypeTunnelPrivateGroupID, []byte("\x02200")),
+		newAttribute(RADIUSAttributeTypeTunnelPrivateGroupID, []byte("\x02300")),
+		newAttribute(RADIUSAttributeTypeTunnelPrivateGroupID, []byte("\x03")),
+		newAttribute(RADIUSAttributeTypeTunnelPrivateGroupID, []byte("100")),
+		newAttribute(RADIUSAttributeTypeUserName, []byte("\x04Admin")),
+	}}
+
+	want := map[uint8]string{0: "100", 1: "l2tp", 2: "200"}
+	if got := radius.TunnelPrivateGroupIDs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func TestRADIUSTunnelMediumType(t *testing.T) {
+	radius := &RADIUS{Attributes: []RADIUSAttribute{
+		newAttribute(RADIUSAttributeTypeTunnelMediumType, []byte{0x03, 0x00, 0x00, 0x06}),
+		newAttribute(RADIUSAttributeTypeTunnelPrivateGroupID, []byte("\x03100")),
+	}}
+	tag, mt, ok := radius.TunnelMediumType()
+	if tag != 3 || mt != TunnelMediumTypeIEEE802 || !ok {
+		t.Errorf("got %d, %v, %v want 3, IEEE-802, true", tag, mt, ok)
+	}
+	if s := mt.String(); s != "IEEE-802" {
+		t.Errorf("got %q want %q", s, "IEEE-802")
+	}
+
+	if v, err := radius.Attributes[0].DecodedValue(); err != nil || v != TunnelMediumTypeIEEE802 {
+		t.Errorf("DecodedValue() got %#v, %v want %#v", v, err, TunnelMediumTypeIEEE802)
+	}
+	if v, err := radius.Attributes[1].DecodedValue(); err != nil || v != "100" {
+		t.Errorf("DecodedValue() got %#v, %v want %q", v, err, "100")
+	}
+}
@@ -0,0 +1,347 @@
+package radius
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ValidateOrdering checks the attribute ordering and placement constraints of
+// the RFCs:
+//
+//   - at most one Message-Authenticator, which must be 16 bytes (RFC2869 5.14)
+//   - a Message-Authenticator whenever EAP-Message is present (RFC3579 3.3)
+//   - multiple EAP-Message attributes are consecutive (RFC3579 3.1)
+//
+// Reply-Message and Proxy-State order is significant (RFC2865 5.18, 5.33),
+// but any order within a single packet is valid; the decoder and SerializeTo
+// preserve it as it is.
+func (radius *RADIUS) ValidateOrdering() error {
+	messageAuthenticators := 0
+	eapMessages := 0
+	eapEnd := -1
+	for i, v := range radius.Attributes {
+		switch v.Type {
+		case RADIUSAttributeTypeMessageAuthenticator:
+			messageAuthenticators++
+			if len(v.Value) != 16 {
+				return fmt.Errorf("RADIUS %s length %d invalid", v.Type, len(v.Value))
+			}
+		case RADIUSAttributeTypeEAPMessage:
+			if eapMessages > 0 && eapEnd != i-1 {
+				return fmt.Errorf("RADIUS %s attribute %d is not consecutive", v.Type, i)
+			}
+			eapMessages++
+			eapEnd = i
+		}
+	}
+
+	if messageAuthenticators > 1 {
+		return fmt.Errorf("RADIUS %d %s attributes", messageAuthenticators, RADIUSAttributeTypeMessageAuthenticator)
+	}
+	if eapMessages > 0 && messageAuthenticators == 0 {
+		return fmt.Errorf("RADIUS %s without %s", RADIUSAttributeTypeEAPMessage, RADIUSAttributeTypeMessageAuthenticator)
+	}
+	return nil
+}
+
+// HasMessageAuthenticator reports whether the packet carries a
+// Message-Authenticator.
+func (radius *RADIUS) HasMessageAuthenticator() bool {
+	return radius.HasAttribute(RADIUSAttributeTypeMessageAuthenticator)
+}
+
+// RequireMessageAuthenticator returns an error unless the packet has a
+// Message-Authenticator that is present and 16 bytes long. Servers hardened
+// against forged Access-Request and response packets (BlastRADIUS,
+// CVE-2024-3596) require it on every packet, beyond the EAP-Message case of
+// the RFCs. It does not check the HMAC-MD5 itself: use
+// VerifyMessageAuthenticator, or VerifyResponseAuthenticatorStrict for
+// responses, with the shared secret.
+func (radius *RADIUS) RequireMessageAuthenticator() error {
+	attr, ok := radius.GetAttribute(RADIUSAttributeTypeMessageAuthenticator)
+	if !ok {
+		return fmt.Errorf("RADIUS %s required", RADIUSAttributeTypeMessageAuthenticator)
+	}
+	if len(attr.Value) != 16 {
+		return fmt.Errorf("RADIUS %s length %d invalid", attr.Type, len(attr.Value))
+	}
+	return nil
+}
+
+// ValidateUserName checks the User-Name attribute: it is required in an
+// Access-Request (RFC2865 5.1), and must be a non-empty UTF-8 string of at
+// most 253 bytes (RFC2865 5.1, RFC8044 3.5).
+func (radius *RADIUS) ValidateUserName() error {
+	attr, ok := radius.GetAttribute(RADIUSAttributeTypeUserName)
+	if !ok {
+		if radius.Code == RADIUSCodeAccessRequest {
+			return fmt.Errorf("RADIUS %s missing in %s", RADIUSAttributeTypeUserName, radius.Code)
+		}
+		return nil
+	}
+	switch {
+	case len(attr.Value) == 0:
+		return fmt.Errorf("RADIUS %s empty", attr.Type)
+	case len(attr.Value) > radiusMaximumAttributeValueSizeInBytes:
+		return fmt.Errorf("RADIUS %s length %d exceeds %d", attr.Type, len(attr.Value), radiusMaximumAttributeValueSizeInBytes)
+	case !utf8.Valid(attr.Value):
+		return fmt.Errorf("RADIUS %s %q is not valid UTF-8", attr.Type, attr.Value)
+	}
+	return nil
+}
+
+// attributeApplicability lists the packet codes an attribute type may appear
+// in, for the attribute types restricted to some codes. Accounting usage and
+// status attributes only appear in Accounting-Request (RFC2866 5.13,
+// RFC2869 5.19).
+var attributeApplicability = map[RADIUSAttributeType][]RADIUSCode{
+	RADIUSAttributeTypeAcctStatusType:      {RADIUSCodeAccountingRequest},
+	RADIUSAttributeTypeAcctDelayTime:       {RADIUSCodeAccountingRequest},
+	RADIUSAttributeTypeAcctInputOctets:     {RADIUSCodeAccountingRequest},
+	RADIUSAttributeTypeAcctOutputOctets:    {RADIUSCodeAccountingRequest},
+	RADIUSAttributeTypeAcctAuthentic:       {RADIUSCodeAccountingRequest},
+	RADIUSAttributeTypeAcctSessionTime:     {RADIUSCodeAccountingRequest},
+	RADIUSAttributeTypeAcctInputPackets:    {RADIUSCodeAccountingRequest},
+	RADIUSAttributeTypeAcctOutputPackets:   {RADIUSCodeAccountingRequest},
+	RADIUSAttributeTypeAcctTerminateCause:  {RADIUSCodeAccountingRequest},
+	RADIUSAttributeTypeAcctLinkCount:       {RADIUSCodeAccountingRequest},
+	RADIUSAttributeTypeAcctInputGigawords:  {RADIUSCodeAccountingRequest},
+	RADIUSAttributeTypeAcctOutputGigawords: {RADIUSCodeAccountingRequest},
+}
+
+// singleInstanceAttributes lists the attribute types that appear at most
+// once in a packet, the 0-1 entries of the tables of RFC2865 5.44, RFC2866
+// 5.13 and RFC2869 5.19.
+var singleInstanceAttributes = map[RADIUSAttributeType]bool{
+	RADIUSAttributeTypeUserName:             true,
+	RADIUSAttributeTypeUserPassword:         true,
+	RADIUSAttributeTypeCHAPPassword:         true,
+	RADIUSAttributeTypeNASIPAddress:         true,
+	RADIUSAttributeTypeNASPort:              true,
+	RADIUSAttributeTypeServiceType:          true,
+	RADIUSAttributeTypeFramedProtocol:       true,
+	RADIUSAttributeTypeFramedIPAddress:      true,
+	RADIUSAttributeTypeFramedIPNetmask:      true,
+	RADIUSAttributeTypeFramedRouting:        true,
+	RADIUSAttributeTypeFramedMTU:            true,
+	RADIUSAttributeTypeLoginService:         true,
+	RADIUSAttributeTypeLoginTCPPort:         true,
+	RADIUSAttributeTypeCallbackNumber:       true,
+	RADIUSAttributeTypeCallbackId:           true,
+	RADIUSAttributeTypeFramedIPXNetwork:     true,
+	RADIUSAttributeTypeState:                true,
+	RADIUSAttributeTypeSessionTimeout:       true,
+	RADIUSAttributeTypeIdleTimeout:          true,
+	RADIUSAttributeTypeTerminationAction:    true,
+	RADIUSAttributeTypeCalledStationId:      true,
+	RADIUSAttributeTypeCallingStationId:     true,
+	RADIUSAttributeTypeNASIdentifier:        true,
+	RADIUSAttributeTypeLoginLATService:      true,
+	RADIUSAttributeTypeLoginLATNode:         true,
+	RADIUSAttributeTypeLoginLATGroup:        true,
+	RADIUSAttributeTypeFramedAppleTalkLink:  true,
+	RADIUSAttributeTypeCHAPChallenge:        true,
+	RADIUSAttributeTypeNASPortType:          true,
+	RADIUSAttributeTypePortLimit:            true,
+	RADIUSAttributeTypeLoginLATPort:         true,
+	RADIUSAttributeTypeAcctStatusType:       true,
+	RADIUSAttributeTypeAcctDelayTime:        true,
+	RADIUSAttributeTypeAcctInputOctets:      true,
+	RADIUSAttributeTypeAcctOutputOctets:     true,
+	RADIUSAttributeTypeAcctSessionId:        true,
+	RADIUSAttributeTypeAcctAuthentic:        true,
+	RADIUSAttributeTypeAcctSessionTime:      true,
+	RADIUSAttributeTypeAcctInputPackets:     true,
+	RADIUSAttributeTypeAcctOutputPackets:    true,
+	RADIUSAttributeTypeAcctTerminateCause:   true,
+	RADIUSAttributeTypeAcctMultiSessionId:   true,
+	RADIUSAttributeTypeAcctLinkCount:        true,
+	RADIUSAttributeTypeAcctInputGigawords:   true,
+	RADIUSAttributeTypeAcctOutputGigawords:  true,
+	RADIUSAttributeTypeEventTimestamp:       true,
+	RADIUSAttributeTypeMessageAuthenticator: true,
+	RADIUSAttributeTypeAcctInterimInterval:  true,
+	RADIUSAttributeTypeNASPortId:            true,
+}
+
+// duplicateWarnings returns a warning for each single-instance attribute
+// type appearing more than once, see singleInstanceAttributes.
+func (radius *RADIUS) duplicateWarnings() []string {
+	var warnings []string
+	var counts [256]int
+	for _, v := range radius.Attributes {
+		if !singleInstanceAttributes[v.Type] {
+			continue
+		}
+		counts[v.Type]++
+		if counts[v.Type] == 2 {
+			warnings = append(warnings, fmt.Sprintf("RADIUS %s appears more than once", v.Type))
+		}
+	}
+	return warnings
+}
+
+// ValidateApplicability checks that each attribute is allowed in the packet
+// code, e.g. that Acct-Status-Type does not leak into an Access-Request.
+func (radius *RADIUS) ValidateApplicability() error {
+	for _, v := range radius.Attributes {
+		codes, ok := attributeApplicability[v.Type]
+		if !ok {
+			continue
+		}
+		allowed := false
+		for _, code := range codes {
+			if code == radius.Code {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return fmt.Errorf("RADIUS %s not allowed in %s", v.Type, radius.Code)
+		}
+	}
+	return nil
+}
+
+// radiusNASIdentification are the attributes identifying the NAS, one of
+// which is required in requests (RFC2865 4.1, RFC2866 4.1, RFC3162 2.1,
+// RFC5176 3.).
+var radiusNASIdentification = []RADIUSAttributeType{
+	RADIUSAttributeTypeNASIPAddress,
+	RADIUSAttributeTypeNASIdentifier,
+	RADIUSAttributeTypeNASIPv6Address,
+}
+
+// radiusSessionIdentification are the attributes identifying the session of
+// a Disconnect-Request or CoA-Request (RFC5176 3.).
+var radiusSessionIdentification = []RADIUSAttributeType{
+	RADIUSAttributeTypeUserName,
+	RADIUSAttributeTypeNASPort,
+	RADIUSAttributeTypeFramedIPAddress,
+	RADIUSAttributeTypeCalledStationId,
+	RADIUSAttributeTypeCallingStationId,
+	RADIUSAttributeTypeAcctSessionId,
+	RADIUSAttributeTypeAcctMultiSessionId,
+	RADIUSAttributeTypeNASPortId,
+	RADIUSAttributeTypeChargeableUserIdentity,
+	RADIUSAttributeTypeFramedInterfaceId,
+	RADIUSAttributeTypeFramedIPv6Prefix,
+}
+
+// requiredAttributes lists the attributes required in each packet code. Each
+// entry is satisfied by any one of its attribute types.
+var requiredAttributes = map[RADIUSCode][][]RADIUSAttributeType{
+	RADIUSCodeAccessRequest: {
+		radiusNASIdentification,
+		// RFC2865 4.1, RFC3579 3.1
+		{RADIUSAttributeTypeUserName, RADIUSAttributeTypeEAPMessage},
+		{RADIUSAttributeTypeUserPassword, RADIUSAttributeTypeCHAPPassword, RADIUSAttributeTypeState, RADIUSAttributeTypeEAPMessage},
+	},
+	RADIUSCodeAccountingRequest: {
+		radiusNASIdentification,
+		// RFC2866 5.1, 5.5
+		{RADIUSAttributeTypeAcctStatusType},
+		{RADIUSAttributeTypeAcctSessionId},
+	},
+	RADIUSCodeStatusServer: {
+		// RFC5997 3.
+		{RADIUSAttributeTypeMessageAuthenticator},
+	},
+	RADIUSCodeDisconnectRequest: {
+		radiusNASIdentification,
+		radiusSessionIdentification,
+	},
+	RADIUSCodeCoARequest: {
+		radiusNASIdentification,
+		radiusSessionIdentification,
+	},
+}
+
+// ValidateRequiredAttributes checks that the packet carries the attributes
+// the RFCs require for its code, e.g. Acct-Status-Type and Acct-Session-Id
+// in an Accounting-Request, and one of User-Password, CHAP-Password, State or
+// EAP-Message in an Access-Request. The error names the missing attributes.
+func (radius *RADIUS) ValidateRequiredAttributes() error {
+	for _, types := range requiredAttributes[radius.Code] {
+		found := false
+		for _, t := range types {
+			if radius.HasAttribute(t) {
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+		if len(types) == 1 {
+			return fmt.Errorf("RADIUS %s missing in %s", types[0], radius.Code)
+		}
+		names := make([]string, len(types))
+		for i, t := range types {
+			names[i] = t.String()
+		}
+		return fmt.Errorf("RADIUS %s requires one of %s", radius.Code, strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// RADIUSValidationErrors lists every problem found by Validate, so that
+// callers can log them all.
+type RADIUSValidationErrors []error
+
+// Error returns the problems separated by "; ".
+func (e RADIUSValidationErrors) Error() string {
+	s := make([]string, len(e))
+	for i, err := range e {
+		s[i] = err.Error()
+	}
+	return strings.Join(s, "; ")
+}
+
+// validateStructure checks the lengths of the packet: the Length within 20
+// and 4096 (RFC2865 3) and equal to 20 plus the attribute Lengths, and each
+// attribute Length equal to the length of its value plus 2.
+func (radius *RADIUS) validateStructure() []error {
+	var errs []error
+	n := int(radius.Length)
+	if n < radiusMinimumRecordSizeInBytes || n > radiusMaximumRecordSizeInBytes {
+		errs = append(errs, fmt.Errorf("RADIUS Length %d out of range %d-%d", n, radiusMinimumRecordSizeInBytes, radiusMaximumRecordSizeInBytes))
+	}
+	want := radiusMinimumRecordSizeInBytes
+	for i, v := range radius.Attributes {
+		if int(v.Length) != len(v.Value)+2 {
+			errs = append(errs, fmt.Errorf("RADIUS attribute %d %s Length %d, want %d", i, v.Type, v.Length, len(v.Value)+2))
+		}
+		want += int(v.Length)
+	}
+	if n != want {
+		errs = append(errs, fmt.Errorf("RADIUS Length %d, want %d from the attributes", n, want))
+	}
+	return errs
+}
+
+// Validate checks the packet for structural consistency and conformance,
+// e.g. before forwarding or replaying it: the lengths of the packet and its
+// attributes, ValidateOrdering, ValidateApplicability and the range of
+// Framed-MTU (ValidateFramedMTU). It returns a RADIUSValidationErrors listing
+// every problem found. Missing attributes which the code requires are left to
+// ValidateRequiredAttributes, since many NASes omit them in practice.
+func (radius *RADIUS) Validate() error {
+	errs := radius.validateStructure()
+	if err := radius.ValidateOrdering(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := radius.ValidateApplicability(); err != nil {
+		errs = append(errs, err)
+	}
+	if mtu, ok := radius.uint32Attribute(RADIUSAttributeTypeFramedMTU); ok {
+		if err := ValidateFramedMTU(mtu); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return RADIUSValidationErrors(errs)
+	}
+	return nil
+}
@@ -0,0 +1,273 @@
+package radius
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRADIUSValidateOrdering(t *testing.T) {
+	messageAuthenticator := newAttribute(RADIUSAttributeTypeMessageAuthenticator, make([]byte, 16))
+	eap := newAttribute(RADIUSAttributeTypeEAPMessage, []byte{0x02, 0x01, 0x00, 0x05, 0x01})
+	userName := newAttribute(RADIUSAttributeTypeUserName, []byte("Admin"))
+
+	tests := []struct {
+		desc       string
+		attributes []RADIUSAttribute
+		wantErr    bool
+	}{
+		{"Empty", nil, false},
+		{"EAP", []RADIUSAttribute{userName, eap, eap, messageAuthenticator}, false},
+		{"EAPWithoutMessageAuthenticator", []RADIUSAttribute{userName, eap}, true},
+		{"EAPNotConsecutive", []RADIUSAttribute{eap, userName, eap, messageAuthenticator}, true},
+		{"DuplicateMessageAuthenticator", []RADIUSAttribute{messageAuthenticator, messageAuthenticator}, true},
+		{"ShortMessageAuthenticator", []RADIUSAttribute{newAttribute(RADIUSAttributeTypeMessageAuthenticator, make([]byte, 4))}, true},
+	}
+
+	for _, tt := range tests {
+		radius := &RADIUS{Attributes: tt.attributes}
+		err := radius.ValidateOrdering()
+		if tt.wantErr && err == nil {
+			t.Errorf("%s: expected error", tt.desc)
+		} else if !tt.wantErr && err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.desc, err)
+		}
+	}
+
+	if err := decodeTestRADIUS(t, testRADIUSAccessRequest).ValidateOrdering(); err != nil {
+		t.Errorf("AccessRequest: unexpected error: %v", err)
+	}
+}
+
+func TestRADIUSRequireMessageAuthenticator(t *testing.T) {
+	tests := []struct {
+		desc       string
+		attributes []RADIUSAttribute
+		wantErr    bool
+	}{
+		{"Present", []RADIUSAttribute{newAttribute(RADIUSAttributeTypeMessageAuthenticator, make([]byte, 16))}, false},
+		{"Absent", []RADIUSAttribute{newAttribute(RADIUSAttributeTypeUserName, []byte("Admin"))}, true},
+		{"Short", []RADIUSAttribute{newAttribute(RADIUSAttributeTypeMessageAuthenticator, make([]byte, 4))}, true},
+	}
+
+	for _, tt := range tests {
+		radius := &RADIUS{Attributes: tt.attributes}
+		err := radius.RequireMessageAuthenticator()
+		if tt.wantErr && err == nil {
+			t.Errorf("%s: expected error", tt.desc)
+		} else if !tt.wantErr && err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.desc, err)
+		}
+	}
+}
+
+func TestRADIUSValidateUserName(t *testing.T) {
+	tests := []struct {
+		desc    string
+		code    RADIUSCode
+		value   []byte
+		wantErr bool
+	}{
+		{"Valid", RADIUSCodeAccessRequest, []byte("Admin"), false},
+		{"UTF8", RADIUSCodeAccessRequest, []byte("ユーザー@example.jp"), false},
+		{"Empty", RADIUSCodeAccessRequest, []byte{}, true},
+		{"InvalidUTF8", RADIUSCodeAccessRequest, []byte{'A', 0xff, 0xfe}, true},
+		{"Overlong", RADIUSCodeAccessRequest, bytes.Repeat([]byte("a"), 254), true},
+		{"Missing", RADIUSCodeAccessRequest, nil, true},
+		{"MissingAccounting", RADIUSCodeAccountingRequest, nil, false},
+	}
+
+	for _, tt := range tests {
+		radius := &RADIUS{Code: tt.code}
+		if tt.value != nil {
+			radius.Attributes = []RADIUSAttribute{newAttribute(RADIUSAttributeTypeUserName, tt.value)}
+		}
+		err := radius.ValidateUserName()
+		if tt.wantErr && err == nil {
+			t.Errorf("%s: expected error", tt.desc)
+		} else if !tt.wantErr && err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.desc, err)
+		}
+	}
+}
+
+func TestRADIUSValidate(t *testing.T) {
+	status := newAttribute(RADIUSAttributeTypeAcctStatusType, uint32Value(uint32(AcctStatusTypeStart)))
+	eap := newAttribute(RADIUSAttributeTypeEAPMessage, []byte{0x02, 0x01, 0x00, 0x05, 0x01})
+
+	tests := []struct {
+		desc       string
+		code       RADIUSCode
+		attributes []RADIUSAttribute
+		wantErr    bool
+	}{
+		{"AccountingRequest", RADIUSCodeAccountingRequest, []RADIUSAttribute{status}, false},
+		{"AcctStatusTypeInAccessRequest", RADIUSCodeAccessRequest, []RADIUSAttribute{status}, true},
+		{"AcctStatusTypeInAccessAccept", RADIUSCodeAccessAccept, []RADIUSAttribute{status}, true},
+		{"AcctInputOctetsInAccessRequest", RADIUSCodeAccessRequest, []RADIUSAttribute{newAttribute(RADIUSAttributeTypeAcctInputOctets, uint32Value(0))}, true},
+		{"AcctSessionIdInAccessRequest", RADIUSCodeAccessRequest, []RADIUSAttribute{newAttribute(RADIUSAttributeTypeAcctSessionId, []byte("0001"))}, false},
+		{"Ordering", RADIUSCodeAccessRequest, []RADIUSAttribute{eap}, true},
+		{"FramedMTU", RADIUSCodeAccessAccept, []RADIUSAttribute{newAttribute(RADIUSAttributeTypeFramedMTU, uint32Value(1500))}, false},
+		{"FramedMTUTooSmall", RADIUSCodeAccessAccept, []RADIUSAttribute{newAttribute(RADIUSAttributeTypeFramedMTU, uint32Value(0))}, true},
+		{"FramedMTUTooLarge", RADIUSCodeAccessAccept, []RADIUSAttribute{newAttribute(RADIUSAttributeTypeFramedMTU, uint32Value(70000))}, true},
+	}
+
+	for _, tt := range tests {
+		radius := NewRADIUS(tt.code, 0x01).Build()
+		radius.Attributes = tt.attributes
+		n, _ := radius.Len()
+		radius.Length = RADIUSLength(n)
+		err := radius.Validate()
+		if tt.wantErr && err == nil {
+			t.Errorf("%s: expected error", tt.desc)
+		} else if !tt.wantErr && err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.desc, err)
+		}
+	}
+
+	if err := decodeTestRADIUS(t, testRADIUSAccessRequest).Validate(); err != nil {
+		t.Errorf("AccessRequest: unexpected error: %v", err)
+	}
+}
+
+func TestRADIUSValidateStructure(t *testing.T) {
+	userName := newAttribute(RADIUSAttributeTypeUserName, []byte("Admin"))
+	shortLength := userName
+	shortLength.Length = 6
+	status := newAttribute(RADIUSAttributeTypeAcctStatusType, uint32Value(uint32(AcctStatusTypeStart)))
+
+	tests := []struct {
+		desc       string
+		length     RADIUSLength
+		attributes []RADIUSAttribute
+		want       []string
+	}{
+		{"Valid", 27, []RADIUSAttribute{userName}, nil},
+		{"PacketLength", 28, []RADIUSAttribute{userName}, []string{
+			"RADIUS Length 28, want 27 from the attributes",
+		}},
+		{"TooShort", 19, nil, []string{
+			"RADIUS Length 19 out of range 20-4096",
+			"RADIUS Length 19, want 20 from the attributes",
+		}},
+		{"AttributeLength", 26, []RADIUSAttribute{shortLength}, []string{
+			"RADIUS attribute 0 User-Name Length 6, want 7",
+		}},
+		{"All", 30, []RADIUSAttribute{shortLength, status}, []string{
+			"RADIUS attribute 0 User-Name Length 6, want 7",
+			"RADIUS Length 30, want 32 from the attributes",
+			"RADIUS Acct-Status-Type not allowed in Access-Request",
+		}},
+	}
+
+	for _, tt := range tests {
+		radius := &RADIUS{Code: RADIUSCodeAccessRequest, Length: tt.length, Attributes: tt.attributes}
+		err := radius.Validate()
+		if tt.want == nil {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tt.desc, err)
+			}
+			continue
+		}
+		errs, ok := err.(RADIUSValidationErrors)
+		if !ok {
+			t.Errorf("%s: got %#v want RADIUSValidationErrors", tt.desc, err)
+			continue
+		}
+		var got []string
+		for _, e := range errs {
+			got = append(got, e.Error())
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %q want %q", tt.desc, got, tt.want)
+		}
+		if s := err.Error(); s != strings.Join(tt.want, "; ") {
+			t.Errorf("%s: Error() got %q", tt.desc, s)
+		}
+	}
+
+	radius := NewRADIUS(RADIUSCodeAccessAccept, 0x01).Build()
+	for i := 0; i < 17; i++ {
+		radius.Attributes = append(radius.Attributes, newAttribute(RADIUSAttributeTypeClass, make([]byte, 253)))
+	}
+	n, _ := radius.Len()
+	radius.Length = RADIUSLength(n)
+	if err := radius.Validate(); err == nil || !strings.Contains(err.Error(), "out of range 20-4096") {
+		t.Errorf("4355 bytes: got %v", err)
+	}
+}
+
+func TestRADIUSValidateRequiredAttributes(t *testing.T) {
+	nasID := newAttribute(RADIUSAttributeTypeNASIdentifier, []byte("nas01"))
+	userName := newAttribute(RADIUSAttributeTypeUserName, []byte("Admin"))
+	password := newAttribute(RADIUSAttributeTypeUserPassword, make([]byte, 16))
+	eap := newAttribute(RADIUSAttributeTypeEAPMessage, []byte{0x02, 0x01, 0x00, 0x05, 0x01})
+	status := newAttribute(RADIUSAttributeTypeAcctStatusType, uint32Value(uint32(AcctStatusTypeStart)))
+	sessionID := newAttribute(RADIUSAttributeTypeAcctSessionId, []byte("0001"))
+
+	tests := []struct {
+		desc       string
+		code       RADIUSCode
+		attributes []RADIUSAttribute
+		wantErr    string
+	}{
+		{"AccessRequest", RADIUSCodeAccessRequest, []RADIUSAttribute{userName, password, nasID}, ""},
+		{"AccessRequestEAP", RADIUSCodeAccessRequest, []RADIUSAttribute{eap, nasID}, ""},
+		{"AccessRequestNoNAS", RADIUSCodeAccessRequest, []RADIUSAttribute{userName, password},
+			"RADIUS Access-Request requires one of NAS-IP-Address, NAS-Identifier, NAS-IPv6-Address"},
+		{"AccessRequestNoPassword", RADIUSCodeAccessRequest, []RADIUSAttribute{userName, nasID},
+			"RADIUS Access-Request requires one of User-Password, CHAP-Password, State, EAP-Message"},
+		{"AccountingRequest", RADIUSCodeAccountingRequest, []RADIUSAttribute{status, sessionID, nasID}, ""},
+		{"AccountingRequestNoSessionID", RADIUSCodeAccountingRequest, []RADIUSAttribute{status, nasID},
+			"RADIUS Acct-Session-Id missing in Accounting-Request"},
+		{"StatusServer", RADIUSCodeStatusServer, []RADIUSAttribute{nasID},
+			"RADIUS Message-Authenticator missing in Status-Server"},
+		{"DisconnectRequest", RADIUSCodeDisconnectRequest, []RADIUSAttribute{nasID, sessionID}, ""},
+		{"CoARequestNoSession", RADIUSCodeCoARequest, []RADIUSAttribute{nasID},
+			"RADIUS CoA-Request requires one of User-Name, NAS-Port, Framed-IP-Address, Called-Station-Id, Calling-Station-Id, Acct-Session-Id, Acct-Multi-Session-Id, NAS-Port-Id, Chargeable-User-Identity, Framed-Interface-Id, Framed-IPv6-Prefix"},
+		{"AccessAccept", RADIUSCodeAccessAccept, nil, ""},
+	}
+
+	for _, tt := range tests {
+		radius := &RADIUS{Code: tt.code, Attributes: tt.attributes}
+		err := radius.ValidateRequiredAttributes()
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error: %v", tt.desc, err)
+			}
+			continue
+		}
+		if err == nil || err.Error() != tt.wantErr {
+			t.Errorf("%s: got %v want %s", tt.desc, err, tt.wantErr)
+		}
+	}
+
+	if err := decodeTestRADIUS(t, testRADIUSAccessRequest).ValidateRequiredAttributes(); err != nil {
+		t.Errorf("AccessRequest: unexpected error: %v", err)
+	}
+}
+
+func TestRADIUSDecodeDuplicateWarnings(t *testing.T) {
+	if radius := decodeTestRADIUS(t, testRADIUSAccessRequest); radius.Warnings != nil {
+		t.Errorf("AccessRequest: unexpected warnings %q", radius.Warnings)
+	}
+
+	data, err := NewRADIUS(RADIUSCodeAccessRequest, 1).
+		AddRaw(RADIUSAttributeTypeUserName, []byte("Admin")).
+		AddRaw(RADIUSAttributeTypeProxyState, []byte("1")).
+		AddRaw(RADIUSAttributeTypeUserName, []byte("root")).
+		AddRaw(RADIUSAttributeTypeProxyState, []byte("2")).
+		AddRaw(RADIUSAttributeTypeUserName, []byte("guest")).
+		Build().serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	radius := decodeTestRADIUS(t, data)
+	if want := []string{"RADIUS User-Name appears more than once"}; !reflect.DeepEqual(radius.Warnings, want) {
+		t.Errorf("got %q want %q", radius.Warnings, want)
+	}
+	if len(radius.Attributes) != 5 {
+		t.Errorf("got %d attributes want 5", len(radius.Attributes))
+	}
+}
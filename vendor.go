@@ -0,0 +1,291 @@
+package radius
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// constants that define SMI Network Management Private Enterprise Codes.
+const (
+	RADIUSVendorIDCisco     uint32 = 9     // Cisco Systems
+	RADIUSVendorIDMicrosoft uint32 = 311   // Microsoft
+	RADIUSVendorIDWiMAX     uint32 = 24757 // WiMAX Forum
+)
+
+// constants that define Cisco vendor attribute types.
+const (
+	RADIUSCiscoAttributeTypeAVPair uint8 = 1 // Cisco-AVPair
+)
+
+// constants that define Microsoft vendor attribute types.
+const (
+	RADIUSMicrosoftAttributeTypeMPPESendKey uint8 = 16 // RFC2548 2.4.2. MS-MPPE-Send-Key
+	RADIUSMicrosoftAttributeTypeMPPERecvKey uint8 = 17 // RFC2548 2.4.3. MS-MPPE-Recv-Key
+)
+
+// VendorName returns the name of a vendor ID.
+func VendorName(vendorID uint32) (s string) {
+	switch vendorID {
+	case RADIUSVendorIDCisco:
+		s = "Cisco"
+	case RADIUSVendorIDMicrosoft:
+		s = "Microsoft"
+	case RADIUSVendorIDWiMAX:
+		s = "WiMAX"
+	default:
+		s = fmt.Sprintf("Unknown(%d)", vendorID)
+	}
+	return
+}
+
+// VendorAttributeName returns the name of a vendor attribute type.
+func VendorAttributeName(vendorID uint32, t uint8) (s string) {
+	switch {
+	case vendorID == RADIUSVendorIDCisco && t == RADIUSCiscoAttributeTypeAVPair:
+		s = "Cisco-AVPair"
+	case vendorID == RADIUSVendorIDMicrosoft && t == RADIUSMicrosoftAttributeTypeMPPESendKey:
+		s = "MS-MPPE-Send-Key"
+	case vendorID == RADIUSVendorIDMicrosoft && t == RADIUSMicrosoftAttributeTypeMPPERecvKey:
+		s = "MS-MPPE-Recv-Key"
+	case vendorID == RADIUSVendorIDWiMAX:
+		s = wimaxAttributeName(t)
+	default:
+		s = fmt.Sprintf("%s-Unknown(%d)", VendorName(vendorID), t)
+	}
+	return
+}
+
+// VendorAttributeValueType returns the data type of the value carried by a
+// vendor attribute type. Unknown vendor attribute types are reported as
+// AttrValueTypeOctets.
+func VendorAttributeValueType(vendorID uint32, t uint8) (v AttrValueType) {
+	switch {
+	case vendorID == RADIUSVendorIDCisco && t == RADIUSCiscoAttributeTypeAVPair:
+		v = AttrValueTypeString
+	case vendorID == RADIUSVendorIDWiMAX:
+		v = wimaxAttributeValueType(t)
+	default:
+		v = AttrValueTypeOctets
+	}
+	return
+}
+
+// RADIUSVendorSpecific represents a Vendor-Specific attribute.
+type RADIUSVendorSpecific struct {
+	VendorID   uint32
+	Attributes []RADIUSVendorAttribute
+}
+
+// RADIUSVendorAttribute represents a sub-attribute of a Vendor-Specific attribute.
+type RADIUSVendorAttribute struct {
+	Type   uint8
+	Length uint8
+	Value  RADIUSAttributeValue
+}
+
+// VendorSpecific decodes a Vendor-Specific attribute in the RFC2865 5.26
+// format, a 4 bytes vendor ID followed by one or more Type/Length/Value
+// sub-attributes. The WiMAX format is recognized by its vendor ID: its
+// single sub-attribute is decoded without the continuation byte, see
+// WiMAXAttribute for the continuation flag.
+func (a RADIUSAttribute) VendorSpecific() (*RADIUSVendorSpecific, error) {
+	if a.Type != RADIUSAttributeTypeVendorSpecific {
+		return nil, fmt.Errorf("RADIUS attribute %s is not %s", a.Type, RADIUSAttributeTypeVendorSpecific)
+	}
+	if len(a.Value) < 4 {
+		return nil, fmt.Errorf("RADIUS %s length %d too short", a.Type, len(a.Value))
+	}
+
+	vsa := &RADIUSVendorSpecific{
+		VendorID: binary.BigEndian.Uint32(a.Value[0:4]),
+	}
+	if vsa.VendorID == RADIUSVendorIDWiMAX {
+		w, err := a.WiMAXAttribute()
+		if err != nil {
+			return nil, err
+		}
+		vsa.Attributes = []RADIUSVendorAttribute{{Type: w.Type, Length: w.Length, Value: w.Value}}
+		return vsa, nil
+	}
+	data := a.Value[4:]
+	for len(data) > 0 {
+		if len(data) < 2 {
+			return nil, fmt.Errorf("RADIUS %s sub-attribute header truncated", a.Type)
+		}
+		n := int(data[1])
+		if n < 2 || n > len(data) {
+			return nil, fmt.Errorf("RADIUS %s sub-attribute length %d invalid", a.Type, n)
+		}
+		vsa.Attributes = append(vsa.Attributes, RADIUSVendorAttribute{
+			Type:   data[0],
+			Length: data[1],
+			Value:  RADIUSAttributeValue(data[2:n]),
+		})
+		data = data[n:]
+	}
+	return vsa, nil
+}
+
+// GetVendorAttribute returns the value of the first sub-attribute of type
+// vendorType of the vendor across all Vendor-Specific attributes, e.g. a
+// Cisco-AVPair, skipping the Vendor-Specific attributes of other vendors and
+// those which are malformed. The sub-attributes are decoded in the RFC2865
+// format, see VendorSpecific.
+func (radius *RADIUS) GetVendorAttribute(vendorID uint32, vendorType byte) (RADIUSAttributeValue, bool) {
+	for _, v := range radius.Attributes {
+		if v.Type != RADIUSAttributeTypeVendorSpecific {
+			continue
+		}
+		vsa, err := v.VendorSpecific()
+		if err != nil || vsa.VendorID != vendorID {
+			continue
+		}
+		for _, sub := range vsa.Attributes {
+			if sub.Type == vendorType {
+				return sub.Value, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// WalkAttributes calls fn for each attribute in packet order, with a zero
+// vendorID, and after each Vendor-Specific attribute for each of its
+// sub-attributes, as decoded by VendorSpecific, with the vendor ID of the
+// Vendor-Specific attribute. A Vendor-Specific attribute that does not
+// decode, such as one in a vendor format other than RFC2865 5.26, is visited
+// as an attribute only and the walk goes on. It stops at the first error
+// returned by fn and returns it.
+func (radius *RADIUS) WalkAttributes(fn func(attr RADIUSAttribute, vendorID uint32) error) error {
+	for _, v := range radius.Attributes {
+		if err := fn(v, 0); err != nil {
+			return err
+		}
+		if v.Type != RADIUSAttributeTypeVendorSpecific {
+			continue
+		}
+		vsa, err := v.VendorSpecific()
+		if err != nil {
+			continue
+		}
+		for _, sub := range vsa.Attributes {
+			attr := RADIUSAttribute{Type: RADIUSAttributeType(sub.Type), Length: RADIUSAttributeLength(sub.Length), Value: sub.Value}
+			if err := fn(attr, vsa.VendorID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// SerializeVendorSpecific encodes vsa as the value of a Vendor-Specific
+// attribute, the reverse of VendorSpecific. The sub-attribute lengths are
+// computed from their values. A WiMAX Vendor-Specific attribute carries a
+// single sub-attribute, encoded with a clear continuation byte.
+func SerializeVendorSpecific(vsa *RADIUSVendorSpecific) (RADIUSAttributeValue, error) {
+	value := make(RADIUSAttributeValue, 4, radiusMaximumAttributeValueSizeInBytes)
+	binary.BigEndian.PutUint32(value, vsa.VendorID)
+	if vsa.VendorID == RADIUSVendorIDWiMAX {
+		if len(vsa.Attributes) != 1 {
+			return nil, fmt.Errorf("RADIUS WiMAX %s carries %d sub-attributes, want 1", RADIUSAttributeTypeVendorSpecific, len(vsa.Attributes))
+		}
+		attr := vsa.Attributes[0]
+		if len(value)+3+len(attr.Value) > radiusMaximumAttributeValueSizeInBytes {
+			return nil, fmt.Errorf("RADIUS %s length %d exceeds %d", RADIUSAttributeTypeVendorSpecific, len(value)+3+len(attr.Value), radiusMaximumAttributeValueSizeInBytes)
+		}
+		value = append(value, attr.Type, byte(len(attr.Value)+3), 0x00)
+		return append(value, attr.Value...), nil
+	}
+	for _, attr := range vsa.Attributes {
+		if len(attr.Value)+2 > 255 {
+			return nil, fmt.Errorf("RADIUS %s sub-attribute %d length %d too long", RADIUSAttributeTypeVendorSpecific, attr.Type, len(attr.Value))
+		}
+		value = append(value, attr.Type, byte(len(attr.Value)+2))
+		value = append(value, attr.Value...)
+	}
+	if len(value) > radiusMaximumAttributeValueSizeInBytes {
+		return nil, fmt.Errorf("RADIUS %s length %d exceeds %d", RADIUSAttributeTypeVendorSpecific, len(value), radiusMaximumAttributeValueSizeInBytes)
+	}
+	return value, nil
+}
+
+// AddVendorSpecific appends a Vendor-Specific attribute carrying a single
+// sub-attribute of the vendor, e.g. a Cisco-AVPair. It returns an error if
+// the attribute would exceed 253 bytes.
+func (b *RADIUSBuilder) AddVendorSpecific(vendorID uint32, vendorType uint8, value []byte) error {
+	v, err := SerializeVendorSpecific(&RADIUSVendorSpecific{
+		VendorID:   vendorID,
+		Attributes: []RADIUSVendorAttribute{{Type: vendorType, Value: value}},
+	})
+	if err != nil {
+		return err
+	}
+	b.AddRaw(RADIUSAttributeTypeVendorSpecific, v)
+	return nil
+}
+
+// AddVendorAttributes appends the sub-attributes of a vendor packed into as
+// few Vendor-Specific attributes as possible: each holds as many consecutive
+// sub-attributes as fit in 253 bytes. The Length of the sub-attributes is
+// computed from their values. It returns an error, adding nothing, if a
+// sub-attribute does not fit in a Vendor-Specific attribute on its own.
+func (b *RADIUSBuilder) AddVendorAttributes(vendorID uint32, subs []RADIUSVendorAttribute) error {
+	var values []RADIUSAttributeValue
+	vsa := &RADIUSVendorSpecific{VendorID: vendorID}
+	size := 4
+	for _, sub := range subs {
+		if n := len(sub.Value) + 2; size+n > radiusMaximumAttributeValueSizeInBytes && len(vsa.Attributes) > 0 {
+			v, err := SerializeVendorSpecific(vsa)
+			if err != nil {
+				return err
+			}
+			values = append(values, v)
+			vsa = &RADIUSVendorSpecific{VendorID: vendorID}
+			size = 4
+		}
+		vsa.Attributes = append(vsa.Attributes, sub)
+		size += len(sub.Value) + 2
+	}
+	if len(vsa.Attributes) > 0 {
+		v, err := SerializeVendorSpecific(vsa)
+		if err != nil {
+			return err
+		}
+		values = append(values, v)
+	}
+
+	for _, v := range values {
+		b.AddRaw(RADIUSAttributeTypeVendorSpecific, v)
+	}
+	return nil
+}
+
+// CiscoAVPairs returns the values of all Cisco-AVPair sub-attributes.
+func (v *RADIUSVendorSpecific) CiscoAVPairs() []string {
+	if v.VendorID != RADIUSVendorIDCisco {
+		return nil
+	}
+	var pairs []string
+	for _, attr := range v.Attributes {
+		if attr.Type == RADIUSCiscoAttributeTypeAVPair {
+			pairs = append(pairs, string(attr.Value))
+		}
+	}
+	return pairs
+}
+
+// ParseCiscoAVPair splits a Cisco-AVPair in the "protocol:attribute=value"
+// format. The protocol is optional, and optional attributes use "*" instead
+// of "=" as the separator.
+func ParseCiscoAVPair(s string) (protocol, attribute, value string) {
+	if i := strings.IndexAny(s, "=*"); i >= 0 {
+		attribute, value = s[:i], s[i+1:]
+	} else {
+		attribute = s
+	}
+	if i := strings.Index(attribute, ":"); i >= 0 {
+		protocol, attribute = attribute[:i], attribute[i+1:]
+	}
+	return
+}
@@ -0,0 +1,289 @@
+package radius
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// testCiscoAVPairVSA is a Vendor-Specific attribute carrying two Cisco-AVPairs.
+var testCiscoAVPairVSA = RADIUSAttribute{
+	Type:   RADIUSAttributeTypeVendorSpecific,
+	Length: 44,
+	Value: RADIUSAttributeValue("\x00\x00\x00\x09" +
+		"\x01\x13shell:priv-lvl=15" +
+		"\x01\x13ip:inacl#1=permit"),
+}
+
+func TestRADIUSAttributeVendorSpecific(t *testing.T) {
+	vsa, err := testCiscoAVPairVSA.VendorSpecific()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &RADIUSVendorSpecific{
+		VendorID: RADIUSVendorIDCisco,
+		Attributes: []RADIUSVendorAttribute{
+			{Type: 1, Length: 0x13, Value: RADIUSAttributeValue("shell:priv-lvl=15")},
+			{Type: 1, Length: 0x13, Value: RADIUSAttributeValue("ip:inacl#1=permit")},
+		},
+	}
+	if !reflect.DeepEqual(vsa, want) {
+		t.Errorf("got %#v want %#v", vsa, want)
+	}
+
+	for _, value := range []string{
+		"\x00\x00\x09",
+		"\x00\x00\x00\x09\x01",
+		"\x00\x00\x00\x09\x01\x01",
+		"\x00\x00\x00\x09\x01\x13shell",
+	} {
+		attr := newAttribute(RADIUSAttributeTypeVendorSpecific, []byte(value))
+		if _, err := attr.VendorSpecific(); err == nil {
+			t.Errorf("%x: expected error", value)
+		}
+	}
+}
+
+func TestRADIUSGetVendorAttribute(t *testing.T) {
+	radius := &RADIUS{Attributes: []RADIUSAttribute{
+		newAttribute(RADIUSAttributeTypeUserName, []byte("Admin")),
+		newAttribute(RADIUSAttributeTypeVendorSpecific, []byte("\x00\x00\x00\x09\x01\x13shell")),
+		newAttribute(RADIUSAttributeTypeVendorSpecific, []byte("\x00\x00\x01\x37\x01\x06\x00\x00\x00\x01")),
+		testCiscoAVPairVSA,
+	}}
+
+	value, ok := radius.GetVendorAttribute(RADIUSVendorIDCisco, RADIUSCiscoAttributeTypeAVPair)
+	if !ok || string(value) != "shell:priv-lvl=15" {
+		t.Errorf("Cisco-AVPair got %q, %v want %q", value, ok, "shell:priv-lvl=15")
+	}
+	value, ok = radius.GetVendorAttribute(RADIUSVendorIDMicrosoft, 1)
+	if !ok || string(value) != "\x00\x00\x00\x01" {
+		t.Errorf("Microsoft 1 got %x, %v want 00000001", value, ok)
+	}
+	for _, tt := range []struct {
+		vendorID   uint32
+		vendorType byte
+	}{
+		{RADIUSVendorIDCisco, 2},
+		{RADIUSVendorIDMicrosoft, 2},
+		{RADIUSVendorIDWiMAX, 1},
+	} {
+		if value, ok := radius.GetVendorAttribute(tt.vendorID, tt.vendorType); ok {
+			t.Errorf("%s: got %q", VendorAttributeName(tt.vendorID, tt.vendorType), value)
+		}
+	}
+}
+
+func TestRADIUSWalkAttributes(t *testing.T) {
+	radius := &RADIUS{Attributes: []RADIUSAttribute{
+		newAttribute(RADIUSAttributeTypeUserName, []byte("Admin")),
+		testCiscoAVPairVSA,
+	}}
+
+	type visit struct {
+		t        RADIUSAttributeType
+		vendorID uint32
+		value    string
+	}
+	var got []visit
+	err := radius.WalkAttributes(func(attr RADIUSAttribute, vendorID uint32) error {
+		got = append(got, visit{attr.Type, vendorID, string(attr.Value)})
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []visit{
+		{RADIUSAttributeTypeUserName, 0, "Admin"},
+		{RADIUSAttributeTypeVendorSpecific, 0, string(testCiscoAVPairVSA.Value)},
+		{RADIUSAttributeType(RADIUSCiscoAttributeTypeAVPair), RADIUSVendorIDCisco, "shell:priv-lvl=15"},
+		{RADIUSAttributeType(RADIUSCiscoAttributeTypeAVPair), RADIUSVendorIDCisco, "ip:inacl#1=permit"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+
+	// an error stops the walk
+	stop := fmt.Errorf("stop")
+	n := 0
+	err = radius.WalkAttributes(func(attr RADIUSAttribute, vendorID uint32) error {
+		n++
+		if vendorID != 0 {
+			return stop
+		}
+		return nil
+	})
+	if err != stop || n != 3 {
+		t.Errorf("got %v after %d calls want %v after 3", err, n, stop)
+	}
+
+	// a malformed Vendor-Specific is visited without its sub-attributes
+	malformed := newAttribute(RADIUSAttributeTypeVendorSpecific, []byte{0x00, 0x00})
+	radius.Attributes = []RADIUSAttribute{malformed, testCiscoAVPairVSA}
+	got = nil
+	err = radius.WalkAttributes(func(attr RADIUSAttribute, vendorID uint32) error {
+		got = append(got, visit{attr.Type, vendorID, string(attr.Value)})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("malformed Vendor-Specific: unexpected error: %v", err)
+	}
+	want = append([]visit{{RADIUSAttributeTypeVendorSpecific, 0, string(malformed.Value)}}, want[1:]...)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("malformed Vendor-Specific: got %v want %v", got, want)
+	}
+}
+
+func TestSerializeVendorSpecific(t *testing.T) {
+	vsa, err := testCiscoAVPairVSA.VendorSpecific()
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := SerializeVendorSpecific(vsa)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(value, testCiscoAVPairVSA.Value) {
+		t.Errorf("got %q want %q", value, testCiscoAVPairVSA.Value)
+	}
+
+	// lengths are computed from the values
+	value, err = SerializeVendorSpecific(&RADIUSVendorSpecific{
+		VendorID:   RADIUSVendorIDCisco,
+		Attributes: []RADIUSVendorAttribute{{Type: RADIUSCiscoAttributeTypeAVPair, Value: RADIUSAttributeValue("shell:priv-lvl=15")}},
+	})
+	if err != nil || string(value) != "\x00\x00\x00\x09\x01\x13shell:priv-lvl=15" {
+		t.Errorf("got %q, %v", value, err)
+	}
+
+	for _, attrs := range [][]RADIUSVendorAttribute{
+		{{Type: 1, Value: make(RADIUSAttributeValue, 254)}},
+		{{Type: 1, Value: make(RADIUSAttributeValue, 200)}, {Type: 2, Value: make(RADIUSAttributeValue, 100)}},
+	} {
+		if _, err := SerializeVendorSpecific(&RADIUSVendorSpecific{VendorID: 9, Attributes: attrs}); err == nil {
+			t.Errorf("%d sub-attributes: expected error", len(attrs))
+		}
+	}
+}
+
+func TestRADIUSBuilderAddVendorSpecific(t *testing.T) {
+	b := NewRADIUS(RADIUSCodeAccessAccept, 0x01)
+	if err := b.AddVendorSpecific(RADIUSVendorIDCisco, RADIUSCiscoAttributeTypeAVPair, []byte("shell:priv-lvl=15")); err != nil {
+		t.Fatal(err)
+	}
+	data, err := b.Build().serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	radius := decodeTestRADIUS(t, data)
+
+	attr, ok := radius.GetAttribute(RADIUSAttributeTypeVendorSpecific)
+	if !ok {
+		t.Fatal("Vendor-Specific missing")
+	}
+	vsa, err := attr.VendorSpecific()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := vsa.CiscoAVPairs(), []string{"shell:priv-lvl=15"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q want %q", got, want)
+	}
+	if vsa.Attributes[0].Length != 19 {
+		t.Errorf("sub-attribute length got %d want 19", vsa.Attributes[0].Length)
+	}
+
+	// 4 bytes vendor ID, 2 bytes header and 247 bytes value
+	if err := b.AddVendorSpecific(RADIUSVendorIDCisco, 1, make([]byte, 247)); err != nil {
+		t.Errorf("253 bytes: unexpected error: %v", err)
+	}
+	if err := b.AddVendorSpecific(RADIUSVendorIDCisco, 1, make([]byte, 248)); err == nil {
+		t.Error("254 bytes: expected error")
+	}
+	if n := len(b.Build().Attributes); n != 2 {
+		t.Errorf("got %d attributes want 2", n)
+	}
+}
+
+func TestRADIUSBuilderAddVendorAttributes(t *testing.T) {
+	var subs []RADIUSVendorAttribute
+	var want []string
+	for i := 0; i < 10; i++ {
+		pair := fmt.Sprintf("ip:inacl#%d=permit tcp any host 192.0.2.%d eq 443", i, i)
+		subs = append(subs, RADIUSVendorAttribute{Type: RADIUSCiscoAttributeTypeAVPair, Value: RADIUSAttributeValue(pair)})
+		want = append(want, pair)
+	}
+
+	b := NewRADIUS(RADIUSCodeAccessAccept, 0x01)
+	if err := b.AddVendorAttributes(RADIUSVendorIDCisco, subs); err != nil {
+		t.Fatal(err)
+	}
+	data, err := b.Build().serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	radius := decodeTestRADIUS(t, data)
+
+	// 4 bytes vendor ID and 5 sub-attributes of 49 bytes each
+	attrs := radius.GetAttributes(RADIUSAttributeTypeVendorSpecific)
+	if len(attrs) != 2 {
+		t.Fatalf("got %d Vendor-Specific attributes want 2", len(attrs))
+	}
+	var got []string
+	for _, attr := range attrs {
+		if len(attr.Value) > radiusMaximumAttributeValueSizeInBytes {
+			t.Errorf("Vendor-Specific length %d exceeds 253", len(attr.Value))
+		}
+		vsa, err := attr.VendorSpecific()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, vsa.CiscoAVPairs()...)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q want %q", got, want)
+	}
+
+	b = NewRADIUS(RADIUSCodeAccessAccept, 0x01)
+	err = b.AddVendorAttributes(RADIUSVendorIDCisco, []RADIUSVendorAttribute{
+		{Type: RADIUSCiscoAttributeTypeAVPair, Value: RADIUSAttributeValue("shell:priv-lvl=15")},
+		{Type: RADIUSCiscoAttributeTypeAVPair, Value: make(RADIUSAttributeValue, 248)},
+	})
+	if err == nil {
+		t.Error("sub-attribute of 248 bytes: expected error")
+	}
+	if n := len(b.Build().Attributes); n != 0 {
+		t.Errorf("got %d attributes after error want 0", n)
+	}
+}
+
+func TestRADIUSVendorSpecificCiscoAVPairs(t *testing.T) {
+	vsa, err := testCiscoAVPairVSA.VendorSpecific()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"shell:priv-lvl=15", "ip:inacl#1=permit"}
+	if got := vsa.CiscoAVPairs(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q want %q", got, want)
+	}
+}
+
+func TestParseCiscoAVPair(t *testing.T) {
+	tests := []struct {
+		s                          string
+		protocol, attribute, value string
+	}{
+		{"shell:priv-lvl=15", "shell", "priv-lvl", "15"},
+		{"ip:inacl#1=permit tcp any any eq 22", "ip", "inacl#1", "permit tcp any any eq 22"},
+		{"shell:roles*network-admin", "shell", "roles", "network-admin"},
+		{"audit-session-id=0a0a0a0a", "", "audit-session-id", "0a0a0a0a"},
+		{"url-redirect=http://portal/?a=b", "", "url-redirect", "http://portal/?a=b"},
+		{"priv-lvl", "", "priv-lvl", ""},
+	}
+
+	for _, tt := range tests {
+		protocol, attribute, value := ParseCiscoAVPair(tt.s)
+		if protocol != tt.protocol || attribute != tt.attribute || value != tt.value {
+			t.Errorf("%q: got %q, %q, %q want %q, %q, %q", tt.s, protocol, attribute, value, tt.protocol, tt.attribute, tt.value)
+		}
+	}
+}
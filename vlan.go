@@ -0,0 +1,53 @@
+package radius
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// constants that define the IEEE 802.1Q tag indicator of RFC4675 attributes.
+const (
+	radiusVLANTagged   byte = 0x31 // RFC4675 2.1.  Tag Indication, tagged frames
+	radiusVLANUntagged byte = 0x32 // RFC4675 2.1.  Tag Indication, untagged frames
+)
+
+// EgressVLANID decodes an Egress-VLANID attribute into its tag indicator and
+// 12 bit VLAN ID.
+func (a RADIUSAttribute) EgressVLANID() (tagged bool, vlanID uint16, err error) {
+	if a.Type != RADIUSAttributeTypeEgressVLANID {
+		return false, 0, fmt.Errorf("RADIUS attribute %s is not %s", a.Type, RADIUSAttributeTypeEgressVLANID)
+	}
+	if len(a.Value) != 4 {
+		return false, 0, fmt.Errorf("RADIUS %s length %d invalid", a.Type, len(a.Value))
+	}
+	if tagged, err = vlanTagIndication(a.Type, a.Value[0]); err != nil {
+		return false, 0, err
+	}
+	return tagged, uint16(binary.BigEndian.Uint32(a.Value) & 0x0fff), nil
+}
+
+// EgressVLANName decodes an Egress-VLAN-Name attribute into its tag indicator
+// and VLAN name.
+func (a RADIUSAttribute) EgressVLANName() (tagged bool, name string, err error) {
+	if a.Type != RADIUSAttributeTypeEgressVLANName {
+		return false, "", fmt.Errorf("RADIUS attribute %s is not %s", a.Type, RADIUSAttributeTypeEgressVLANName)
+	}
+	if len(a.Value) < 2 {
+		return false, "", fmt.Errorf("RADIUS %s length %d too short", a.Type, len(a.Value))
+	}
+	if tagged, err = vlanTagIndication(a.Type, a.Value[0]); err != nil {
+		return false, "", err
+	}
+	return tagged, string(a.Value[1:]), nil
+}
+
+func vlanTagIndication(t RADIUSAttributeType, b byte) (bool, error) {
+	switch b {
+	case radiusVLANTagged:
+		return true, nil
+	case radiusVLANUntagged:
+		return false, nil
+	default:
+		return false, fmt.Errorf("RADIUS %s tag indication 0x%02x invalid", t, b)
+	}
+}
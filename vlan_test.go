@@ -0,0 +1,75 @@
+package radius
+
+import (
+	"testing"
+)
+
+func TestRADIUSAttributeEgressVLANName(t *testing.T) {
+	tests := []struct {
+		desc    string
+		attr    RADIUSAttribute
+		tagged  bool
+		name    string
+		wantErr bool
+	}{
+		{
+			desc:   "Tagged",
+			attr:   RADIUSAttribute{Type: RADIUSAttributeTypeEgressVLANName, Length: 10, Value: RADIUSAttributeValue("1Guests")},
+			tagged: true,
+			name:   "Guests",
+		},
+		{
+			desc:   "Untagged",
+			attr:   RADIUSAttribute{Type: RADIUSAttributeTypeEgressVLANName, Length: 10, Value: RADIUSAttributeValue("2Staff")},
+			tagged: false,
+			name:   "Staff",
+		},
+		{
+			desc:    "InvalidIndicator",
+			attr:    RADIUSAttribute{Type: RADIUSAttributeTypeEgressVLANName, Length: 10, Value: RADIUSAttributeValue("3Staff")},
+			wantErr: true,
+		},
+		{
+			desc:    "Empty",
+			attr:    RADIUSAttribute{Type: RADIUSAttributeTypeEgressVLANName, Length: 3, Value: RADIUSAttributeValue("1")},
+			wantErr: true,
+		},
+		{
+			desc:    "WrongType",
+			attr:    RADIUSAttribute{Type: RADIUSAttributeTypeUserName, Length: 10, Value: RADIUSAttributeValue("1Guests")},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tagged, name, err := tt.attr.EgressVLANName()
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected error", tt.desc)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.desc, err)
+			continue
+		}
+		if tagged != tt.tagged || name != tt.name {
+			t.Errorf("%s: got %v, %q want %v, %q", tt.desc, tagged, name, tt.tagged, tt.name)
+		}
+	}
+}
+
+func TestRADIUSAttributeEgressVLANID(t *testing.T) {
+	attr := RADIUSAttribute{
+		Type:   RADIUSAttributeTypeEgressVLANID,
+		Length: 6,
+		Value:  RADIUSAttributeValue("\x31\x00\x00\x64"),
+	}
+	tagged, vlanID, err := attr.EgressVLANID()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tagged || vlanID != 100 {
+		t.Errorf("got %v, %d want true, 100", tagged, vlanID)
+	}
+}
@@ -0,0 +1,121 @@
+package radius
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// constants that define WiMAX vendor attribute types.
+const (
+	RADIUSWiMAXAttributeTypeCapability                    uint8 = 1 // WiMAX-Capability
+	RADIUSWiMAXAttributeTypeDeviceAuthenticationIndicator uint8 = 2 // WiMAX-Device-Authentication-Indicator
+	RADIUSWiMAXAttributeTypeGMTTimezoneOffset             uint8 = 3 // WiMAX-GMT-Timezone-offset
+	RADIUSWiMAXAttributeTypeAAASessionId                  uint8 = 4 // WiMAX-AAA-Session-Id
+	RADIUSWiMAXAttributeTypeMSK                           uint8 = 5 // WiMAX-MSK
+	RADIUSWiMAXAttributeTypeHHAIPMIP4                     uint8 = 6 // WiMAX-hHA-IP-MIP4
+	RADIUSWiMAXAttributeTypeHHAIPMIP6                     uint8 = 7 // WiMAX-hHA-IP-MIP6
+	RADIUSWiMAXAttributeTypeDHCPv4Server                  uint8 = 8 // WiMAX-DHCPv4-Server
+	RADIUSWiMAXAttributeTypeDHCPv6Server                  uint8 = 9 // WiMAX-DHCPv6-Server
+)
+
+// radiusWiMAXContinuation is the continuation flag of a WiMAX vendor attribute.
+const radiusWiMAXContinuation byte = 0x80
+
+func wimaxAttributeName(t uint8) (s string) {
+	switch t {
+	case RADIUSWiMAXAttributeTypeCapability:
+		s = "WiMAX-Capability"
+	case RADIUSWiMAXAttributeTypeDeviceAuthenticationIndicator:
+		s = "WiMAX-Device-Authentication-Indicator"
+	case RADIUSWiMAXAttributeTypeGMTTimezoneOffset:
+		s = "WiMAX-GMT-Timezone-offset"
+	case RADIUSWiMAXAttributeTypeAAASessionId:
+		s = "WiMAX-AAA-Session-Id"
+	case RADIUSWiMAXAttributeTypeMSK:
+		s = "WiMAX-MSK"
+	case RADIUSWiMAXAttributeTypeHHAIPMIP4:
+		s = "WiMAX-hHA-IP-MIP4"
+	case RADIUSWiMAXAttributeTypeHHAIPMIP6:
+		s = "WiMAX-hHA-IP-MIP6"
+	case RADIUSWiMAXAttributeTypeDHCPv4Server:
+		s = "WiMAX-DHCPv4-Server"
+	case RADIUSWiMAXAttributeTypeDHCPv6Server:
+		s = "WiMAX-DHCPv6-Server"
+	default:
+		s = fmt.Sprintf("WiMAX-Unknown(%d)", t)
+	}
+	return
+}
+
+func wimaxAttributeValueType(t uint8) (v AttrValueType) {
+	switch t {
+	case RADIUSWiMAXAttributeTypeGMTTimezoneOffset:
+		v = AttrValueTypeInteger
+	case RADIUSWiMAXAttributeTypeHHAIPMIP4,
+		RADIUSWiMAXAttributeTypeDHCPv4Server:
+		v = AttrValueTypeIPAddr
+	case RADIUSWiMAXAttributeTypeHHAIPMIP6,
+		RADIUSWiMAXAttributeTypeDHCPv6Server:
+		v = AttrValueTypeIPv6Addr
+	default:
+		v = AttrValueTypeOctets
+	}
+	return
+}
+
+// RADIUSWiMAXAttribute represents a WiMAX vendor attribute.
+type RADIUSWiMAXAttribute struct {
+	Type         uint8
+	Length       uint8
+	Continuation bool
+	Value        RADIUSAttributeValue
+}
+
+// WiMAXAttribute decodes a WiMAX Vendor-Specific attribute. Unlike RFC2865
+// 5.26 sub-attributes, the vendor length is followed by a continuation byte
+// whose high bit flags that the value continues in the next WiMAX attribute
+// of the same type, and the vendor length covers that byte.
+func (a RADIUSAttribute) WiMAXAttribute() (*RADIUSWiMAXAttribute, error) {
+	if a.Type != RADIUSAttributeTypeVendorSpecific {
+		return nil, fmt.Errorf("RADIUS attribute %s is not %s", a.Type, RADIUSAttributeTypeVendorSpecific)
+	}
+	if len(a.Value) < 7 {
+		return nil, fmt.Errorf("RADIUS WiMAX %s length %d too short", a.Type, len(a.Value))
+	}
+	if vendorID := binary.BigEndian.Uint32(a.Value[0:4]); vendorID != RADIUSVendorIDWiMAX {
+		return nil, fmt.Errorf("RADIUS %s vendor %s is not WiMAX", a.Type, VendorName(vendorID))
+	}
+	n := int(a.Value[5])
+	if n < 3 || n != len(a.Value)-4 {
+		return nil, fmt.Errorf("RADIUS WiMAX sub-attribute length %d invalid", n)
+	}
+	return &RADIUSWiMAXAttribute{
+		Type:         a.Value[4],
+		Length:       a.Value[5],
+		Continuation: a.Value[6]&radiusWiMAXContinuation != 0,
+		Value:        a.Value[7:],
+	}, nil
+}
+
+// WiMAXValue returns the value of the WiMAX vendor attribute of type
+// vendorType, reassembled from the fragments chained by the continuation
+// flag across the WiMAX Vendor-Specific attributes of that type, in the
+// order of the packet. It returns false if the attribute is absent, or if
+// its fragments end with the continuation flag set.
+func (radius *RADIUS) WiMAXValue(vendorType byte) ([]byte, bool) {
+	var value []byte
+	for _, v := range radius.Attributes {
+		if v.Type != RADIUSAttributeTypeVendorSpecific {
+			continue
+		}
+		w, err := v.WiMAXAttribute()
+		if err != nil || w.Type != vendorType {
+			continue
+		}
+		value = append(value, w.Value...)
+		if !w.Continuation {
+			return value, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,113 @@
+package radius
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRADIUSAttributeWiMAXAttribute(t *testing.T) {
+	attr := newAttribute(RADIUSAttributeTypeVendorSpecific, []byte("\x00\x00\x60\xb5\x04\x0b\x80session1"))
+	got, err := attr.WiMAXAttribute()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &RADIUSWiMAXAttribute{
+		Type:         RADIUSWiMAXAttributeTypeAAASessionId,
+		Length:       0x0b,
+		Continuation: true,
+		Value:        RADIUSAttributeValue("session1"),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v want %#v", got, want)
+	}
+	if s := VendorAttributeName(RADIUSVendorIDWiMAX, got.Type); s != "WiMAX-AAA-Session-Id" {
+		t.Errorf("got %q want %q", s, "WiMAX-AAA-Session-Id")
+	}
+	if v := VendorAttributeValueType(RADIUSVendorIDWiMAX, RADIUSWiMAXAttributeTypeHHAIPMIP4); v != AttrValueTypeIPAddr {
+		t.Errorf("got %v want %v", v, AttrValueTypeIPAddr)
+	}
+
+	for _, value := range []string{
+		"\x00\x00\x60\xb5\x04\x0b",             // truncated
+		"\x00\x00\x00\x09\x04\x0b\x00session1", // not WiMAX
+		"\x00\x00\x60\xb5\x04\x0c\x00session1", // length overruns
+		"\x00\x00\x60\xb5\x04\x02\x00session1", // length too short
+	} {
+		attr := newAttribute(RADIUSAttributeTypeVendorSpecific, []byte(value))
+		if _, err := attr.WiMAXAttribute(); err == nil {
+			t.Errorf("%x: expected error", value)
+		}
+	}
+}
+
+func TestRADIUSWiMAXValue(t *testing.T) {
+	fragment := func(vendorType uint8, continuation bool, value string) RADIUSAttribute {
+		flags := byte(0)
+		if continuation {
+			flags = radiusWiMAXContinuation
+		}
+		return newAttribute(RADIUSAttributeTypeVendorSpecific, append([]byte{0x00, 0x00, 0x60, 0xb5, vendorType, byte(len(value) + 3), flags}, value...))
+	}
+	msk := strings.Repeat("k", 240) + strings.Repeat("m", 40)
+
+	tests := []struct {
+		desc       string
+		attributes []RADIUSAttribute
+		want       string
+		wantOK     bool
+	}{
+		{"Fragmented", []RADIUSAttribute{
+			fragment(RADIUSWiMAXAttributeTypeMSK, true, msk[:240]),
+			newAttribute(RADIUSAttributeTypeUserName, []byte("Admin")),
+			testCiscoAVPairVSA,
+			fragment(RADIUSWiMAXAttributeTypeAAASessionId, false, "session1"),
+			fragment(RADIUSWiMAXAttributeTypeMSK, false, msk[240:]),
+		}, msk, true},
+		{"Single", []RADIUSAttribute{
+			fragment(RADIUSWiMAXAttributeTypeMSK, false, "short"),
+		}, "short", true},
+		{"Truncated", []RADIUSAttribute{
+			fragment(RADIUSWiMAXAttributeTypeMSK, true, msk[:240]),
+		}, "", false},
+		{"Absent", []RADIUSAttribute{
+			fragment(RADIUSWiMAXAttributeTypeAAASessionId, false, "session1"),
+		}, "", false},
+	}
+
+	for _, tt := range tests {
+		radius := &RADIUS{Attributes: tt.attributes}
+		got, ok := radius.WiMAXValue(RADIUSWiMAXAttributeTypeMSK)
+		if string(got) != tt.want || ok != tt.wantOK {
+			t.Errorf("%s: got %d bytes, %v want %d bytes, %v", tt.desc, len(got), ok, len(tt.want), tt.wantOK)
+		}
+	}
+}
+
+func TestRADIUSAttributeVendorSpecificWiMAX(t *testing.T) {
+	attr := newAttribute(RADIUSAttributeTypeVendorSpecific, []byte("\x00\x00\x60\xb5\x04\x0b\x80session1"))
+	vsa, err := attr.VendorSpecific()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &RADIUSVendorSpecific{
+		VendorID:   RADIUSVendorIDWiMAX,
+		Attributes: []RADIUSVendorAttribute{{Type: RADIUSWiMAXAttributeTypeAAASessionId, Length: 0x0b, Value: RADIUSAttributeValue("session1")}},
+	}
+	if !reflect.DeepEqual(vsa, want) {
+		t.Errorf("got %#v want %#v", vsa, want)
+	}
+	radius := &RADIUS{Attributes: []RADIUSAttribute{attr}}
+	if value, ok := radius.GetVendorAttribute(RADIUSVendorIDWiMAX, RADIUSWiMAXAttributeTypeAAASessionId); !ok || string(value) != "session1" {
+		t.Errorf("GetVendorAttribute got %q, %v", value, ok)
+	}
+
+	value, err := SerializeVendorSpecific(want)
+	if err != nil || string(value) != "\x00\x00\x60\xb5\x04\x0b\x00session1" {
+		t.Errorf("SerializeVendorSpecific got %q, %v", value, err)
+	}
+	want.Attributes = append(want.Attributes, want.Attributes[0])
+	if _, err := SerializeVendorSpecific(want); err == nil {
+		t.Error("two WiMAX sub-attributes: expected error")
+	}
+}